@@ -0,0 +1,127 @@
+package availability
+
+import (
+	"sort"
+	"time"
+
+	// Sahaların saat dilimleri sunucuda zoneinfo olmasa da çözülebilsin
+	_ "time/tzdata"
+)
+
+const day = 24 * time.Hour
+
+// Window bir hafta gününde sahanın açık olduğu aralıktır. Opens ve Closes gün başından itibaren süredir,
+// gece yarısına kadar açık bir saha için Closes 24 saattir.
+type Window struct {
+	Weekday time.Weekday
+	Opens   time.Duration
+	Closes  time.Duration
+}
+
+// Slot rezerve edilebilir veya dolu bir zaman aralığıdır, bitiş dahil değildir
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (s Slot) Overlaps(o Slot) bool {
+	return s.Start.Before(o.End) && o.Start.Before(s.End)
+}
+
+// Schedule bir sahanın haftalık çalışma düzenidir. Hiç açılış saati tanımlanmamış saha her gün 24 saat açık sayılır.
+type Schedule struct {
+	Location   *time.Location
+	SlotLength time.Duration
+	Windows    []Window
+}
+
+// windows gün için açılış aralıklarını başlangıca göre sıralı döner
+func (s Schedule) windows(weekday time.Weekday) []Window {
+	if len(s.Windows) == 0 {
+		return []Window{{Weekday: weekday, Opens: 0, Closes: day}}
+	}
+
+	var result []Window
+	for _, w := range s.Windows {
+		if w.Weekday == weekday {
+			result = append(result, w)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Opens < result[j].Opens })
+	return result
+}
+
+func (s Schedule) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+// clock günün saat dilimindeki duvar saatini döner. Saat dilimi değişen günlerde gün 23 veya 25 saat
+// sürdüğü için gün başına süre eklemek saati kaydırır; 24 saat ertesi günün başıdır.
+func clock(midnight time.Time, offset time.Duration) time.Time {
+	y, m, d := midnight.Date()
+	return time.Date(y, m, d, int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, midnight.Location())
+}
+
+// Contains aralığın tamamı sahanın tek bir açılış aralığına sığıyorsa true döner
+func (s Schedule) Contains(start, end time.Time) bool {
+	loc := s.location()
+	local := start.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	for _, w := range s.windows(local.Weekday()) {
+		opens := clock(midnight, w.Opens)
+		closes := clock(midnight, w.Closes)
+		if !start.Before(opens) && !end.After(closes) {
+			return true
+		}
+	}
+	return false
+}
+
+// Slots [from, to) aralığındaki bütün aday slotları döner. Slotlar her açılış aralığının başından SlotLength
+// adımlarla kesilir, aralığa sığmayan son parça slot sayılmaz.
+func (s Schedule) Slots(from, to time.Time) []Slot {
+	if s.SlotLength <= 0 || !from.Before(to) {
+		return nil
+	}
+
+	loc := s.location()
+	local := from.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	var slots []Slot
+	for ; midnight.Before(to); midnight = midnight.AddDate(0, 0, 1) {
+		for _, w := range s.windows(midnight.Weekday()) {
+			closes := clock(midnight, w.Closes)
+			for start := clock(midnight, w.Opens); !start.Add(s.SlotLength).After(closes); start = start.Add(s.SlotLength) {
+				slot := Slot{Start: start, End: start.Add(s.SlotLength)}
+				if slot.Start.Before(from) || slot.End.After(to) {
+					continue
+				}
+				slots = append(slots, slot)
+			}
+		}
+	}
+	return slots
+}
+
+// Free aday slotlardan dolu aralıklarla (oyunlar, bakım/kapalı dönemler) çakışmayanları döner
+func Free(s Schedule, from, to time.Time, busy []Slot) []Slot {
+	var free []Slot
+	for _, slot := range s.Slots(from, to) {
+		taken := false
+		for _, b := range busy {
+			if slot.Overlaps(b) {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			free = append(free, slot)
+		}
+	}
+	return free
+}
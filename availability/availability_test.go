@@ -0,0 +1,213 @@
+package availability
+
+import (
+	"testing"
+	"time"
+)
+
+// saturday 2 Mayıs 2026 Cumartesi günün başıdır
+var saturday = time.Date(2026, time.May, 2, 0, 0, 0, 0, time.UTC)
+
+func at(day time.Time, hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+func slot(day time.Time, fromHour, fromMinute, toHour, toMinute int) Slot {
+	return Slot{Start: at(day, fromHour, fromMinute), End: at(day, toHour, toMinute)}
+}
+
+func assertSlots(t *testing.T, got, want []Slot) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d slots %v, want %d slots %v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Errorf("slot %d: got %s-%s, want %s-%s", i+1, got[i].Start, got[i].End, want[i].Start, want[i].End)
+		}
+	}
+}
+
+func TestSlots(t *testing.T) {
+	sunday := saturday.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		from, to time.Time
+		want     []Slot
+	}{
+		{
+			name: "açılış aralığı slot uzunluğunda kesilir, sığmayan parça atlanır",
+			schedule: Schedule{SlotLength: time.Hour, Windows: []Window{
+				{Weekday: time.Saturday, Opens: 18 * time.Hour, Closes: 20*time.Hour + 30*time.Minute},
+			}},
+			from: saturday,
+			to:   sunday,
+			want: []Slot{slot(saturday, 18, 0, 19, 0), slot(saturday, 19, 0, 20, 0)},
+		},
+		{
+			name: "24:00'te kapanan aralığın son slotu gece yarısında biter",
+			schedule: Schedule{SlotLength: 90 * time.Minute, Windows: []Window{
+				{Weekday: time.Saturday, Opens: 21 * time.Hour, Closes: 24 * time.Hour},
+			}},
+			from: saturday,
+			to:   sunday.AddDate(0, 0, 1),
+			want: []Slot{
+				slot(saturday, 21, 0, 22, 30),
+				{Start: at(saturday, 22, 30), End: sunday},
+			},
+		},
+		{
+			name: "bir günde birden fazla açılış aralığı sırayla döner",
+			schedule: Schedule{SlotLength: time.Hour, Windows: []Window{
+				{Weekday: time.Saturday, Opens: 20 * time.Hour, Closes: 21 * time.Hour},
+				{Weekday: time.Saturday, Opens: 9 * time.Hour, Closes: 10 * time.Hour},
+				{Weekday: time.Sunday, Opens: 9 * time.Hour, Closes: 10 * time.Hour},
+			}},
+			from: saturday,
+			to:   sunday.AddDate(0, 0, 1),
+			want: []Slot{
+				slot(saturday, 9, 0, 10, 0),
+				slot(saturday, 20, 0, 21, 0),
+				slot(sunday, 9, 0, 10, 0),
+			},
+		},
+		{
+			name: "istenen aralığa tam sığmayan slotlar dönmez",
+			schedule: Schedule{SlotLength: time.Hour, Windows: []Window{
+				{Weekday: time.Saturday, Opens: 18 * time.Hour, Closes: 22 * time.Hour},
+			}},
+			from: at(saturday, 18, 30),
+			to:   at(saturday, 21, 30),
+			want: []Slot{slot(saturday, 19, 0, 20, 0), slot(saturday, 20, 0, 21, 0)},
+		},
+		{
+			name:     "açılış saati tanımlanmamış saha gün boyu açıktır",
+			schedule: Schedule{SlotLength: 6 * time.Hour},
+			from:     saturday,
+			to:       sunday,
+			want: []Slot{
+				slot(saturday, 0, 0, 6, 0),
+				slot(saturday, 6, 0, 12, 0),
+				slot(saturday, 12, 0, 18, 0),
+				{Start: at(saturday, 18, 0), End: sunday},
+			},
+		},
+		{
+			name:     "slot uzunluğu yoksa slot yoktur",
+			schedule: Schedule{},
+			from:     saturday,
+			to:       sunday,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSlots(t, tt.schedule.Slots(tt.from, tt.to), tt.want)
+		})
+	}
+}
+
+func TestSlotsKeepWallClockOnDSTDays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schedule := Schedule{Location: loc, SlotLength: time.Hour, Windows: []Window{
+		{Weekday: time.Sunday, Opens: 18 * time.Hour, Closes: 24 * time.Hour},
+	}}
+
+	for _, day := range []time.Time{
+		time.Date(2026, time.March, 29, 0, 0, 0, 0, loc),
+		time.Date(2026, time.October, 25, 0, 0, 0, 0, loc),
+	} {
+		slots := schedule.Slots(day, day.AddDate(0, 0, 1))
+		if len(slots) != 6 {
+			t.Fatalf("%s: got %d slots, want 6", day.Format("2006-01-02"), len(slots))
+		}
+		if first := slots[0].Start.In(loc); first.Hour() != 18 || first.Minute() != 0 {
+			t.Errorf("%s: first slot starts at %s, want 18:00", day.Format("2006-01-02"), first)
+		}
+		if last := slots[len(slots)-1].End; !last.Equal(day.AddDate(0, 0, 1)) {
+			t.Errorf("%s: last slot ends at %s, want midnight", day.Format("2006-01-02"), last)
+		}
+		if !schedule.Contains(at(day, 23, 0), day.AddDate(0, 0, 1)) {
+			t.Errorf("%s: 23:00-24:00 should be open", day.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	schedule := Schedule{Windows: []Window{
+		{Weekday: time.Saturday, Opens: 9 * time.Hour, Closes: 12 * time.Hour},
+		{Weekday: time.Saturday, Opens: 18 * time.Hour, Closes: 24 * time.Hour},
+	}}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{name: "aralığın içinde", start: at(saturday, 9, 0), end: at(saturday, 12, 0), want: true},
+		{name: "gece yarısına kadar", start: at(saturday, 22, 30), end: saturday.AddDate(0, 0, 1), want: true},
+		{name: "kapanıştan sonra biter", start: at(saturday, 11, 0), end: at(saturday, 12, 30), want: false},
+		{name: "iki aralığa yayılır", start: at(saturday, 11, 0), end: at(saturday, 19, 0), want: false},
+		{name: "gece yarısını geçer", start: at(saturday, 23, 0), end: at(saturday.AddDate(0, 0, 1), 0, 30), want: false},
+		{name: "açılış aralığı olmayan gün", start: at(saturday.AddDate(0, 0, 1), 9, 0), end: at(saturday.AddDate(0, 0, 1), 10, 0), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.Contains(tt.start, tt.end); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFree(t *testing.T) {
+	schedule := Schedule{SlotLength: time.Hour, Windows: []Window{
+		{Weekday: time.Saturday, Opens: 18 * time.Hour, Closes: 22 * time.Hour},
+	}}
+	all := []Slot{
+		slot(saturday, 18, 0, 19, 0),
+		slot(saturday, 19, 0, 20, 0),
+		slot(saturday, 20, 0, 21, 0),
+		slot(saturday, 21, 0, 22, 0),
+	}
+
+	tests := []struct {
+		name string
+		busy []Slot
+		want []Slot
+	}{
+		{name: "dolu aralık yok", want: all},
+		{
+			name: "oyunla çakışan slotlar düşer",
+			busy: []Slot{slot(saturday, 18, 30, 20, 0)},
+			want: []Slot{all[2], all[3]},
+		},
+		{
+			name: "bitişik dolu aralık slotu kapatmaz",
+			busy: []Slot{slot(saturday, 17, 0, 18, 0), slot(saturday, 22, 0, 23, 0)},
+			want: all,
+		},
+		{
+			name: "günü kapsayan kapalı dönem bütün slotları kapatır",
+			busy: []Slot{{Start: saturday.Add(-time.Hour), End: saturday.AddDate(0, 0, 1)}},
+		},
+		{
+			name: "oyunlar ve kapalı dönemler birlikte düşülür",
+			busy: []Slot{slot(saturday, 18, 0, 19, 0), slot(saturday, 20, 30, 21, 15)},
+			want: []Slot{all[1]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSlots(t, Free(schedule, saturday, saturday.AddDate(0, 0, 1), tt.busy), tt.want)
+		})
+	}
+}
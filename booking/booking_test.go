@@ -0,0 +1,233 @@
+package booking
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// saturday 2 Mayıs 2026 Cumartesi günün başıdır
+var saturday = time.Date(2026, time.May, 2, 0, 0, 0, 0, time.UTC)
+
+func at(day time.Time, hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+func TestPrice(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	evening := PeakWindow{Weekdays: []time.Weekday{time.Saturday}, From: 18 * time.Hour, To: 20 * time.Hour, Multiplier: 1.5}
+	prime := PeakWindow{Weekdays: []time.Weekday{time.Saturday}, From: 19 * time.Hour, To: 21 * time.Hour, Multiplier: 2}
+	lateNight := PeakWindow{Weekdays: []time.Weekday{time.Saturday}, From: 22 * time.Hour, To: 24 * time.Hour, Multiplier: 2}
+	everyDay := PeakWindow{From: 18 * time.Hour, To: 20 * time.Hour, Multiplier: 2}
+
+	tests := []struct {
+		name       string
+		pricing    Pricing
+		start, end time.Time
+		want       int64
+	}{
+		{
+			name:    "yoğun saat yoksa dakika başı ücret",
+			pricing: Pricing{PricePerHour: 600},
+			start:   at(saturday, 18, 0),
+			end:     at(saturday, 19, 30),
+			want:    90_000,
+		},
+		{
+			name:    "çakışan yoğun saatlerde en yüksek çarpan kullanılır",
+			pricing: Pricing{PricePerHour: 600, Peaks: []PeakWindow{evening, prime}},
+			start:   at(saturday, 18, 30),
+			end:     at(saturday, 20, 30),
+			// 30 dakika 1.5 kat, 90 dakika 2 kat
+			want: 45_000 + 180_000,
+		},
+		{
+			name:    "çarpan sırası sonucu değiştirmez",
+			pricing: Pricing{PricePerHour: 600, Peaks: []PeakWindow{prime, evening}},
+			start:   at(saturday, 18, 30),
+			end:     at(saturday, 20, 30),
+			want:    45_000 + 180_000,
+		},
+		{
+			name:    "yoğun saat başka günde geçerli değildir",
+			pricing: Pricing{PricePerHour: 600, Peaks: []PeakWindow{evening, prime}},
+			start:   at(saturday.AddDate(0, 0, 1), 18, 30),
+			end:     at(saturday.AddDate(0, 0, 1), 20, 30),
+			want:    120_000,
+		},
+		{
+			name:    "24:00'te biten yoğun saat gece yarısından sonra uygulanmaz",
+			pricing: Pricing{PricePerHour: 600, Peaks: []PeakWindow{lateNight}},
+			start:   at(saturday, 23, 0),
+			end:     at(saturday.AddDate(0, 0, 1), 0, 30),
+			want:    120_000 + 30_000,
+		},
+		{
+			name:    "yoğun saatler sahanın saat diliminde hesaplanır",
+			pricing: Pricing{PricePerHour: 600, Location: istanbul, Peaks: []PeakWindow{everyDay}},
+			// 15:00 UTC İstanbul'da 18:00'dir
+			start: at(saturday, 15, 0),
+			end:   at(saturday, 16, 0),
+			want:  120_000,
+		},
+		{
+			name:    "sonuç en yakın kuruşa yuvarlanır",
+			pricing: Pricing{PricePerHour: 100},
+			start:   at(saturday, 18, 0),
+			end:     at(saturday, 18, 7),
+			// 7 * 10000 / 60 = 1166.67
+			want: 1167,
+		},
+		{
+			name:    "boş aralık ücretsizdir",
+			pricing: Pricing{PricePerHour: 600},
+			start:   at(saturday, 18, 0),
+			end:     at(saturday, 18, 0),
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pricing.Price(tt.start, tt.end); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceKeepsWallClockOnDSTDays(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	pricing := Pricing{PricePerHour: 600, Location: berlin, Peaks: []PeakWindow{{From: 18 * time.Hour, To: 20 * time.Hour, Multiplier: 2}}}
+	for _, day := range []time.Time{
+		time.Date(2026, time.March, 29, 0, 0, 0, 0, berlin),
+		time.Date(2026, time.October, 25, 0, 0, 0, 0, berlin),
+	} {
+		if got := pricing.Price(at(day, 18, 0), at(day, 19, 0)); got != 120_000 {
+			t.Errorf("%s: got %d, want 120000", day.Format("2006-01-02"), got)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int64
+		n     int
+		want  []int64
+	}{
+		{name: "tam bölünür", total: 99, n: 3, want: []int64{33, 33, 33}},
+		{name: "artan kuruş ilk paya eklenir", total: 100, n: 3, want: []int64{34, 33, 33}},
+		{name: "artan kuruşlar ilk paylara dağıtılır", total: 101, n: 3, want: []int64{34, 34, 33}},
+		{name: "kişi sayısından az kuruş", total: 2, n: 4, want: []int64{1, 1, 0, 0}},
+		{name: "tek kişi", total: 12_345, n: 1, want: []int64{12_345}},
+		{name: "kişi yoksa pay yoktur", total: 100, n: 0, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Split(tt.total, tt.n)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+
+			var sum int64
+			for _, share := range got {
+				sum += share
+			}
+			if len(got) > 0 && sum != tt.total {
+				t.Errorf("shares add up to %d, want %d", sum, tt.total)
+			}
+		})
+	}
+}
+
+func TestRefund(t *testing.T) {
+	policy := RefundPolicy{FullRefundBefore: 24 * time.Hour, PartialRefundBefore: 6 * time.Hour, PartialRefundPercent: 50}
+	start := at(saturday, 20, 0)
+
+	tests := []struct {
+		name   string
+		notice time.Duration
+		want   int64
+	}{
+		{name: "tam iade süresinden önce", notice: 48 * time.Hour, want: 1001},
+		{name: "tam iade sınırında", notice: 24 * time.Hour, want: 1001},
+		{name: "tam iade sınırından hemen sonra", notice: 24*time.Hour - time.Second, want: 500},
+		{name: "kısmi iade sınırında", notice: 6 * time.Hour, want: 500},
+		{name: "kısmi iade sınırından hemen sonra", notice: 6*time.Hour - time.Second, want: 0},
+		{name: "başladıktan sonra", notice: -time.Hour, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Refund(1001, start, start.Add(-tt.notice)); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefundPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RefundPolicy
+		wantErr bool
+	}{
+		{name: "geçerli", policy: RefundPolicy{FullRefundBefore: 24 * time.Hour, PartialRefundBefore: 6 * time.Hour, PartialRefundPercent: 50}},
+		{name: "kısmi süre tam süreden uzun", policy: RefundPolicy{FullRefundBefore: time.Hour, PartialRefundBefore: 2 * time.Hour}, wantErr: true},
+		{name: "yüzde 100'den büyük", policy: RefundPolicy{FullRefundBefore: time.Hour, PartialRefundPercent: 101}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePeakWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		weekdays   []string
+		from, to   string
+		multiplier float64
+		want       PeakWindow
+		wantErr    bool
+	}{
+		{
+			name:       "gün sonu 24:00",
+			weekdays:   []string{"friday", "cumartesi"},
+			from:       "18:00",
+			to:         "24:00",
+			multiplier: 1.5,
+			want:       PeakWindow{Weekdays: []time.Weekday{time.Friday, time.Saturday}, From: 18 * time.Hour, To: 24 * time.Hour, Multiplier: 1.5},
+		},
+		{name: "bitiş başlangıçtan önce", from: "20:00", to: "18:00", multiplier: 2, wantErr: true},
+		{name: "çarpan 1'den küçük", from: "18:00", to: "20:00", multiplier: 0.5, wantErr: true},
+		{name: "bilinmeyen gün", weekdays: []string{"someday"}, from: "18:00", to: "20:00", multiplier: 2, wantErr: true},
+		{name: "geçersiz saat", from: "18", to: "20:00", multiplier: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePeakWindow(tt.weekdays, tt.from, tt.to, tt.multiplier)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
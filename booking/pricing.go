@@ -0,0 +1,129 @@
+package booking
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/personal-project/pitch-league/utils"
+)
+
+// PeakWindow belirli günlerde belirli saatler arasında fiyatın çarpanla arttığı dönemdir.
+// From ve To gün başından itibaren süredir, Weekdays boşsa her gün geçerlidir.
+type PeakWindow struct {
+	Weekdays   []time.Weekday
+	From       time.Duration
+	To         time.Duration
+	Multiplier float64
+}
+
+func (w PeakWindow) contains(t time.Time) bool {
+	if len(w.Weekdays) > 0 {
+		found := false
+		for _, d := range w.Weekdays {
+			if d == t.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	// Saat dilimi değişen günlerde de duvar saatiyle karşılaştırılsın diye sınırlar gün başına süre eklenerek kurulmaz
+	from, to := clock(t, w.From), clock(t, w.To)
+	return !t.Before(from) && t.Before(to)
+}
+
+// clock t'nin gününde verilen duvar saatini döner, 24 saat ertesi günün başıdır
+func clock(t time.Time, offset time.Duration) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, t.Location())
+}
+
+// Pricing bir sahanın saatlik ücretinden rezervasyon fiyatını hesaplar. Tutarlar kuruş cinsindendir.
+type Pricing struct {
+	PricePerHour float64
+	Location     *time.Location
+	Peaks        []PeakWindow
+}
+
+// Price [start, end) aralığının fiyatını dakika dakika hesaplar: her dakika saatlik ücretin 1/60'ı ile
+// o dakikaya denk gelen en yüksek yoğun saat çarpanının çarpımıdır. Sonuç en yakın kuruşa yuvarlanır.
+func (p Pricing) Price(start, end time.Time) int64 {
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	perMinute := p.PricePerHour * 100 / 60
+	var total float64
+	for t := start; t.Before(end); t = t.Add(time.Minute) {
+		total += perMinute * p.multiplier(t.In(loc))
+	}
+	return int64(math.Round(total))
+}
+
+func (p Pricing) multiplier(t time.Time) float64 {
+	m := 1.0
+	for _, w := range p.Peaks {
+		if w.contains(t) && w.Multiplier > m {
+			m = w.Multiplier
+		}
+	}
+	return m
+}
+
+// Split tutarı n kişiye eşit böler, bölünemeyen kuruşlar ilk paylara eklenir ki toplam değişmesin
+func Split(total int64, n int) []int64 {
+	if n <= 0 {
+		return nil
+	}
+
+	shares := make([]int64, n)
+	base := total / int64(n)
+	remainder := total % int64(n)
+	for i := range shares {
+		shares[i] = base
+		if int64(i) < remainder {
+			shares[i]++
+		}
+	}
+	return shares
+}
+
+// ParsePeakWindow yapılandırmadaki gün isimlerini ve "HH:MM" saatlerini PeakWindow'a çevirir, gün sonu için "24:00" kabul edilir
+func ParsePeakWindow(weekdays []string, from, to string, multiplier float64) (PeakWindow, error) {
+	w := PeakWindow{Multiplier: multiplier}
+	for _, name := range weekdays {
+		d, err := utils.ParseWeekday(name)
+		if err != nil {
+			return w, err
+		}
+		w.Weekdays = append(w.Weekdays, d)
+	}
+
+	var err error
+	if w.From, err = parseClock(from); err != nil {
+		return w, err
+	}
+	if w.To, err = parseClock(to); err != nil {
+		return w, err
+	}
+	if w.To <= w.From {
+		return w, fmt.Errorf("peak window must end after it starts: %s-%s", from, to)
+	}
+	if multiplier < 1 {
+		return w, fmt.Errorf("peak multiplier must be at least 1: %v", multiplier)
+	}
+	return w, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	if strings.TrimSpace(s) == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	return utils.ParseClock(s)
+}
@@ -0,0 +1,42 @@
+package booking
+
+import (
+	"errors"
+	"time"
+)
+
+// RefundPolicy iptalde geri ödenecek tutarı oyunun başlamasına kalan süreye göre belirler:
+// FullRefundBefore veya daha önce iptal edilirse tamamı, PartialRefundBefore veya daha önce iptal edilirse
+// PartialRefundPercent kadarı, daha geç iptallerde hiçbir şey geri ödenmez.
+type RefundPolicy struct {
+	FullRefundBefore     time.Duration
+	PartialRefundBefore  time.Duration
+	PartialRefundPercent int64
+}
+
+func (p RefundPolicy) Validate() error {
+	var errs []error
+	if p.FullRefundBefore < p.PartialRefundBefore {
+		errs = append(errs, errors.New("full refund window must be at least the partial refund window"))
+	}
+	if p.PartialRefundBefore < 0 {
+		errs = append(errs, errors.New("partial refund window cannot be negative"))
+	}
+	if p.PartialRefundPercent < 0 || p.PartialRefundPercent > 100 {
+		errs = append(errs, errors.New("partial refund percent must be between 0 and 100"))
+	}
+	return errors.Join(errs...)
+}
+
+// Refund start zamanlı bir rezervasyon cancelledAt anında iptal edilirse amount'un ne kadarının geri ödeneceğini döner
+func (p RefundPolicy) Refund(amount int64, start, cancelledAt time.Time) int64 {
+	notice := start.Sub(cancelledAt)
+	switch {
+	case notice >= p.FullRefundBefore:
+		return amount
+	case notice >= p.PartialRefundBefore:
+		return amount * p.PartialRefundPercent / 100
+	default:
+		return 0
+	}
+}
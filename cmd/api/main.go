@@ -1,25 +1,49 @@
 package main
 
 import (
+	"github.com/personal-project/pitch-league/config"
 	"github.com/personal-project/pitch-league/database"
 	"github.com/personal-project/pitch-league/router"
 	"log"
+	"log/slog"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+
+	peakHours, err := cfg.Booking.PeakWindows()
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: cfg.Log.SlogLevel()})))
+
 	log.Println("bismillah")
-	db := database.DB()
+	db := database.DB(cfg.Database)
 
 	app := fiber.New()
 	router.Setup(app, db, router.Config{
-		JWTSecret:              "secret",
-		AccessTokenExpireTime:  15,
-		RefreshTokenExpireTime: 24,
+		JWTSecret:                 cfg.Auth.JWTSecret,
+		AccessTokenExpireTime:     cfg.Auth.AccessTokenTTL,
+		RefreshTokenExpireTime:    cfg.Auth.RefreshTokenTTL,
+		RevocationStore:           cfg.Auth.RevocationStore,
+		RevocationSweepInterval:   cfg.Auth.RevocationSweepInterval,
+		NotificationDriver:        cfg.Notification.Driver,
+		NotificationFilePath:      cfg.Notification.FilePath,
+		NotificationSweepInterval: cfg.Notification.SweepInterval,
+		ReservationHoldTTL:        cfg.Booking.HoldTTL,
+		ReservationSweepInterval:  cfg.Booking.HoldSweepInterval,
+		ReservationRefundPolicy:   cfg.Booking.RefundPolicy(),
+		ReservationPeakHours:      peakHours,
 	})
 
-	if err := app.Listen(":3000"); err != nil {
+	if err := app.Listen(cfg.Server.Addr); err != nil {
 		log.Fatalf("%v", err)
 	}
 
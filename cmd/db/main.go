@@ -2,9 +2,11 @@ package main
 
 import (
 	"fmt"
+	"github.com/personal-project/pitch-league/config"
 	"github.com/personal-project/pitch-league/database"
 	"github.com/personal-project/pitch-league/database/migrations"
 	"os"
+	"strings"
 
 	"github.com/uptrace/bun/migrate"
 	"github.com/urfave/cli/v2"
@@ -16,9 +18,13 @@ func main() {
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "env",
-				Value: "dev",
+				Value: config.EnvDevelopment,
 				Usage: "environment",
 			},
+			&cli.StringFlag{
+				Name:  "config",
+				Usage: "path to a YAML or TOML config file",
+			},
 		},
 		Commands: commands,
 	}
@@ -34,7 +40,10 @@ var commands = []*cli.Command{
 		Name:  "init",
 		Usage: "create migration tables",
 		Action: func(c *cli.Context) error {
-			migrator := getMigrator()
+			migrator, err := getMigrator(c)
+			if err != nil {
+				return err
+			}
 			return migrator.Init(c.Context)
 		},
 	},
@@ -42,7 +51,15 @@ var commands = []*cli.Command{
 		Name:  "migrate",
 		Usage: "migrate database",
 		Action: func(c *cli.Context) error {
-			migrator := getMigrator()
+			migrator, err := getMigrator(c)
+			if err != nil {
+				return err
+			}
+
+			if err := migrator.Lock(c.Context); err != nil {
+				return err
+			}
+			defer migrator.Unlock(c.Context) //nolint:errcheck
 
 			group, err := migrator.Migrate(c.Context)
 			if err != nil {
@@ -62,7 +79,15 @@ var commands = []*cli.Command{
 		Name:  "rollback",
 		Usage: "rollback the last migration group",
 		Action: func(c *cli.Context) error {
-			migrator := getMigrator()
+			migrator, err := getMigrator(c)
+			if err != nil {
+				return err
+			}
+
+			if err := migrator.Lock(c.Context); err != nil {
+				return err
+			}
+			defer migrator.Unlock(c.Context) //nolint:errcheck
 
 			group, err := migrator.Rollback(c.Context)
 			if err != nil {
@@ -81,7 +106,10 @@ var commands = []*cli.Command{
 		Name:  "status",
 		Usage: "print migrations status",
 		Action: func(c *cli.Context) error {
-			migrator := getMigrator()
+			migrator, err := getMigrator(c)
+			if err != nil {
+				return err
+			}
 
 			ms, err := migrator.MigrationsWithStatus(c.Context)
 			if err != nil {
@@ -94,10 +122,101 @@ var commands = []*cli.Command{
 			return nil
 		},
 	},
+	{
+		Name:  "lock",
+		Usage: "lock migrations",
+		Action: func(c *cli.Context) error {
+			migrator, err := getMigrator(c)
+			if err != nil {
+				return err
+			}
+			return migrator.Lock(c.Context)
+		},
+	},
+	{
+		Name:  "unlock",
+		Usage: "unlock migrations",
+		Action: func(c *cli.Context) error {
+			migrator, err := getMigrator(c)
+			if err != nil {
+				return err
+			}
+			return migrator.Unlock(c.Context)
+		},
+	},
+	{
+		Name:  "create_go",
+		Usage: "create Go migration",
+		Action: func(c *cli.Context) error {
+			migrator, err := getMigrator(c)
+			if err != nil {
+				return err
+			}
+
+			name := strings.Join(c.Args().Slice(), "_")
+			mf, err := migrator.CreateGoMigration(c.Context, name)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("created migration %s (%s)\n", mf.Name, mf.Path)
+			return nil
+		},
+	},
+	{
+		Name:  "create_sql",
+		Usage: "create up and down SQL migrations",
+		Action: func(c *cli.Context) error {
+			migrator, err := getMigrator(c)
+			if err != nil {
+				return err
+			}
+
+			name := strings.Join(c.Args().Slice(), "_")
+			files, err := migrator.CreateSQLMigrations(c.Context, name)
+			if err != nil {
+				return err
+			}
+
+			for _, mf := range files {
+				fmt.Printf("created migration %s (%s)\n", mf.Name, mf.Path)
+			}
+			return nil
+		},
+	},
+	{
+		Name:  "create_tx_sql",
+		Usage: "create up and down transactional SQL migrations",
+		Action: func(c *cli.Context) error {
+			migrator, err := getMigrator(c)
+			if err != nil {
+				return err
+			}
+
+			name := strings.Join(c.Args().Slice(), "_")
+			files, err := migrator.CreateTxSQLMigrations(c.Context, name)
+			if err != nil {
+				return err
+			}
+
+			for _, mf := range files {
+				fmt.Printf("created migration %s (%s)\n", mf.Name, mf.Path)
+			}
+			return nil
+		},
+	},
 }
 
-func getMigrator() *migrate.Migrator {
-	db := database.DB()
+func getMigrator(c *cli.Context) (*migrate.Migrator, error) {
+	args := []string{"-env", c.String("env")}
+	if path := c.String("config"); path != "" {
+		args = append(args, "-config", path)
+	}
+
+	cfg, err := config.Load(args)
+	if err != nil {
+		return nil, err
+	}
+	db := database.DB(cfg.Database)
 
-	return migrate.NewMigrator(db, migrations.Migrations)
+	return migrate.NewMigrator(db, migrations.Migrations), nil
 }
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/personal-project/pitch-league/booking"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvProduction  = "production"
+
+	// DefaultJWTSecret sadece lokal geliştirme içindir, production'da kullanılırsa uygulama başlamaz
+	DefaultJWTSecret = "secret"
+)
+
+type Config struct {
+	Env          string             `yaml:"env" toml:"env"`
+	Server       ServerConfig       `yaml:"server" toml:"server"`
+	Auth         AuthConfig         `yaml:"auth" toml:"auth"`
+	Database     DatabaseConfig     `yaml:"database" toml:"database"`
+	Log          LogConfig          `yaml:"log" toml:"log"`
+	Notification NotificationConfig `yaml:"notification" toml:"notification"`
+	Booking      BookingConfig      `yaml:"booking" toml:"booking"`
+}
+
+type ServerConfig struct {
+	Addr string `yaml:"addr" toml:"addr"`
+}
+
+type AuthConfig struct {
+	JWTSecret               string        `yaml:"jwt_secret" toml:"jwt_secret"`
+	AccessTokenTTL          time.Duration `yaml:"access_token_ttl" toml:"access_token_ttl"`
+	RefreshTokenTTL         time.Duration `yaml:"refresh_token_ttl" toml:"refresh_token_ttl"`
+	RevocationStore         string        `yaml:"revocation_store" toml:"revocation_store"`
+	RevocationSweepInterval time.Duration `yaml:"revocation_sweep_interval" toml:"revocation_sweep_interval"`
+}
+
+type DatabaseConfig struct {
+	URL             string        `yaml:"url" toml:"url"`
+	MaxOpenConns    int           `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`
+	// Debug açıkken tüm sorgular bundebug ile loglanır
+	Debug bool `yaml:"debug" toml:"debug"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level" toml:"level"`
+}
+
+type NotificationConfig struct {
+	// Driver "log" veya "file" olabilir
+	Driver   string `yaml:"driver" toml:"driver"`
+	FilePath string `yaml:"file_path" toml:"file_path"`
+	// SweepInterval iptal edilen veya reddedilen oyunların bildirimlerinin gönderilme sıklığıdır
+	SweepInterval time.Duration `yaml:"sweep_interval" toml:"sweep_interval"`
+}
+
+type BookingConfig struct {
+	// HoldTTL onaylanmayan rezervasyonun sahayı tuttuğu süredir
+	HoldTTL           time.Duration `yaml:"hold_ttl" toml:"hold_ttl"`
+	HoldSweepInterval time.Duration `yaml:"hold_sweep_interval" toml:"hold_sweep_interval"`
+	// PaymentProvider şimdilik sadece "fake" olabilir, gerçek sağlayıcı eklenene kadar ödemeler bellekte tutulur
+	PaymentProvider string       `yaml:"payment_provider" toml:"payment_provider"`
+	Refund          RefundConfig `yaml:"refund" toml:"refund"`
+	PeakHours       []PeakHour   `yaml:"peak_hours" toml:"peak_hours"`
+}
+
+// RefundConfig iptal iade politikasıdır: full_before veya daha önce iptalde tamamı, partial_before veya daha
+// önce iptalde partial_percent kadarı iade edilir, daha geç iptallerde iade yoktur
+type RefundConfig struct {
+	FullBefore     time.Duration `yaml:"full_before" toml:"full_before"`
+	PartialBefore  time.Duration `yaml:"partial_before" toml:"partial_before"`
+	PartialPercent int64         `yaml:"partial_percent" toml:"partial_percent"`
+}
+
+// PeakHour sahanın saatlik ücretinin çarpanla arttığı saatlerdir, saatler sahanın saat diliminde yorumlanır
+type PeakHour struct {
+	Weekdays   []string `yaml:"weekdays" toml:"weekdays"`
+	From       string   `yaml:"from" toml:"from"`
+	To         string   `yaml:"to" toml:"to"`
+	Multiplier float64  `yaml:"multiplier" toml:"multiplier"`
+}
+
+func (c BookingConfig) RefundPolicy() booking.RefundPolicy {
+	return booking.RefundPolicy{
+		FullRefundBefore:     c.Refund.FullBefore,
+		PartialRefundBefore:  c.Refund.PartialBefore,
+		PartialRefundPercent: c.Refund.PartialPercent,
+	}
+}
+
+func (c BookingConfig) PeakWindows() ([]booking.PeakWindow, error) {
+	windows := make([]booking.PeakWindow, 0, len(c.PeakHours))
+	for _, p := range c.PeakHours {
+		w, err := booking.ParsePeakWindow(p.Weekdays, p.From, p.To, p.Multiplier)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// Default lokal geliştirme için çalışan varsayılan ayarları döner
+func Default() Config {
+	return Config{
+		Env: EnvDevelopment,
+		Server: ServerConfig{
+			Addr: ":3000",
+		},
+		Auth: AuthConfig{
+			JWTSecret:               DefaultJWTSecret,
+			AccessTokenTTL:          15 * time.Hour,
+			RefreshTokenTTL:         24 * time.Hour,
+			RevocationStore:         "postgres",
+			RevocationSweepInterval: 10 * time.Minute,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			Debug:           false,
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Notification: NotificationConfig{
+			Driver:        "log",
+			FilePath:      "notifications.log",
+			SweepInterval: 30 * time.Second,
+		},
+		Booking: BookingConfig{
+			HoldTTL:           15 * time.Minute,
+			HoldSweepInterval: time.Minute,
+			PaymentProvider:   "fake",
+			Refund: RefundConfig{
+				FullBefore:     24 * time.Hour,
+				PartialBefore:  6 * time.Hour,
+				PartialPercent: 50,
+			},
+		},
+	}
+}
+
+func (c Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+// Validate ayarların tutarlı olduğunu kontrol eder
+func (c Config) Validate() error {
+	var errs []error
+
+	switch c.Env {
+	case EnvDevelopment, EnvTest, EnvProduction:
+	default:
+		errs = append(errs, fmt.Errorf("env must be one of development, test, production: %q", c.Env))
+	}
+
+	if c.Server.Addr == "" {
+		errs = append(errs, errors.New("server.addr is required"))
+	}
+
+	if c.Auth.JWTSecret == "" {
+		errs = append(errs, errors.New("auth.jwt_secret is required"))
+	}
+	if c.IsProduction() && (c.Auth.JWTSecret == DefaultJWTSecret || len(c.Auth.JWTSecret) < 32) {
+		errs = append(errs, errors.New("auth.jwt_secret must be set to a random value of at least 32 characters in production"))
+	}
+	if c.Auth.AccessTokenTTL <= 0 {
+		errs = append(errs, errors.New("auth.access_token_ttl must be positive"))
+	}
+	if c.Auth.RefreshTokenTTL <= c.Auth.AccessTokenTTL {
+		errs = append(errs, errors.New("auth.refresh_token_ttl must be longer than auth.access_token_ttl"))
+	}
+	switch c.Auth.RevocationStore {
+	case "memory", "postgres":
+	default:
+		errs = append(errs, fmt.Errorf("auth.revocation_store must be memory or postgres: %q", c.Auth.RevocationStore))
+	}
+	if c.Auth.RevocationSweepInterval <= 0 {
+		errs = append(errs, errors.New("auth.revocation_sweep_interval must be positive"))
+	}
+
+	if c.Database.URL == "" {
+		errs = append(errs, errors.New("database.url (DATABASE_URL) is required"))
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("database connection pool sizes cannot be negative"))
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, errors.New("database.max_idle_conns cannot exceed database.max_open_conns"))
+	}
+
+	if _, err := parseLevel(c.Log.Level); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch c.Notification.Driver {
+	case "log":
+	case "file":
+		if c.Notification.FilePath == "" {
+			errs = append(errs, errors.New("notification.file_path is required for the file driver"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("notification.driver must be log or file: %q", c.Notification.Driver))
+	}
+	if c.Notification.SweepInterval <= 0 {
+		errs = append(errs, errors.New("notification.sweep_interval must be positive"))
+	}
+
+	if c.Booking.HoldTTL <= 0 {
+		errs = append(errs, errors.New("booking.hold_ttl must be positive"))
+	}
+	if c.Booking.HoldSweepInterval <= 0 {
+		errs = append(errs, errors.New("booking.hold_sweep_interval must be positive"))
+	}
+	if c.Booking.PaymentProvider != "fake" {
+		errs = append(errs, fmt.Errorf("booking.payment_provider must be fake: %q", c.Booking.PaymentProvider))
+	}
+	if err := c.Booking.RefundPolicy().Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("booking.refund: %w", err))
+	}
+	if _, err := c.Booking.PeakWindows(); err != nil {
+		errs = append(errs, fmt.Errorf("booking.peak_hours: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// SlogLevel log seviyesini slog karşılığına çevirir
+func (c LogConfig) SlogLevel() slog.Level {
+	level, _ := parseLevel(c.Level)
+	return level
+}
+
+func parseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("log.level must be one of debug, info, warn, error: %q", s)
+	}
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Load ayarları sırasıyla varsayılanlar, config dosyası, ortam değişkenleri ve
+// komut satırı bayraklarından okur; sonraki kaynak öncekini ezer.
+func Load(args []string) (Config, error) {
+	cfg := Default()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, environment variables will be read from the system environment")
+	}
+
+	fs := flag.NewFlagSet("pitch-league", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	env := fs.String("env", "", "environment: development, test or production")
+	addr := fs.String("addr", "", "HTTP listen address")
+	jwtSecret := fs.String("jwt-secret", "", "JWT signing secret")
+	accessTTL := fs.Duration("access-token-ttl", 0, "access token lifetime")
+	refreshTTL := fs.Duration("refresh-token-ttl", 0, "refresh token lifetime")
+	databaseURL := fs.String("database-url", "", "PostgreSQL connection string")
+	dbDebug := fs.Bool("db-debug", false, "log every SQL query")
+	logLevel := fs.String("log-level", "", "log level: debug, info, warn, error")
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	if *configPath != "" {
+		if err := loadFile(*configPath, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	if err := loadEnv(&cfg); err != nil {
+		return cfg, err
+	}
+
+	// Sadece açıkça verilen bayraklar uygulanır
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "env":
+			cfg.Env = *env
+		case "addr":
+			cfg.Server.Addr = *addr
+		case "jwt-secret":
+			cfg.Auth.JWTSecret = *jwtSecret
+		case "access-token-ttl":
+			cfg.Auth.AccessTokenTTL = *accessTTL
+		case "refresh-token-ttl":
+			cfg.Auth.RefreshTokenTTL = *refreshTTL
+		case "database-url":
+			cfg.Database.URL = *databaseURL
+		case "db-debug":
+			cfg.Database.Debug = *dbDebug
+		case "log-level":
+			cfg.Log.Level = *logLevel
+		}
+	})
+
+	return cfg, cfg.Validate()
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file could not be read: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file extension: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("config file %s could not be parsed: %w", path, err)
+	}
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	setString := func(key string, dst *string) {
+		if v, ok := os.LookupEnv(key); ok {
+			*dst = v
+		}
+	}
+
+	var errs []error
+	setDuration := func(key string, dst *time.Duration) {
+		if v, ok := os.LookupEnv(key); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = d
+		}
+	}
+	setInt := func(key string, dst *int) {
+		if v, ok := os.LookupEnv(key); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = n
+		}
+	}
+	setBool := func(key string, dst *bool) {
+		if v, ok := os.LookupEnv(key); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = b
+		}
+	}
+
+	setString("APP_ENV", &cfg.Env)
+	setString("HTTP_ADDR", &cfg.Server.Addr)
+	setString("JWT_SECRET", &cfg.Auth.JWTSecret)
+	setDuration("ACCESS_TOKEN_TTL", &cfg.Auth.AccessTokenTTL)
+	setDuration("REFRESH_TOKEN_TTL", &cfg.Auth.RefreshTokenTTL)
+	setString("REVOCATION_STORE", &cfg.Auth.RevocationStore)
+	setDuration("REVOCATION_SWEEP_INTERVAL", &cfg.Auth.RevocationSweepInterval)
+	setString("DATABASE_URL", &cfg.Database.URL)
+	setInt("DB_MAX_OPEN_CONNS", &cfg.Database.MaxOpenConns)
+	setInt("DB_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns)
+	setDuration("DB_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime)
+	setDuration("DB_CONN_MAX_IDLE_TIME", &cfg.Database.ConnMaxIdleTime)
+	setBool("DB_DEBUG", &cfg.Database.Debug)
+	setString("LOG_LEVEL", &cfg.Log.Level)
+	setString("NOTIFICATION_DRIVER", &cfg.Notification.Driver)
+	setString("NOTIFICATION_FILE", &cfg.Notification.FilePath)
+	setDuration("NOTIFICATION_SWEEP_INTERVAL", &cfg.Notification.SweepInterval)
+	setDuration("BOOKING_HOLD_TTL", &cfg.Booking.HoldTTL)
+	setString("PAYMENT_PROVIDER", &cfg.Booking.PaymentProvider)
+
+	return errors.Join(errs...)
+}
@@ -2,33 +2,29 @@ package database
 
 import (
 	"database/sql"
-	"github.com/joho/godotenv"
+	"github.com/personal-project/pitch-league/config"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/uptrace/bun/extra/bundebug"
-	"log"
-	"os"
 )
 
 var db *bun.DB
 
-func DB() *bun.DB {
+func DB(cfg config.DatabaseConfig) *bun.DB {
 	if db != nil {
 		return db
 	}
 
-	if err := godotenv.Load(); err != nil {
-		log.Println("Warning: .env file not found, environment variables will be read from the system environment")
-	}
-
-	dsn := os.Getenv("DATABASE_URL")
-	if dsn == "" {
-		log.Fatal("DATABASE_URL environment variable not set")
-	}
+	sqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(cfg.URL)))
+	sqldb.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqldb.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqldb.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqldb.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	sqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn)))
 	db = bun.NewDB(sqldb, pgdialect.New())
-	db.AddQueryHook(bundebug.NewQueryHook(bundebug.WithVerbose(true)))
+	if cfg.Debug {
+		db.AddQueryHook(bundebug.NewQueryHook(bundebug.WithVerbose(true)))
+	}
 	return db
 }
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    email      VARCHAR(64)  NOT NULL DEFAULT '',
+    phone      VARCHAR(11)  NOT NULL DEFAULT '',
+    name       VARCHAR(100) NOT NULL,
+    surname    VARCHAR(100) NOT NULL,
+    username   VARCHAR(20)  NOT NULL,
+    password   VARCHAR(255) NOT NULL,
+    role       INTEGER      NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT current_timestamp,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT users_email_or_phone_check CHECK (email <> '' OR phone <> '')
+);
+
+--bun:split
+
+-- Migrasyonlardan önce oluşturulmuş veritabanlarında CREATE TABLE atlandığı için sonradan eklenen kolonlar ayrıca eklenir
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ;
+
+--bun:split
+
+-- Doğrulama gelmeden önce kayıt olan kullanıcılar doğrulanmış sayılır, aksi halde giriş yapamazlar
+UPDATE users SET verified_at = created_at WHERE verified_at IS NULL;
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (email) WHERE email <> '' AND deleted_at IS NULL;
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_phone_key ON users (phone) WHERE phone <> '' AND deleted_at IS NULL;
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_key ON users (username) WHERE deleted_at IS NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN IF EXISTS team_id;
+
+--bun:split
+
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS teams (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    capacity   BIGINT       NOT NULL CHECK (capacity >= 0),
+    captain_id BIGINT       NOT NULL REFERENCES users (id) ON DELETE RESTRICT
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS teams_captain_id_idx ON teams (captain_id);
+
+--bun:split
+
+-- TeamRepository.AddUserToTeam oyuncunun takımını users tablosunda tutar
+ALTER TABLE users ADD COLUMN IF NOT EXISTS team_id BIGINT REFERENCES teams (id) ON DELETE SET NULL;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS users_team_id_idx ON users (team_id);
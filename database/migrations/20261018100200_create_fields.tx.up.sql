@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS fields (
+    id             BIGSERIAL PRIMARY KEY,
+    name           VARCHAR(100)     NOT NULL,
+    location       VARCHAR(255)     NOT NULL,
+    price_per_hour DOUBLE PRECISION NOT NULL CHECK (price_per_hour >= 0),
+    capacity       BIGINT           NOT NULL CHECK (capacity > 0),
+    available      BOOLEAN          NOT NULL DEFAULT TRUE
+);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS games (
+    id          BIGSERIAL PRIMARY KEY,
+    field_id    BIGINT      NOT NULL REFERENCES fields (id) ON DELETE RESTRICT,
+    host_id     BIGINT      NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    start_time  TIMESTAMPTZ NOT NULL,
+    end_time    TIMESTAMPTZ NOT NULL,
+    max_players BIGINT      NOT NULL CHECK (max_players > 0),
+    status      VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    CONSTRAINT games_time_range_check CHECK (end_time > start_time),
+    CONSTRAINT games_status_check CHECK (status IN ('PENDING', 'ACCEPTED', 'REJECTED', 'CANCELLED', 'FINISHED'))
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS games_field_id_start_time_idx ON games (field_id, start_time);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS games_host_id_idx ON games (host_id);
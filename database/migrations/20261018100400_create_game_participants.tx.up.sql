@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS game_participants (
+    id      BIGSERIAL PRIMARY KEY,
+    game_id BIGINT NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    team_id BIGINT NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    CONSTRAINT game_participants_game_user_key UNIQUE (game_id, user_id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS game_participants_user_id_idx ON game_participants (user_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS game_participants_team_id_idx ON game_participants (team_id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS leagues (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    location   VARCHAR(255) NOT NULL,
+    start_date TIMESTAMPTZ  NOT NULL,
+    end_date   TIMESTAMPTZ  NOT NULL,
+    CONSTRAINT leagues_name_key UNIQUE (name),
+    CONSTRAINT leagues_date_range_check CHECK (end_date >= start_date)
+);
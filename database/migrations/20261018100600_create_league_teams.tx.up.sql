@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS league_teams (
+    id        BIGSERIAL PRIMARY KEY,
+    league_id BIGINT NOT NULL REFERENCES leagues (id) ON DELETE CASCADE,
+    team_id   BIGINT NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    points    BIGINT NOT NULL DEFAULT 0,
+    rank      BIGINT NOT NULL DEFAULT 0,
+    -- MatchRepository.UpdateLeagueStandings bu kısıta göre ON CONFLICT kullanır
+    CONSTRAINT league_teams_league_team_key UNIQUE (league_id, team_id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS league_teams_team_id_idx ON league_teams (team_id);
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS matches (
+    id           BIGSERIAL PRIMARY KEY,
+    league_id    BIGINT      NOT NULL REFERENCES leagues (id) ON DELETE CASCADE,
+    home_team_id BIGINT      NOT NULL REFERENCES teams (id) ON DELETE RESTRICT,
+    away_team_id BIGINT      NOT NULL REFERENCES teams (id) ON DELETE RESTRICT,
+    match_time   TIMESTAMPTZ NOT NULL,
+    home_score   BIGINT      NOT NULL DEFAULT 0 CHECK (home_score >= 0),
+    away_score   BIGINT      NOT NULL DEFAULT 0 CHECK (away_score >= 0),
+    status       VARCHAR(20) NOT NULL DEFAULT 'SCHEDULED',
+    game_id      BIGINT      NOT NULL REFERENCES games (id) ON DELETE RESTRICT,
+    CONSTRAINT matches_distinct_teams_check CHECK (home_team_id <> away_team_id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS matches_league_id_status_idx ON matches (league_id, status);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS matches_home_team_id_idx ON matches (home_team_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS matches_away_team_id_idx ON matches (away_team_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS matches_game_id_idx ON matches (game_id);
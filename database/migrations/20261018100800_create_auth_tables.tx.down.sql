@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS verification_codes;
+
+--bun:split
+
+DROP TABLE IF EXISTS revoked_access_tokens;
+
+--bun:split
+
+DROP TABLE IF EXISTS auth_refresh_tokens;
@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS auth_refresh_tokens (
+    token_id          UUID PRIMARY KEY,
+    family_id         UUID             NOT NULL,
+    user_id           BIGINT           NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role              DOUBLE PRECISION NOT NULL,
+    expires_at        TIMESTAMPTZ      NOT NULL,
+    consumed_at       TIMESTAMPTZ,
+    revoked_at        TIMESTAMPTZ,
+    access_token_id   UUID,
+    access_expires_at TIMESTAMPTZ,
+    device_name       VARCHAR(100)     NOT NULL DEFAULT '',
+    user_agent        TEXT             NOT NULL DEFAULT '',
+    ip                VARCHAR(45)      NOT NULL DEFAULT '',
+    last_used_at      TIMESTAMPTZ      NOT NULL DEFAULT current_timestamp,
+    created_at        TIMESTAMPTZ      NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS auth_refresh_tokens_user_id_idx ON auth_refresh_tokens (user_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS auth_refresh_tokens_family_id_idx ON auth_refresh_tokens (family_id);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    token_id   UUID PRIMARY KEY,
+    user_id    BIGINT      NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS revoked_access_tokens_expires_at_idx ON revoked_access_tokens (expires_at);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS verification_codes (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    channel     VARCHAR(10) NOT NULL CHECK (channel IN ('EMAIL', 'PHONE')),
+    target      VARCHAR(64) NOT NULL,
+    code_hash   CHAR(64)    NOT NULL,
+    attempts    BIGINT      NOT NULL DEFAULT 0,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    consumed_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS verification_codes_user_id_created_at_idx ON verification_codes (user_id, created_at DESC);
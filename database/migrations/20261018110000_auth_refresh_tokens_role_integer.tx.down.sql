@@ -0,0 +1 @@
+ALTER TABLE auth_refresh_tokens ALTER COLUMN role TYPE DOUBLE PRECISION USING role::DOUBLE PRECISION;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS fields_owner_id_idx;
+
+--bun:split
+
+ALTER TABLE fields DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,5 @@
+ALTER TABLE fields ADD COLUMN IF NOT EXISTS owner_id BIGINT REFERENCES users (id) ON DELETE SET NULL;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS fields_owner_id_idx ON fields (owner_id);
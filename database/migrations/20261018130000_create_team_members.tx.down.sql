@@ -0,0 +1,26 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS team_id BIGINT REFERENCES teams (id) ON DELETE SET NULL;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS users_team_id_idx ON users (team_id);
+
+--bun:split
+
+-- Kullanıcının en son katıldığı aktif takım users.team_id'ye geri yazılır
+UPDATE users AS u
+SET team_id = (
+    SELECT tm.team_id
+    FROM team_members AS tm
+    WHERE tm.user_id = u.id AND tm.status = 'ACTIVE' AND tm.role <> 'CAPTAIN'
+    ORDER BY tm.joined_at DESC
+    LIMIT 1
+);
+
+--bun:split
+
+UPDATE teams AS t
+SET capacity = GREATEST(t.capacity - (SELECT COUNT(*) FROM team_members AS tm WHERE tm.team_id = t.id AND tm.status = 'ACTIVE'), 0);
+
+--bun:split
+
+DROP TABLE IF EXISTS team_members;
@@ -0,0 +1,56 @@
+CREATE TABLE IF NOT EXISTS team_members (
+    id            BIGSERIAL PRIMARY KEY,
+    team_id       BIGINT      NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    user_id       BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role          VARCHAR(20) NOT NULL DEFAULT 'PLAYER' CHECK (role IN ('CAPTAIN', 'VICE_CAPTAIN', 'PLAYER')),
+    jersey_number BIGINT CHECK (jersey_number BETWEEN 1 AND 99),
+    status        VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'LEFT', 'KICKED')),
+    joined_at     TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    left_at       TIMESTAMPTZ
+);
+
+--bun:split
+
+-- Bir kullanıcı bir takımda aynı anda tek aktif üyeliğe sahip olabilir
+CREATE UNIQUE INDEX IF NOT EXISTS team_members_team_user_active_key ON team_members (team_id, user_id) WHERE status = 'ACTIVE';
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS team_members_team_jersey_active_key ON team_members (team_id, jersey_number) WHERE status = 'ACTIVE' AND jersey_number IS NOT NULL;
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS team_members_team_captain_active_key ON team_members (team_id) WHERE status = 'ACTIVE' AND role = 'CAPTAIN';
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS team_members_user_id_idx ON team_members (user_id);
+
+--bun:split
+
+-- Mevcut kaptanları ve users.team_id üzerindeki oyuncuları yeni tabloya taşı
+INSERT INTO team_members (team_id, user_id, role)
+SELECT t.id, t.captain_id, 'CAPTAIN'
+FROM teams AS t;
+
+--bun:split
+
+INSERT INTO team_members (team_id, user_id, role)
+SELECT u.team_id, u.id, 'PLAYER'
+FROM users AS u
+JOIN teams AS t ON t.id = u.team_id
+WHERE u.id <> t.captain_id AND u.deleted_at IS NULL;
+
+--bun:split
+
+-- AddUserToTeam her katılımda kapasiteyi azaltıyordu, kapasite artık en fazla üye sayısı
+UPDATE teams AS t
+SET capacity = t.capacity + (SELECT COUNT(*) FROM team_members AS tm WHERE tm.team_id = t.id);
+
+--bun:split
+
+DROP INDEX IF EXISTS users_team_id_idx;
+
+--bun:split
+
+ALTER TABLE users DROP COLUMN IF EXISTS team_id;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS team_invitations (
+    id           BIGSERIAL PRIMARY KEY,
+    team_id      BIGINT      NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    user_id      BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind         VARCHAR(20) NOT NULL CHECK (kind IN ('INVITE', 'REQUEST')),
+    status       VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'ACCEPTED', 'DECLINED', 'CANCELLED', 'EXPIRED')),
+    created_by   BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    responded_by BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+-- Aynı oyuncu için bir takımda tek bekleyen davet veya istek olabilir
+CREATE UNIQUE INDEX IF NOT EXISTS team_invitations_team_user_pending_key ON team_invitations (team_id, user_id) WHERE status = 'PENDING';
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS team_invitations_user_id_idx ON team_invitations (user_id);
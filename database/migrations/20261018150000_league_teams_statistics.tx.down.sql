@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS league_teams_league_id_rank_idx;
+
+--bun:split
+
+ALTER TABLE league_teams
+    DROP COLUMN IF EXISTS played,
+    DROP COLUMN IF EXISTS won,
+    DROP COLUMN IF EXISTS drawn,
+    DROP COLUMN IF EXISTS lost,
+    DROP COLUMN IF EXISTS goals_for,
+    DROP COLUMN IF EXISTS goals_against,
+    DROP COLUMN IF EXISTS goal_difference,
+    DROP COLUMN IF EXISTS form;
@@ -0,0 +1,13 @@
+ALTER TABLE league_teams
+    ADD COLUMN IF NOT EXISTS played          BIGINT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS won             BIGINT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS drawn           BIGINT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS lost            BIGINT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS goals_for       BIGINT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS goals_against   BIGINT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS goal_difference BIGINT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS form            VARCHAR(5) NOT NULL DEFAULT '';
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS league_teams_league_id_rank_idx ON league_teams (league_id, rank);
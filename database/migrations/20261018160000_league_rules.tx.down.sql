@@ -0,0 +1,14 @@
+ALTER TABLE league_teams DROP COLUMN IF EXISTS decided_by;
+
+--bun:split
+
+ALTER TABLE leagues
+    DROP CONSTRAINT IF EXISTS leagues_tiebreakers_check,
+    DROP CONSTRAINT IF EXISTS leagues_points_order_check,
+    DROP COLUMN IF EXISTS points_per_win,
+    DROP COLUMN IF EXISTS points_per_draw,
+    DROP COLUMN IF EXISTS points_per_loss,
+    DROP COLUMN IF EXISTS forfeit_goals_for,
+    DROP COLUMN IF EXISTS forfeit_goals_against,
+    DROP COLUMN IF EXISTS forfeit_points_deduction,
+    DROP COLUMN IF EXISTS tiebreakers;
@@ -0,0 +1,21 @@
+ALTER TABLE leagues
+    ADD COLUMN IF NOT EXISTS points_per_win           BIGINT NOT NULL DEFAULT 3,
+    ADD COLUMN IF NOT EXISTS points_per_draw          BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS points_per_loss          BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS forfeit_goals_for        BIGINT NOT NULL DEFAULT 3,
+    ADD COLUMN IF NOT EXISTS forfeit_goals_against    BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS forfeit_points_deduction BIGINT NOT NULL DEFAULT 0 CHECK (forfeit_points_deduction >= 0),
+    ADD COLUMN IF NOT EXISTS tiebreakers              TEXT[] NOT NULL DEFAULT '{GOAL_DIFFERENCE,GOALS_SCORED}';
+
+--bun:split
+
+ALTER TABLE leagues ADD CONSTRAINT leagues_points_order_check CHECK (points_per_win >= points_per_draw AND points_per_draw >= points_per_loss);
+
+--bun:split
+
+ALTER TABLE leagues ADD CONSTRAINT leagues_tiebreakers_check
+    CHECK (tiebreakers <@ ARRAY ['GOAL_DIFFERENCE', 'GOALS_SCORED', 'HEAD_TO_HEAD', 'FAIR_PLAY', 'DRAWING_LOTS']::TEXT[]);
+
+--bun:split
+
+ALTER TABLE league_teams ADD COLUMN IF NOT EXISTS decided_by VARCHAR(20) NOT NULL DEFAULT '';
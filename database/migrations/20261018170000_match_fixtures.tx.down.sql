@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS matches_league_id_round_idx;
+
+--bun:split
+
+ALTER TABLE matches DROP COLUMN IF EXISTS round;
+
+--bun:split
+
+DELETE FROM matches WHERE game_id IS NULL;
+
+--bun:split
+
+ALTER TABLE matches ALTER COLUMN game_id SET NOT NULL;
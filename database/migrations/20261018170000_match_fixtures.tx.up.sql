@@ -0,0 +1,10 @@
+-- Fikstürden üretilen maçların henüz bir saha rezervasyonu (game) yoktur
+ALTER TABLE matches ALTER COLUMN game_id DROP NOT NULL;
+
+--bun:split
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS round BIGINT CHECK (round > 0);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS matches_league_id_round_idx ON matches (league_id, round) WHERE round IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE games DROP CONSTRAINT IF EXISTS games_field_no_overlap;
@@ -0,0 +1,8 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+--bun:split
+
+-- Aynı sahada zaman aralığı çakışan iki aktif oyun olamaz, iptal ve reddedilen oyunlar sahayı boşaltır
+ALTER TABLE games ADD CONSTRAINT games_field_no_overlap
+    EXCLUDE USING gist (field_id WITH =, tstzrange(start_time, end_time, '[)') WITH &&)
+    WHERE (status NOT IN ('REJECTED', 'CANCELLED'));
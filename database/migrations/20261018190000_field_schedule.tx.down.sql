@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS field_blackouts;
+
+--bun:split
+
+DROP TABLE IF EXISTS field_opening_hours;
+
+--bun:split
+
+ALTER TABLE fields
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS slot_minutes;
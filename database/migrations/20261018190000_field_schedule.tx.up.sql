@@ -0,0 +1,35 @@
+ALTER TABLE fields
+    ADD COLUMN IF NOT EXISTS slot_minutes BIGINT      NOT NULL DEFAULT 60 CHECK (slot_minutes BETWEEN 15 AND 1440),
+    ADD COLUMN IF NOT EXISTS timezone     VARCHAR(64) NOT NULL DEFAULT 'Europe/Istanbul';
+
+--bun:split
+
+-- Açılış ve kapanış saatleri gün başından itibaren dakikadır, 1440 gece yarısı kapanışıdır
+CREATE TABLE IF NOT EXISTS field_opening_hours (
+    id        BIGSERIAL PRIMARY KEY,
+    field_id  BIGINT   NOT NULL REFERENCES fields (id) ON DELETE CASCADE,
+    weekday   SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens_at  BIGINT   NOT NULL CHECK (opens_at >= 0),
+    closes_at BIGINT   NOT NULL CHECK (closes_at <= 1440),
+    CONSTRAINT field_opening_hours_range_check CHECK (closes_at > opens_at)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS field_opening_hours_field_id_idx ON field_opening_hours (field_id, weekday);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS field_blackouts (
+    id         BIGSERIAL PRIMARY KEY,
+    field_id   BIGINT       NOT NULL REFERENCES fields (id) ON DELETE CASCADE,
+    starts_at  TIMESTAMPTZ  NOT NULL,
+    ends_at    TIMESTAMPTZ  NOT NULL,
+    reason     VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT field_blackouts_range_check CHECK (ends_at > starts_at)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS field_blackouts_field_id_starts_at_idx ON field_blackouts (field_id, starts_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS reservation_shares;
+
+--bun:split
+
+DROP TABLE IF EXISTS reservations;
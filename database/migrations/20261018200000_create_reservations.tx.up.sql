@@ -0,0 +1,46 @@
+-- Tutarlar kuruş cinsindendir
+CREATE TABLE IF NOT EXISTS reservations (
+    id              BIGSERIAL PRIMARY KEY,
+    game_id         BIGINT      NOT NULL UNIQUE REFERENCES games (id) ON DELETE CASCADE,
+    field_id        BIGINT      NOT NULL REFERENCES fields (id) ON DELETE CASCADE,
+    user_id         BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status          VARCHAR(20) NOT NULL DEFAULT 'HELD' CHECK (status IN ('HELD', 'CONFIRMED', 'CANCELLED', 'EXPIRED')),
+    start_time      TIMESTAMPTZ NOT NULL,
+    end_time        TIMESTAMPTZ NOT NULL,
+    total_price     BIGINT      NOT NULL CHECK (total_price >= 0),
+    currency        VARCHAR(3)  NOT NULL DEFAULT 'TRY',
+    hold_expires_at TIMESTAMPTZ NOT NULL,
+    payment_id      VARCHAR(255),
+    refund_amount   BIGINT      NOT NULL DEFAULT 0 CHECK (refund_amount >= 0 AND refund_amount <= total_price),
+    refund_id       VARCHAR(255),
+    confirmed_at    TIMESTAMPTZ,
+    cancelled_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    CONSTRAINT reservations_range_check CHECK (end_time > start_time)
+);
+
+--bun:split
+
+-- Süresi dolan tutmaları bulmak için
+CREATE INDEX IF NOT EXISTS reservations_held_expires_idx ON reservations (hold_expires_at) WHERE status = 'HELD';
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS reservations_user_id_idx ON reservations (user_id);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS reservation_shares (
+    id             BIGSERIAL PRIMARY KEY,
+    reservation_id BIGINT      NOT NULL REFERENCES reservations (id) ON DELETE CASCADE,
+    user_id        BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    amount         BIGINT      NOT NULL CHECK (amount >= 0),
+    status         VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'PAID', 'CANCELLED')),
+    refund_amount  BIGINT      NOT NULL DEFAULT 0 CHECK (refund_amount >= 0),
+    paid_at        TIMESTAMPTZ,
+    CONSTRAINT reservation_shares_reservation_user_key UNIQUE (reservation_id, user_id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS reservation_shares_user_id_idx ON reservation_shares (user_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS game_scores;
+
+--bun:split
+
+DROP TABLE IF EXISTS game_status_changes;
@@ -0,0 +1,24 @@
+-- changed_by boşsa durumu sistem değiştirmiştir (süresi dolan rezervasyon, yeniden üretilen fikstür...)
+CREATE TABLE IF NOT EXISTS game_status_changes (
+    id          BIGSERIAL PRIMARY KEY,
+    game_id     BIGINT       NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+    from_status VARCHAR(20)  NOT NULL,
+    to_status   VARCHAR(20)  NOT NULL,
+    changed_by  BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    reason      VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS game_status_changes_game_id_idx ON game_status_changes (game_id, created_at);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS game_scores (
+    id      BIGSERIAL PRIMARY KEY,
+    game_id BIGINT NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+    team_id BIGINT NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    score   BIGINT NOT NULL CHECK (score >= 0),
+    CONSTRAINT game_scores_game_team_key UNIQUE (game_id, team_id)
+);
@@ -0,0 +1,9 @@
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_forfeited_by_check;
+
+--bun:split
+
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_status_check;
+
+--bun:split
+
+ALTER TABLE matches DROP COLUMN IF EXISTS forfeited_by;
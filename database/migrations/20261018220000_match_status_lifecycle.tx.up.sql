@@ -0,0 +1,13 @@
+-- Hükmen biten (FORFEIT, WALKOVER) maçlarda kaybeden takım
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS forfeited_by BIGINT REFERENCES teams (id) ON DELETE RESTRICT;
+
+--bun:split
+
+ALTER TABLE matches ADD CONSTRAINT matches_status_check
+    CHECK (status IN ('SCHEDULED', 'LIVE', 'COMPLETED', 'POSTPONED', 'ABANDONED', 'FORFEIT', 'WALKOVER'));
+
+--bun:split
+
+ALTER TABLE matches ADD CONSTRAINT matches_forfeited_by_check
+    CHECK ((status IN ('FORFEIT', 'WALKOVER')) = (forfeited_by IS NOT NULL)
+        AND (forfeited_by IS NULL OR forfeited_by IN (home_team_id, away_team_id)));
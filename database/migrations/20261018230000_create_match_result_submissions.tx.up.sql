@@ -0,0 +1,11 @@
+-- Kaptanların bildirdiği skorlar, her takımın maç başına tek bildirimi olur ve yeniden bildirince güncellenir
+CREATE TABLE IF NOT EXISTS match_result_submissions (
+    id           BIGSERIAL PRIMARY KEY,
+    match_id     BIGINT      NOT NULL REFERENCES matches (id) ON DELETE CASCADE,
+    team_id      BIGINT      NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    submitted_by BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    home_score   BIGINT      NOT NULL CHECK (home_score >= 0),
+    away_score   BIGINT      NOT NULL CHECK (away_score >= 0),
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    CONSTRAINT match_result_submissions_match_team_key UNIQUE (match_id, team_id)
+);
@@ -0,0 +1,25 @@
+-- Maçtaki goller, kartlar ve oyuncu değişiklikleri. Oyuncular maçın oyunundaki katılımcılardır;
+-- team_id ve user_id sorgularda katılımcıya join yapılmasın diye katılımcıdan kopyalanır.
+CREATE TABLE IF NOT EXISTS match_events (
+    id                        BIGSERIAL PRIMARY KEY,
+    match_id                  BIGINT      NOT NULL REFERENCES matches (id) ON DELETE CASCADE,
+    type                      VARCHAR(20) NOT NULL CHECK (type IN ('GOAL', 'OWN_GOAL', 'YELLOW_CARD', 'RED_CARD', 'SUBSTITUTION')),
+    minute                    BIGINT      NOT NULL CHECK (minute BETWEEN 0 AND 150),
+    participant_id            BIGINT      NOT NULL REFERENCES game_participants (id) ON DELETE CASCADE,
+    team_id                   BIGINT      NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    user_id                   BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    assist_participant_id     BIGINT REFERENCES game_participants (id) ON DELETE SET NULL,
+    substitute_participant_id BIGINT REFERENCES game_participants (id) ON DELETE CASCADE,
+    created_by                BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    created_at                TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    CONSTRAINT match_events_assist_check CHECK (assist_participant_id IS NULL OR type = 'GOAL'),
+    CONSTRAINT match_events_substitute_check CHECK ((type = 'SUBSTITUTION') = (substitute_participant_id IS NOT NULL))
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS match_events_match_id_idx ON match_events (match_id, minute);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS match_events_user_id_idx ON match_events (user_id);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS suspensions;
+
+--bun:split
+
+ALTER TABLE leagues DROP COLUMN IF EXISTS red_card_ban_matches;
+
+--bun:split
+
+ALTER TABLE leagues DROP COLUMN IF EXISTS yellow_cards_per_ban;
@@ -0,0 +1,33 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS yellow_cards_per_ban BIGINT NOT NULL DEFAULT 3 CHECK (yellow_cards_per_ban >= 0);
+
+--bun:split
+
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS red_card_ban_matches BIGINT NOT NULL DEFAULT 1 CHECK (red_card_ban_matches >= 0);
+
+--bun:split
+
+-- Kartlardan gelen cezalar (match_id dolu) kartlar değiştikçe yeniden hesaplanır, MANUAL cezaları lig yönetimi verir
+CREATE TABLE IF NOT EXISTS suspensions (
+    id           BIGSERIAL PRIMARY KEY,
+    league_id    BIGINT       NOT NULL REFERENCES leagues (id) ON DELETE CASCADE,
+    user_id      BIGINT       NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    team_id      BIGINT       NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    match_id     BIGINT REFERENCES matches (id) ON DELETE CASCADE,
+    reason       VARCHAR(20)  NOT NULL CHECK (reason IN ('YELLOW_CARDS', 'RED_CARD', 'MANUAL')),
+    matches      BIGINT       NOT NULL CHECK (matches > 0),
+    starts_after TIMESTAMPTZ  NOT NULL,
+    note         VARCHAR(255) NOT NULL DEFAULT '',
+    created_by   BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT current_timestamp,
+    lifted_at    TIMESTAMPTZ,
+    lifted_by    BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    CONSTRAINT suspensions_card_match_check CHECK ((reason = 'MANUAL') = (match_id IS NULL))
+);
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS suspensions_card_key ON suspensions (league_id, user_id, match_id, reason) WHERE match_id IS NOT NULL;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS suspensions_user_id_idx ON suspensions (user_id, league_id);
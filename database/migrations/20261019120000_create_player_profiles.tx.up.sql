@@ -0,0 +1,10 @@
+-- Oyuncu profilleri, profilini hiç doldurmamış kullanıcıların satırı yoktur
+CREATE TABLE IF NOT EXISTS player_profiles (
+    user_id       BIGINT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    position      VARCHAR(20)  NOT NULL DEFAULT '' CHECK (position IN ('', 'GOALKEEPER', 'DEFENDER', 'MIDFIELDER', 'FORWARD')),
+    strong_foot   VARCHAR(10)  NOT NULL DEFAULT '' CHECK (strong_foot IN ('', 'LEFT', 'RIGHT', 'BOTH')),
+    date_of_birth DATE,
+    avatar_url    VARCHAR(512) NOT NULL DEFAULT '',
+    bio           VARCHAR(500) NOT NULL DEFAULT '',
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT current_timestamp
+);
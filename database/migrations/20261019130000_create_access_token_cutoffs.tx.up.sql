@@ -0,0 +1,13 @@
+-- Bir kullanıcının (session_id sıfırsa tüm oturumlarının) revoked_before anından önce üretilen
+-- access tokenları geçersizdir. Rotasyonla üretilmiş eski tokenların jti'si tutulmadığı için gerekir.
+CREATE TABLE IF NOT EXISTS access_token_cutoffs (
+    user_id        BIGINT      NOT NULL,
+    session_id     UUID        NOT NULL,
+    revoked_before TIMESTAMPTZ NOT NULL,
+    expires_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, session_id)
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS access_token_cutoffs_expires_at_idx ON access_token_cutoffs (expires_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS game_status_changes_unnotified_idx;
+
+--bun:split
+
+ALTER TABLE game_status_changes DROP COLUMN IF EXISTS notified_at;
@@ -0,0 +1,11 @@
+-- İptal ve ret bildirimleri commit'ten sonra bu kolona bakılarak gönderilir
+ALTER TABLE game_status_changes ADD COLUMN IF NOT EXISTS notified_at TIMESTAMPTZ;
+
+--bun:split
+
+-- Bu migration'dan önceki değişiklikler için geriye dönük bildirim gönderilmez
+UPDATE game_status_changes SET notified_at = created_at WHERE notified_at IS NULL;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS game_status_changes_unnotified_idx ON game_status_changes (id) WHERE notified_at IS NULL;
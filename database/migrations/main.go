@@ -1,6 +1,8 @@
 package migrations
 
 import (
+	"embed"
+
 	"github.com/uptrace/bun/migrate"
 )
 
@@ -8,3 +10,13 @@ var (
 	// Migrations provides migration logic for bun
 	Migrations = migrate.NewMigrations()
 )
+
+//go:embed *.sql
+var sqlMigrations embed.FS
+
+func init() {
+	// SQL migrationları dosya adındaki versiyona göre kaydedilir, Go migrationları kendi init'lerinde MustRegister çağırır
+	if err := Migrations.Discover(sqlMigrations); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,148 @@
+package discipline
+
+import (
+	"sort"
+	"time"
+)
+
+type Reason string
+
+const (
+	ReasonYellowCards Reason = "YELLOW_CARDS" // sarı kart birikimi
+	ReasonRedCard     Reason = "RED_CARD"
+	ReasonManual      Reason = "MANUAL" // lig yönetiminin verdiği ceza
+)
+
+// Card bir oyuncunun bir maçta gördüğü karttır
+type Card struct {
+	UserID   uint
+	TeamID   uint
+	MatchID  int64
+	PlayedAt time.Time
+	Red      bool
+}
+
+// Ban oyuncunun, kartı gördüğü takımın StartsAfter'dan sonraki Matches maçında oynayamayacağı cezadır
+type Ban struct {
+	ID          int64 // kaydedilmiş cezalarda sıralamayı sabit tutmak için kullanılır
+	UserID      uint
+	TeamID      uint
+	MatchID     int64 // kartın görüldüğü maç, elle verilen cezalarda 0
+	StartsAfter time.Time
+	Matches     int64
+	Reason      Reason
+}
+
+// TeamMatch takımın ligdeki, ceza çekmeye sayılan bir maçıdır
+type TeamMatch struct {
+	MatchID  int64
+	PlayedAt time.Time
+	Played   bool
+}
+
+// Serving bir cezanın hangi maçlarda çekildiğini veya çekileceğini gösterir
+type Serving struct {
+	Ban Ban
+	// MatchIDs cezanın kapsadığı maçlardır, fikstürde yeterli maç yoksa Matches'tan az olabilir
+	MatchIDs []int64
+	// Remaining cezanın henüz oynanmamış veya fikstüre girmemiş maç sayısıdır
+	Remaining int64
+}
+
+func (s Serving) Active() bool {
+	return s.Remaining > 0
+}
+
+// Covers cezanın verilen maçı kapsayıp kapsamadığını söyler
+func (s Serving) Covers(matchID int64) bool {
+	for _, id := range s.MatchIDs {
+		if id == matchID {
+			return true
+		}
+	}
+	return false
+}
+
+// Compute kartlardan otomatik cezaları hesaplar. Oyuncunun ligdeki her YellowCardsPerBan'ıncı sarı kartı bir maç,
+// her kırmızı kartı RedCardBanMatches maç ceza getirir. Aynı kartlar her zaman aynı cezaları verir, bu yüzden
+// kartlar değiştikçe cezaları baştan hesaplamak güvenlidir.
+func Compute(cards []Card, rules Rules) []Ban {
+	ordered := make([]Card, len(cards))
+	copy(ordered, cards)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if !ordered[i].PlayedAt.Equal(ordered[j].PlayedAt) {
+			return ordered[i].PlayedAt.Before(ordered[j].PlayedAt)
+		}
+		return ordered[i].MatchID < ordered[j].MatchID
+	})
+
+	var bans []Ban
+	yellows := make(map[uint]int64)
+	for _, card := range ordered {
+		ban := Ban{
+			UserID:      card.UserID,
+			TeamID:      card.TeamID,
+			MatchID:     card.MatchID,
+			StartsAfter: card.PlayedAt,
+		}
+
+		if card.Red {
+			if rules.RedCardBanMatches > 0 {
+				ban.Matches = rules.RedCardBanMatches
+				ban.Reason = ReasonRedCard
+				bans = append(bans, ban)
+			}
+			continue
+		}
+
+		yellows[card.UserID]++
+		if rules.YellowCardsPerBan > 0 && yellows[card.UserID]%rules.YellowCardsPerBan == 0 {
+			ban.Matches = 1
+			ban.Reason = ReasonYellowCards
+			bans = append(bans, ban)
+		}
+	}
+	return bans
+}
+
+// Serve bir oyuncunun bir takımdaki cezalarını takımın maçlarına dağıtır. Cezalar sırayla çekilir: her ceza
+// başladığı andan sonraki, önceki cezaların kapsamadığı ilk Matches maçı kapsar. matches takımın ceza çekmeye
+// sayılan maçlarıdır (ertelenen ve yarıda kalan maçlar sayılmaz).
+func Serve(bans []Ban, matches []TeamMatch) []Serving {
+	ordered := make([]Ban, len(bans))
+	copy(ordered, bans)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if !ordered[i].StartsAfter.Equal(ordered[j].StartsAfter) {
+			return ordered[i].StartsAfter.Before(ordered[j].StartsAfter)
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	schedule := make([]TeamMatch, len(matches))
+	copy(schedule, matches)
+	sort.SliceStable(schedule, func(i, j int) bool {
+		return schedule[i].PlayedAt.Before(schedule[j].PlayedAt)
+	})
+
+	covered := make(map[int64]bool, len(schedule))
+	servings := make([]Serving, 0, len(ordered))
+	for _, ban := range ordered {
+		serving := Serving{Ban: ban, MatchIDs: []int64{}, Remaining: ban.Matches}
+		for _, match := range schedule {
+			if int64(len(serving.MatchIDs)) == ban.Matches {
+				break
+			}
+			if covered[match.MatchID] || !match.PlayedAt.After(ban.StartsAfter) {
+				continue
+			}
+
+			covered[match.MatchID] = true
+			serving.MatchIDs = append(serving.MatchIDs, match.MatchID)
+			if match.Played {
+				serving.Remaining--
+			}
+		}
+		servings = append(servings, serving)
+	}
+	return servings
+}
@@ -0,0 +1,172 @@
+package discipline
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var day0 = time.Date(2026, time.March, 1, 19, 0, 0, 0, time.UTC)
+
+// matchTime n'inci haftanın maç zamanıdır, maç id'leri hafta numarasıyla aynıdır
+func matchTime(n int64) time.Time {
+	return day0.AddDate(0, 0, int(7*n))
+}
+
+func yellow(userID uint, matchID int64) Card {
+	return Card{UserID: userID, TeamID: 1, MatchID: matchID, PlayedAt: matchTime(matchID)}
+}
+
+func red(userID uint, matchID int64) Card {
+	card := yellow(userID, matchID)
+	card.Red = true
+	return card
+}
+
+func ban(userID uint, matchID, matches int64, reason Reason) Ban {
+	return Ban{UserID: userID, TeamID: 1, MatchID: matchID, StartsAfter: matchTime(matchID), Matches: matches, Reason: reason}
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		cards []Card
+		want  []Ban
+	}{
+		{
+			name:  "her üçüncü sarı kart bir maç ceza getirir",
+			rules: DefaultRules(),
+			cards: []Card{yellow(7, 1), yellow(7, 2), yellow(7, 3), yellow(7, 4), yellow(7, 5), yellow(7, 6), yellow(7, 7)},
+			want:  []Ban{ban(7, 3, 1, ReasonYellowCards), ban(7, 6, 1, ReasonYellowCards)},
+		},
+		{
+			name:  "sarı kartlar oyuncu başına sayılır",
+			rules: Rules{YellowCardsPerBan: 2},
+			cards: []Card{yellow(7, 1), yellow(8, 1), yellow(8, 2), yellow(7, 3)},
+			want:  []Ban{ban(8, 2, 1, ReasonYellowCards), ban(7, 3, 1, ReasonYellowCards)},
+		},
+		{
+			name:  "kartlar giriş sırasına değil oynanma zamanına göre sayılır",
+			rules: DefaultRules(),
+			cards: []Card{yellow(7, 5), yellow(7, 1), yellow(7, 3)},
+			want:  []Ban{ban(7, 5, 1, ReasonYellowCards)},
+		},
+		{
+			name:  "kırmızı kart sarı kart birikimine sayılmaz",
+			rules: Rules{YellowCardsPerBan: 2, RedCardBanMatches: 2},
+			cards: []Card{yellow(7, 1), red(7, 2), yellow(7, 3)},
+			want:  []Ban{ban(7, 2, 2, ReasonRedCard), ban(7, 3, 1, ReasonYellowCards)},
+		},
+		{
+			name:  "kural 0 ise kart ceza getirmez",
+			rules: Rules{},
+			cards: []Card{yellow(7, 1), yellow(7, 2), yellow(7, 3), red(7, 4)},
+		},
+		{
+			name:  "eşiğin altında ceza yok",
+			rules: DefaultRules(),
+			cards: []Card{yellow(7, 1), yellow(7, 2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compute(tt.cards, tt.rules)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func teamMatch(id int64, played bool) TeamMatch {
+	return TeamMatch{MatchID: id, PlayedAt: matchTime(id), Played: played}
+}
+
+func TestServe(t *testing.T) {
+	type want struct {
+		matchIDs  []int64
+		remaining int64
+	}
+
+	tests := []struct {
+		name    string
+		bans    []Ban
+		matches []TeamMatch
+		want    []want
+	}{
+		{
+			name:    "ceza kartın görüldüğü maçtan sonraki maçta çekilir",
+			bans:    []Ban{ban(7, 1, 1, ReasonYellowCards)},
+			matches: []TeamMatch{teamMatch(1, true), teamMatch(2, true), teamMatch(3, true)},
+			want:    []want{{matchIDs: []int64{2}, remaining: 0}},
+		},
+		{
+			name:    "oynanmamış maçlar cezayı kapsar ama çekilmiş sayılmaz",
+			bans:    []Ban{ban(7, 1, 2, ReasonRedCard)},
+			matches: []TeamMatch{teamMatch(1, true), teamMatch(2, true), teamMatch(3, false), teamMatch(4, false)},
+			want:    []want{{matchIDs: []int64{2, 3}, remaining: 1}},
+		},
+		{
+			// Ertelenen 2. hafta maçı ceza çekmeye sayılmadığı için listede yoktur
+			name:    "ertelenen maç atlanır",
+			bans:    []Ban{ban(7, 1, 1, ReasonRedCard)},
+			matches: []TeamMatch{teamMatch(1, true), teamMatch(3, false)},
+			want:    []want{{matchIDs: []int64{3}, remaining: 1}},
+		},
+		{
+			name: "ertelenen maç yeni tarihinde sırasına girer",
+			bans: []Ban{ban(7, 1, 2, ReasonRedCard)},
+			matches: []TeamMatch{
+				teamMatch(1, true),
+				{MatchID: 2, PlayedAt: matchTime(5), Played: false},
+				teamMatch(3, true),
+				teamMatch(4, false),
+			},
+			want: []want{{matchIDs: []int64{3, 4}, remaining: 1}},
+		},
+		{
+			name:    "fikstürde yeterli maç yoksa kalan ceza beklemede kalır",
+			bans:    []Ban{ban(7, 2, 3, ReasonRedCard)},
+			matches: []TeamMatch{teamMatch(1, true), teamMatch(2, true), teamMatch(3, false)},
+			want:    []want{{matchIDs: []int64{3}, remaining: 3}},
+		},
+		{
+			name:    "aynı anda başlayan cezalar art arda çekilir",
+			bans:    []Ban{ban(7, 1, 1, ReasonYellowCards), ban(7, 1, 2, ReasonRedCard)},
+			matches: []TeamMatch{teamMatch(1, true), teamMatch(2, true), teamMatch(3, true), teamMatch(4, false)},
+			want: []want{
+				{matchIDs: []int64{2}, remaining: 0},
+				{matchIDs: []int64{3, 4}, remaining: 1},
+			},
+		},
+		{
+			name:    "sonraki ceza öncekinin kapsadığı maçı tekrar kullanmaz",
+			bans:    []Ban{ban(7, 2, 1, ReasonYellowCards), ban(7, 1, 2, ReasonRedCard)},
+			matches: []TeamMatch{teamMatch(1, true), teamMatch(2, true), teamMatch(3, true), teamMatch(4, true)},
+			want: []want{
+				{matchIDs: []int64{2, 3}, remaining: 0},
+				{matchIDs: []int64{4}, remaining: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Serve(tt.bans, tt.matches)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d servings, want %d", len(got), len(tt.want))
+			}
+			for i, w := range tt.want {
+				if !reflect.DeepEqual(got[i].MatchIDs, w.matchIDs) || got[i].Remaining != w.remaining {
+					t.Errorf("serving %d: got matches %v remaining %d, want matches %v remaining %d",
+						i+1, got[i].MatchIDs, got[i].Remaining, w.matchIDs, w.remaining)
+				}
+				if got[i].Active() != (w.remaining > 0) {
+					t.Errorf("serving %d: got active %v", i+1, got[i].Active())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package discipline
+
+import "errors"
+
+// Rules bir ligin kart cezası kurallarıdır
+type Rules struct {
+	// YellowCardsPerBan her kaç sarı kartta bir maç ceza verileceğidir, 0 ise sarı kartlar ceza getirmez
+	YellowCardsPerBan int64
+	// RedCardBanMatches kırmızı kartın kaç maç ceza getirdiğidir, 0 ise kırmızı kart ceza getirmez
+	RedCardBanMatches int64
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		YellowCardsPerBan: 3,
+		RedCardBanMatches: 1,
+	}
+}
+
+func (r Rules) Validate() error {
+	var errs []error
+	if r.YellowCardsPerBan < 0 {
+		errs = append(errs, errors.New("sarı kart cezası için kart sayısı negatif olamaz"))
+	}
+	if r.RedCardBanMatches < 0 {
+		errs = append(errs, errors.New("kırmızı kart cezası negatif olamaz"))
+	}
+	return errors.Join(errs...)
+}
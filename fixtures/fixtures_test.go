@@ -0,0 +1,221 @@
+package fixtures
+
+import (
+	"testing"
+	"time"
+)
+
+func teamIDs(n int) []uint {
+	ids := make([]uint, n)
+	for i := range ids {
+		ids[i] = uint(10 + i)
+	}
+	return ids
+}
+
+func TestRoundRobin(t *testing.T) {
+	tests := []struct {
+		name   string
+		teams  int
+		double bool
+	}{
+		{name: "iki takım", teams: 2},
+		{name: "tek sayıda takım", teams: 5},
+		{name: "çift sayıda takım", teams: 6},
+		{name: "tek sayıda takım çift devre", teams: 7, double: true},
+		{name: "çift sayıda takım çift devre", teams: 8, double: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams := teamIDs(tt.teams)
+			rounds, err := RoundRobin(teams, tt.double)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			slots := tt.teams
+			if slots%2 == 1 {
+				slots++
+			}
+			legRounds := slots - 1
+			legs := 1
+			if tt.double {
+				legs = 2
+			}
+			if len(rounds) != legRounds*legs {
+				t.Fatalf("got %d rounds, want %d", len(rounds), legRounds*legs)
+			}
+
+			type pair struct{ a, b uint }
+			home := make(map[uint]int)
+			played := make(map[uint]int)
+			for leg := 0; leg < legs; leg++ {
+				seen := make(map[pair]int)
+				for _, round := range rounds[leg*legRounds : (leg+1)*legRounds] {
+					inRound := make(map[uint]bool)
+					for _, p := range round.Pairings {
+						if inRound[p.HomeTeamID] || inRound[p.AwayTeamID] {
+							t.Fatalf("round %d: a team plays twice", round.Number)
+						}
+						inRound[p.HomeTeamID], inRound[p.AwayTeamID] = true, true
+
+						a, b := p.HomeTeamID, p.AwayTeamID
+						if a > b {
+							a, b = b, a
+						}
+						seen[pair{a, b}]++
+						home[p.HomeTeamID]++
+						played[p.HomeTeamID]++
+						played[p.AwayTeamID]++
+					}
+					if tt.teams%2 == 1 && (round.ByeTeamID == Bye || inRound[round.ByeTeamID]) {
+						t.Errorf("round %d: bye team %d is wrong", round.Number, round.ByeTeamID)
+					}
+				}
+
+				// Her ikili her devrede tam bir kez karşılaşır
+				for i, a := range teams {
+					for _, b := range teams[i+1:] {
+						if got := seen[pair{a, b}]; got != 1 {
+							t.Errorf("leg %d: %d-%d played %d times, want 1", leg+1, a, b, got)
+						}
+					}
+				}
+			}
+
+			// Her takımın iç saha maçı sayısı dış saha maçlarından en fazla bir farklıdır,
+			// çift devrede iç ve dış saha maçları eşittir
+			for _, team := range teams {
+				away := played[team] - home[team]
+				diff := home[team] - away
+				if diff < 0 {
+					diff = -diff
+				}
+				if (tt.double && diff != 0) || diff > 1 {
+					t.Errorf("team %d: %d home, %d away", team, home[team], away)
+				}
+			}
+		})
+	}
+}
+
+func TestRoundRobinDoubleSwapsHomeAndAway(t *testing.T) {
+	rounds, err := RoundRobin(teamIDs(4), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	half := len(rounds) / 2
+	for i := 0; i < half; i++ {
+		first, second := rounds[i], rounds[half+i]
+		for j, p := range first.Pairings {
+			r := second.Pairings[j]
+			if r.HomeTeamID != p.AwayTeamID || r.AwayTeamID != p.HomeTeamID {
+				t.Errorf("round %d: rematch of %d-%d is %d-%d", second.Number, p.HomeTeamID, p.AwayTeamID, r.HomeTeamID, r.AwayTeamID)
+			}
+		}
+	}
+}
+
+func TestRoundRobinNotEnoughTeams(t *testing.T) {
+	if _, err := RoundRobin(teamIDs(1), false); err != ErrNotEnoughTeams {
+		t.Fatalf("got %v, want %v", err, ErrNotEnoughTeams)
+	}
+}
+
+func TestScheduleKeepsWallClockOnDSTDays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// 29 Mart 2026 Pazar ileri, 25 Ekim 2026 Pazar geri saat uygulanır
+	tests := []struct {
+		name string
+		day  time.Time
+	}{
+		{name: "yaz saatine geçiş", day: time.Date(2026, time.March, 29, 0, 0, 0, 0, loc)},
+		{name: "kış saatine geçiş", day: time.Date(2026, time.October, 25, 0, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rounds := []Round{{Number: 1, Pairings: []Pairing{{HomeTeamID: 1, AwayTeamID: 2}, {HomeTeamID: 3, AwayTeamID: 4}}}}
+			fixtures, err := Schedule(rounds, Calendar{
+				Start:         tt.day,
+				End:           tt.day.AddDate(0, 0, 1),
+				Weekdays:      []time.Weekday{time.Sunday},
+				KickoffTimes:  []time.Duration{19 * time.Hour, 21 * time.Hour},
+				Location:      loc,
+				MatchDuration: 90 * time.Minute,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, want := range []int{19, 21} {
+				got := fixtures[i].MatchTime.In(loc)
+				if got.Day() != tt.day.Day() || got.Hour() != want || got.Minute() != 0 {
+					t.Errorf("match %d: got %s, want %02d:00", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleSkipsBusyKickoffs(t *testing.T) {
+	day := time.Date(2026, time.May, 2, 0, 0, 0, 0, time.UTC) // Cumartesi
+	rounds := []Round{
+		{Number: 1, Pairings: []Pairing{{HomeTeamID: 1, AwayTeamID: 2}, {HomeTeamID: 3, AwayTeamID: 4}}},
+		{Number: 2, Pairings: []Pairing{{HomeTeamID: 1, AwayTeamID: 3}}},
+	}
+	calendar := Calendar{
+		Start:         day,
+		End:           day.AddDate(0, 0, 14),
+		Weekdays:      []time.Weekday{time.Saturday},
+		KickoffTimes:  []time.Duration{21 * time.Hour, 18 * time.Hour, 19*time.Hour + 30*time.Minute},
+		Location:      time.UTC,
+		MatchDuration: 90 * time.Minute,
+		// İlk cumartesi 18:00-19:00 arası dolu
+		Busy: []Interval{{Start: day.Add(18 * time.Hour), End: day.Add(19 * time.Hour)}},
+	}
+
+	fixtures, err := Schedule(rounds, calendar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []time.Time{
+		day.Add(19*time.Hour + 30*time.Minute),
+		day.Add(21 * time.Hour),
+		day.AddDate(0, 0, 7).Add(18 * time.Hour),
+	}
+	if len(fixtures) != len(want) {
+		t.Fatalf("got %d fixtures, want %d", len(fixtures), len(want))
+	}
+	for i, f := range fixtures {
+		if !f.MatchTime.Equal(want[i]) {
+			t.Errorf("fixture %d: got %s, want %s", i+1, f.MatchTime, want[i])
+		}
+	}
+}
+
+func TestScheduleNotEnoughMatchdays(t *testing.T) {
+	day := time.Date(2026, time.May, 2, 0, 0, 0, 0, time.UTC)
+	rounds, err := RoundRobin(teamIDs(4), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Schedule(rounds, Calendar{
+		Start:        day,
+		End:          day.AddDate(0, 0, 7),
+		Weekdays:     []time.Weekday{time.Saturday},
+		KickoffTimes: []time.Duration{19 * time.Hour},
+		Location:     time.UTC,
+	})
+	if err != ErrNotEnoughMatchdays {
+		t.Fatalf("got %v, want %v", err, ErrNotEnoughMatchdays)
+	}
+}
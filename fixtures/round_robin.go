@@ -0,0 +1,81 @@
+package fixtures
+
+import (
+	"errors"
+	"sort"
+)
+
+var ErrNotEnoughTeams = errors.New("fikstür için en az iki takım gerekli")
+
+// Bye tek sayıda takım olduğunda o hafta maç yapmayan takımı temsil eder
+const Bye uint = 0
+
+type Pairing struct {
+	HomeTeamID uint
+	AwayTeamID uint
+}
+
+type Round struct {
+	Number   int
+	Pairings []Pairing
+	// ByeTeamID bu hafta boşta kalan takımdır, tüm takımlar oynuyorsa 0
+	ByeTeamID uint
+}
+
+// RoundRobin çember yöntemiyle her takımın diğerleriyle bir kez (double ise iki kez) karşılaştığı haftaları üretir.
+// Takım sayısı tekse her hafta bir takım bay geçer. Ev sahibi, o ana kadar daha az iç saha maçı olan takıma verilir;
+// çift devrede ikinci yarı ilk yarının rövanşıdır. Aynı takımlar her zaman aynı fikstürü verir.
+func RoundRobin(teamIDs []uint, double bool) ([]Round, error) {
+	teams := make([]uint, len(teamIDs))
+	copy(teams, teamIDs)
+	sort.Slice(teams, func(i, j int) bool { return teams[i] < teams[j] })
+
+	if len(teams) < 2 {
+		return nil, ErrNotEnoughTeams
+	}
+	if len(teams)%2 == 1 {
+		teams = append(teams, Bye)
+	}
+
+	n := len(teams)
+	homeGames := make(map[uint]int, n)
+	rounds := make([]Round, 0, n-1)
+
+	for r := 0; r < n-1; r++ {
+		round := Round{Number: r + 1}
+		for i := 0; i < n/2; i++ {
+			a, b := teams[i], teams[n-1-i]
+			if a == Bye || b == Bye {
+				round.ByeTeamID = a + b
+				continue
+			}
+
+			// Ev sahibi dengesini koru, eşitlikte haftaya göre sırayla değiştir
+			home, away := a, b
+			if homeGames[a] > homeGames[b] || (homeGames[a] == homeGames[b] && (r+i)%2 == 1) {
+				home, away = b, a
+			}
+			homeGames[home]++
+			round.Pairings = append(round.Pairings, Pairing{HomeTeamID: home, AwayTeamID: away})
+		}
+		rounds = append(rounds, round)
+
+		// İlk takım sabit kalır, diğerleri saat yönünde bir adım döner
+		last := teams[n-1]
+		copy(teams[2:], teams[1:n-1])
+		teams[1] = last
+	}
+
+	if double {
+		firstLeg := len(rounds)
+		for i := 0; i < firstLeg; i++ {
+			round := Round{Number: firstLeg + i + 1, ByeTeamID: rounds[i].ByeTeamID}
+			for _, p := range rounds[i].Pairings {
+				round.Pairings = append(round.Pairings, Pairing{HomeTeamID: p.AwayTeamID, AwayTeamID: p.HomeTeamID})
+			}
+			rounds = append(rounds, round)
+		}
+	}
+
+	return rounds, nil
+}
@@ -0,0 +1,158 @@
+package fixtures
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var (
+	ErrNotEnoughMatchdays = errors.New("lig tarihleri arasında tüm haftalara yetecek maç günü yok")
+	ErrNoMatchdays        = errors.New("en az bir maç günü ve bir başlama saati gerekli")
+)
+
+// Calendar maçların hangi günlerde ve saatlerde oynanabileceğini tanımlar
+type Calendar struct {
+	Start    time.Time
+	End      time.Time
+	Weekdays []time.Weekday
+	// KickoffTimes gün başından itibaren başlama saatleridir, örn. 19:00 için 19 saat
+	KickoffTimes []time.Duration
+	Location     *time.Location
+	// MatchDuration verilirse maçlar tek bir sahada oynanır: aynı haftanın maçları farklı saatlere konur
+	// ve Busy aralıklarıyla çakışan başlama saatleri atlanır
+	MatchDuration time.Duration
+	Busy          []Interval
+	// Allow verilirse tek sahalı takvimde sadece true dönen aralıklar kullanılır, örn. sahanın açık olduğu saatler
+	Allow func(start, end time.Time) bool
+}
+
+// Interval sahanın dolu olduğu bir zaman aralığıdır, bitiş dahil değildir
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (i Interval) Overlaps(o Interval) bool {
+	return i.Start.Before(o.End) && o.Start.Before(i.End)
+}
+
+type Fixture struct {
+	Round      int
+	HomeTeamID uint
+	AwayTeamID uint
+	MatchTime  time.Time
+}
+
+// Matchdays takvimdeki maç oynanabilecek günleri (gün başı olarak) sırayla döner
+func (c Calendar) Matchdays() []time.Time {
+	loc := c.location()
+	allowed := make(map[time.Weekday]bool, len(c.Weekdays))
+	for _, d := range c.Weekdays {
+		allowed[d] = true
+	}
+
+	start := c.Start.In(loc)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	end := c.End.In(loc)
+
+	var days []time.Time
+	for !day.After(end) {
+		if allowed[day.Weekday()] {
+			days = append(days, day)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return days
+}
+
+// Schedule her haftayı sıradaki maç gününe yerleştirir, haftanın maçları başlama saatlerine sırayla dağıtılır.
+// Başlangıçtan önceye düşen başlama saatleri atlanır. Tek sahalı takvimde haftanın bütün maçlarına
+// yetecek kadar boş saat olmayan günler de atlanır.
+func Schedule(rounds []Round, c Calendar) ([]Fixture, error) {
+	if len(c.Weekdays) == 0 || len(c.KickoffTimes) == 0 {
+		return nil, ErrNoMatchdays
+	}
+
+	kickoffTimes := make([]time.Duration, len(c.KickoffTimes))
+	copy(kickoffTimes, c.KickoffTimes)
+	sort.Slice(kickoffTimes, func(i, j int) bool { return kickoffTimes[i] < kickoffTimes[j] })
+	c.KickoffTimes = kickoffTimes
+
+	busy := make([]Interval, len(c.Busy))
+	copy(busy, c.Busy)
+
+	days := c.Matchdays()
+	var fixtures []Fixture
+	next := 0
+	for _, round := range rounds {
+		if len(round.Pairings) == 0 {
+			continue
+		}
+
+		var kickoffs []time.Time
+		for ; next < len(days) && len(kickoffs) == 0; next++ {
+			kickoffs = c.kickoffs(days[next], busy)
+			if c.MatchDuration > 0 && len(kickoffs) < len(round.Pairings) {
+				kickoffs = nil
+			}
+		}
+		if len(kickoffs) == 0 {
+			return nil, ErrNotEnoughMatchdays
+		}
+
+		for i, p := range round.Pairings {
+			kickoff := kickoffs[i%len(kickoffs)]
+			fixtures = append(fixtures, Fixture{
+				Round:      round.Number,
+				HomeTeamID: p.HomeTeamID,
+				AwayTeamID: p.AwayTeamID,
+				MatchTime:  kickoff,
+			})
+			if c.MatchDuration > 0 {
+				busy = append(busy, Interval{Start: kickoff, End: kickoff.Add(c.MatchDuration)})
+			}
+		}
+	}
+
+	return fixtures, nil
+}
+
+// kickoffs günün takvime uyan başlama saatlerini döner, tek sahalı takvimde dolu aralıklarla çakışanlar
+// ve bir önceki maç bitmeden başlayanlar atlanır
+func (c Calendar) kickoffs(day time.Time, busy []Interval) []time.Time {
+	var times []time.Time
+	y, m, d := day.Date()
+	for _, k := range c.KickoffTimes {
+		// Saat dilimi değişen günlerde de duvar saati korunsun diye gün başına süre eklenmez
+		t := time.Date(y, m, d, int(k/time.Hour), int(k%time.Hour/time.Minute), 0, 0, c.location())
+		if t.Before(c.Start) || t.After(c.End) {
+			continue
+		}
+
+		if c.MatchDuration > 0 {
+			slot := Interval{Start: t, End: t.Add(c.MatchDuration)}
+			if (c.Allow != nil && !c.Allow(slot.Start, slot.End)) || overlapsAny(slot, busy) || (len(times) > 0 && slot.Start.Before(times[len(times)-1].Add(c.MatchDuration))) {
+				continue
+			}
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+func overlapsAny(slot Interval, busy []Interval) bool {
+	for _, b := range busy {
+		if slot.Overlaps(b) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Calendar) location() *time.Location {
+	if c.Location == nil {
+		return time.Local
+	}
+	return c.Location
+}
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
+	"github.com/personal-project/pitch-league/middleware"
 	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/notification"
 	"github.com/personal-project/pitch-league/repository"
 	"github.com/personal-project/pitch-league/utils"
 
@@ -14,31 +19,152 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	verificationCodeLength     = 6
+	verificationCodeTTL        = 15 * time.Minute
+	verificationMaxAttempts    = 5
+	verificationResendCooldown = time.Minute
+)
+
+var (
+	ErrUserNotVerified      = errors.New("hesabınız henüz doğrulanmadı, lütfen size gönderilen kodu girin")
+	ErrUserAlreadyExists    = errors.New("bu e-posta, telefon veya kullanıcı adı zaten kullanılıyor")
+	ErrUserAlreadyVerified  = errors.New("hesap zaten doğrulanmış")
+	ErrInvalidVerification  = errors.New("doğrulama kodu hatalı")
+	ErrVerificationAttempts = errors.New("çok fazla hatalı deneme, lütfen yeni kod isteyin")
+	ErrVerificationCooldown = errors.New("yeni kod istemeden önce lütfen biraz bekleyin")
+)
+
 type AuthHandler struct {
 	authRepository         repository.IAuthRepository
 	userRepository         repository.IUserRepository
+	verificationRepository repository.IVerificationRepository
+	revocationStore        repository.ITokenRevocationStore
+	notifier               notification.Notifier
 	refreshTokenExpireTime time.Duration
 	jwtSecret              string
 }
 
-func NewAuthHandler(ar repository.IAuthRepository, ur repository.IUserRepository, refreshTokenExpireTime time.Duration, jwtSecret string) *AuthHandler {
+func NewAuthHandler(ar repository.IAuthRepository, ur repository.IUserRepository, vr repository.IVerificationRepository, rs repository.ITokenRevocationStore, notifier notification.Notifier, refreshTokenExpireTime time.Duration, jwtSecret string) *AuthHandler {
 	return &AuthHandler{
 		authRepository:         ar,
 		userRepository:         ur,
+		verificationRepository: vr,
+		revocationStore:        rs,
+		notifier:               notifier,
 		refreshTokenExpireTime: refreshTokenExpireTime,
 		jwtSecret:              jwtSecret,
 	}
 }
 
+func (h *AuthHandler) Register(ctx *fiber.Ctx) error {
+	var createModel models.UserCreate
+	if err := ctx.BodyParser(&createModel); err != nil {
+		return errorResult(ctx, err)
+	}
+
+	if err := createModel.Validate(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	user := createModel.ToModel()
+	user.Role = models.UserRoleNormal
+
+	exists, err := h.userRepository.ExistsByIdentity(ctx.Context(), user.Email, user.Phone, user.UserName)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+	if exists {
+		return conflictResult(ctx, ErrUserAlreadyExists)
+	}
+
+	createdUser, err := h.userRepository.Create(ctx.Context(), user)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	// Kullanıcı kaydedildi, kod gönderilemezse istemci /verify/resend ile yeni kod isteyebilir
+	if err := h.sendVerificationCode(ctx.Context(), createdUser); err != nil {
+		slog.Warn("doğrulama kodu gönderilemedi", "user_id", createdUser.ID, "error", err)
+	}
+
+	return successResult(ctx, models.ToUserResponse(createdUser))
+}
+
+func (h *AuthHandler) Verify(ctx *fiber.Ctx) error {
+	var vm models.AuthVerifyVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return errorResult(ctx, err)
+	}
+
+	user, err := h.findUserByIdentity(ctx.Context(), vm.Email, vm.Phone)
+	if err != nil {
+		return badRequestResult(ctx, ErrInvalidVerification)
+	}
+
+	if user.IsVerified() {
+		return badRequestResult(ctx, ErrUserAlreadyVerified)
+	}
+
+	code, err := h.verificationRepository.GetActiveVerificationCode(ctx.Context(), user.ID)
+	if err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	if code.Attempts >= verificationMaxAttempts {
+		return badRequestResult(ctx, ErrVerificationAttempts)
+	}
+
+	if utils.HashCode(vm.Code) != code.CodeHash {
+		if err := h.verificationRepository.IncrementVerificationAttempts(ctx.Context(), code.ID); err != nil {
+			return errorResult(ctx, err)
+		}
+		return badRequestResult(ctx, ErrInvalidVerification)
+	}
+
+	if err := h.verificationRepository.ConsumeVerificationCode(ctx.Context(), code.ID, user.ID); err != nil {
+		return errorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Hesabınız başarıyla doğrulandı")
+}
+
+func (h *AuthHandler) ResendVerification(ctx *fiber.Ctx) error {
+	var vm models.AuthResendVerificationVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return errorResult(ctx, err)
+	}
+
+	user, err := h.findUserByIdentity(ctx.Context(), vm.Email, vm.Phone)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("kullanıcı bulunamadı"))
+	}
+
+	if user.IsVerified() {
+		return badRequestResult(ctx, ErrUserAlreadyVerified)
+	}
+
+	last, err := h.verificationRepository.GetLatestVerificationCode(ctx.Context(), user.ID)
+	if err == nil && time.Since(last.CreatedAt) < verificationResendCooldown {
+		return badRequestResult(ctx, ErrVerificationCooldown)
+	}
+
+	if err := h.sendVerificationCode(ctx.Context(), user); err != nil {
+		return errorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Doğrulama kodu tekrar gönderildi")
+}
+
 func (h *AuthHandler) Login(ctx *fiber.Ctx) error {
 	var vm models.AuthLoginVM
 	if err := ctx.BodyParser(&vm); err != nil {
 		return errorResult(ctx, err)
 	}
 
-	user, err := h.userRepository.GetByEmail(ctx.Context(), utils.CleanEmail(vm.Email))
+	user, err := h.findUserByIdentity(ctx.Context(), vm.Email, vm.Phone)
 	if err != nil {
-		return errorResult(ctx, err)
+		return errorResult(ctx, errors.New("hatalı email veya parola"))
 	}
 
 	ok := utils.CheckPasswordHash(strings.TrimSpace(vm.Password), user.Password)
@@ -46,17 +172,29 @@ func (h *AuthHandler) Login(ctx *fiber.Ctx) error {
 		return errorResult(ctx, errors.New("hatalı email veya parola"))
 	}
 
+	if !user.IsVerified() {
+		return forbiddenResult(ctx, ErrUserNotVerified)
+	}
+
+	// Her login yeni bir oturum başlatır, oturum id'si ilk refresh token id'sidir
 	refreshTokenID := uuid.New()
-	tokens, err := h.authRepository.GenerateTokenPair(user.ID, refreshTokenID, float64(user.Role))
+	tokens, err := h.authRepository.GenerateTokenPair(user.ID, refreshTokenID, refreshTokenID, user.Role)
 	if err != nil {
 		return errorResult(ctx, err)
 	}
 
 	refreshToken := models.AuthRefreshToken{
-		TokenID:   refreshTokenID,
-		UserID:    user.ID,
-		Role:      float64(user.Role),
-		ExpiresAt: time.Now().Add(h.refreshTokenExpireTime),
+		TokenID:         refreshTokenID,
+		FamilyID:        refreshTokenID,
+		UserID:          user.ID,
+		Role:            user.Role,
+		ExpiresAt:       time.Now().Add(h.refreshTokenExpireTime),
+		AccessTokenID:   tokens.AccessTokenID,
+		AccessExpiresAt: tokens.AccessTokenExpiresAt,
+		DeviceName:      strings.TrimSpace(vm.DeviceName),
+		UserAgent:       ctx.Get(fiber.HeaderUserAgent),
+		IP:              ctx.IP(),
+		LastUsedAt:      time.Now(),
 	}
 
 	err = h.authRepository.CreateAuthRefreshToken(ctx.Context(), refreshToken)
@@ -76,22 +214,56 @@ func (h *AuthHandler) RefreshToken(ctx *fiber.Ctx) error {
 		return errorResult(ctx, err)
 	}
 
-	refreshTokenID, userID, role, err := h.authRepository.ParseRefreshToken(vm.RefreshToken)
+	refreshTokenID, userID, _, err := h.authRepository.ParseRefreshToken(vm.RefreshToken)
 	if err != nil {
-		return errorResult(ctx, errors.New("yetkisiz: "+err.Error()))
+		return unauthorizedResult(ctx, errors.New("yetkisiz: "+err.Error()))
 	}
 
 	authRefreshToken, err := h.authRepository.GetAuthRefreshToken(ctx.Context(), refreshTokenID)
 	if err != nil {
-		return errorResult(ctx, err)
+		return unauthorizedResult(ctx, err)
+	}
+
+	// Daha önce kullanılmış bir token tekrar geldiyse çalınmış olabilir, tüm aileyi iptal et
+	if err := repository.CheckRefreshable(authRefreshToken); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenReused) {
+			return h.revokeReusedFamily(ctx, authRefreshToken)
+		}
+		return unauthorizedResult(ctx, err)
 	}
 
-	newTokenPair, err := h.authRepository.GenerateTokenPair(userID, refreshTokenID, role)
+	// Rol değişmiş veya kullanıcı silinmiş olabilir, token'daki role değil güncel kullanıcıya güven
+	user, err := h.userRepository.GetByID(ctx.Context(), userID)
+	if err != nil {
+		if err := h.authRepository.RevokeTokenFamily(ctx.Context(), authRefreshToken.FamilyID); err != nil {
+			return errorResult(ctx, err)
+		}
+		return unauthorizedResult(ctx, errors.New("kullanıcı bulunamadı"))
+	}
+	role := user.Role
+
+	newRefreshTokenID := uuid.New()
+	newTokenPair, err := h.authRepository.GenerateTokenPair(userID, newRefreshTokenID, authRefreshToken.FamilyID, role)
 	if err != nil {
 		return errorResult(ctx, err)
 	}
 
-	err = h.authRepository.UpdateAuthRefreshTokenExpires(ctx.Context(), authRefreshToken.TokenID, time.Now().Add(h.refreshTokenExpireTime))
+	err = h.authRepository.RotateAuthRefreshToken(ctx.Context(), authRefreshToken.TokenID, models.AuthRefreshToken{
+		TokenID:         newRefreshTokenID,
+		FamilyID:        authRefreshToken.FamilyID,
+		UserID:          userID,
+		Role:            role,
+		ExpiresAt:       time.Now().Add(h.refreshTokenExpireTime),
+		AccessTokenID:   newTokenPair.AccessTokenID,
+		AccessExpiresAt: newTokenPair.AccessTokenExpiresAt,
+		DeviceName:      authRefreshToken.DeviceName,
+		UserAgent:       ctx.Get(fiber.HeaderUserAgent),
+		IP:              ctx.IP(),
+		LastUsedAt:      time.Now(),
+	})
+	if errors.Is(err, repository.ErrRefreshTokenReused) {
+		return h.revokeReusedFamily(ctx, authRefreshToken)
+	}
 	if err != nil {
 		return errorResult(ctx, err)
 	}
@@ -121,13 +293,210 @@ func (h *AuthHandler) Logout(ctx *fiber.Ctx) error {
 		return errorResult(ctx, errors.New("geçersiz token"))
 	}
 
-	// Kullanıcının tüm refresh token'larını sil
-	err = h.authRepository.DeleteAuthRefreshToken(ctx.Context(), claims.UserID)
-	if err != nil {
+	// Oturumda rotasyonla üretilmiş eski tokenlar dahil hiçbiri süresi dolana kadar geçerli kalmasın
+	if err := h.revocationStore.RevokeIssuedBefore(ctx.Context(), claims.UserID, claims.SessionID, time.Now()); err != nil {
+		return errorResult(ctx, err)
+	}
+	if claims.ExpiresAt != nil {
+		if err := h.revocationStore.Revoke(ctx.Context(), claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+			return errorResult(ctx, err)
+		}
+	}
+
+	// Sadece bu token'ın ait olduğu oturumu kapat, diğer cihazlar açık kalır
+	err = h.authRepository.RevokeSession(ctx.Context(), claims.UserID, claims.SessionID)
+	if err != nil && !errors.Is(err, repository.ErrSessionNotFound) {
 		return errorResult(ctx, err)
 	}
 
 	return successResult(ctx, "Başarıyla çıkış yapıldı")
 }
 
-// ... diğer handler metodları ve yardımcı fonksiyonlar buraya gelecek ...
+func (h *AuthHandler) GetSessions(ctx *fiber.Ctx) error {
+	claims, ok := middleware.Claims(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	sessions, err := h.authRepository.ListActiveSessions(ctx.Context(), claims.UserID)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	result := make([]models.AuthSessionVM, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, models.AuthSessionVM{}.FromDBModel(session, claims.SessionID))
+	}
+
+	return successResult(ctx, result)
+}
+
+func (h *AuthHandler) RevokeSession(ctx *fiber.Ctx) error {
+	claims, ok := middleware.Claims(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	sessionID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return badRequestResult(ctx, errors.New("geçersiz oturum id"))
+	}
+
+	sessions, err := h.authRepository.ListActiveSessions(ctx.Context(), claims.UserID)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+	for _, session := range sessions {
+		if session.FamilyID != sessionID {
+			continue
+		}
+		if err := revokeSessionAccessTokens(ctx.Context(), h.revocationStore, []models.AuthRefreshToken{session}); err != nil {
+			return errorResult(ctx, err)
+		}
+	}
+
+	err = h.authRepository.RevokeSession(ctx.Context(), claims.UserID, sessionID)
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		return notFoundResult(ctx)
+	}
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Oturum başarıyla kapatıldı")
+}
+
+func (h *AuthHandler) RevokeOtherSessions(ctx *fiber.Ctx) error {
+	claims, ok := middleware.Claims(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	if err := revokeOtherSessions(ctx.Context(), h.authRepository, h.revocationStore, claims); err != nil {
+		return errorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Diğer tüm oturumlar kapatıldı")
+}
+
+func (h *AuthHandler) revokeReusedFamily(ctx *fiber.Ctx, token models.AuthRefreshToken) error {
+	// Ailenin en son verilen access token'ı da çalınmış olabilir
+	sessions, err := h.authRepository.ListActiveSessions(ctx.Context(), token.UserID)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+	for _, session := range sessions {
+		if session.FamilyID != token.FamilyID {
+			continue
+		}
+		if err := revokeSessionAccessTokens(ctx.Context(), h.revocationStore, []models.AuthRefreshToken{session}); err != nil {
+			return errorResult(ctx, err)
+		}
+	}
+
+	if err := h.authRepository.RevokeTokenFamily(ctx.Context(), token.FamilyID); err != nil {
+		return errorResult(ctx, err)
+	}
+	return unauthorizedResult(ctx, repository.ErrRefreshTokenReused)
+}
+
+// revokeSessionAccessTokens oturumlarda şimdiye kadar üretilen tüm access tokenları iptal eder. Rotasyondan önce
+// üretilenler oturumun kesme zamanıyla, kesme zamanıyla aynı saniyede üretilmiş olabilen son token jti'siyle iptal edilir.
+func revokeSessionAccessTokens(ctx context.Context, store repository.ITokenRevocationStore, sessions []models.AuthRefreshToken) error {
+	now := time.Now()
+	for _, session := range sessions {
+		if err := store.RevokeIssuedBefore(ctx, session.UserID, session.FamilyID, now); err != nil {
+			return err
+		}
+		if session.AccessTokenID == uuid.Nil {
+			continue
+		}
+		if err := store.Revoke(ctx, session.AccessTokenID, session.UserID, session.AccessExpiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revokeOtherSessions isteği yapan oturum dışındaki tüm oturumları ve access tokenlarını kapatır
+func revokeOtherSessions(ctx context.Context, authRepository repository.IAuthRepository, store repository.ITokenRevocationStore, claims *models.AccessTokenClaims) error {
+	sessions, err := authRepository.ListActiveSessions(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	var others []models.AuthRefreshToken
+	for _, session := range sessions {
+		if session.FamilyID != claims.SessionID {
+			others = append(others, session)
+		}
+	}
+	if err := revokeSessionAccessTokens(ctx, store, others); err != nil {
+		return err
+	}
+
+	return authRepository.RevokeOtherSessions(ctx, claims.UserID, claims.SessionID)
+}
+
+// revokeUserAccessTokens kullanıcının o ana kadar üretilmiş tüm access tokenlarını iptal eder
+func revokeUserAccessTokens(ctx context.Context, authRepository repository.IAuthRepository, store repository.ITokenRevocationStore, userID int64) error {
+	if err := store.RevokeIssuedBefore(ctx, userID, uuid.Nil, time.Now()); err != nil {
+		return err
+	}
+
+	sessions, err := authRepository.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.AccessTokenID == uuid.Nil {
+			continue
+		}
+		if err := store.Revoke(ctx, session.AccessTokenID, session.UserID, session.AccessExpiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendVerificationCode önceki kodları geçersiz kılar, yeni bir kod üretip kullanıcıya gönderir
+func (h *AuthHandler) sendVerificationCode(ctx context.Context, user models.User) error {
+	code, err := utils.GenerateNumericCode(verificationCodeLength)
+	if err != nil {
+		return err
+	}
+
+	if err := h.verificationRepository.InvalidateVerificationCodes(ctx, user.ID); err != nil {
+		return err
+	}
+
+	channel, target := user.VerificationTarget()
+	err = h.verificationRepository.CreateVerificationCode(ctx, models.VerificationCode{
+		UserID:    user.ID,
+		Channel:   channel,
+		Target:    target,
+		CodeHash:  utils.HashCode(code),
+		ExpiresAt: time.Now().Add(verificationCodeTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return h.notifier.Send(ctx, notification.Message{
+		Channel: string(channel),
+		To:      target,
+		Subject: "Pitch League doğrulama kodu",
+		Body:    fmt.Sprintf("Doğrulama kodunuz: %s (%d dakika geçerlidir)", code, int(verificationCodeTTL.Minutes())),
+	})
+}
+
+// findUserByIdentity kullanıcıyı e-posta, yoksa telefon numarası ile bulur
+func (h *AuthHandler) findUserByIdentity(ctx context.Context, email, phone string) (models.User, error) {
+	if email != "" {
+		return h.userRepository.GetByEmail(ctx, utils.CleanEmail(email))
+	}
+	if phone != "" {
+		return h.userRepository.GetByPhone(ctx, utils.CleanPhone(phone))
+	}
+	return models.User{}, errors.New("e-posta veya telefon zorunludur")
+}
@@ -39,6 +39,34 @@ func errorResult(c *fiber.Ctx, err error) error {
 	})
 }
 
+func badRequestResult(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
+
+func unauthorizedResult(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
+
+func forbiddenResult(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
+
+func conflictResult(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
+
 func notFoundResult(c *fiber.Ctx) error {
 	return c.Status(404).JSON(fiber.Map{
 		"success": false,
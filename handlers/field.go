@@ -1,13 +1,26 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/availability"
+	"github.com/personal-project/pitch-league/middleware"
 	"github.com/personal-project/pitch-league/models"
 	"github.com/personal-project/pitch-league/repository"
 )
 
+const (
+	// defaultAvailabilityDays from/to verilmediğinde gösterilen gün sayısıdır
+	defaultAvailabilityDays = 7
+	// maxAvailabilityDays tek istekte sorgulanabilecek en uzun aralıktır
+	maxAvailabilityDays = 31
+)
+
+var ErrInvalidBlackout = errors.New("kapalı dönemin bitişi başlangıcından sonra olmalı")
+
 type FieldHandler struct {
 	BaseHandler[models.Field]
 	fieldRepository repository.IFieldRepository
@@ -97,6 +110,11 @@ func (h FieldHandler) UpdateFieldByID(ctx *fiber.Ctx) error {
 		return errorResult(ctx, err)
 	}
 
+	// fields:manage yetkisi olmayan saha sahibi sahayı başkasına devredemez
+	if claims, ok := middleware.Claims(ctx); !ok || !claims.Role.HasPermission(models.PermissionFieldsManage) {
+		vm.OwnerID = field.OwnerID
+	}
+
 	updatedField := vm.ToDBModel(*field)
 	if err := h.fieldRepository.UpdateField(ctx.Context(), updatedField); err != nil {
 		return errorResult(ctx, err)
@@ -104,3 +122,207 @@ func (h FieldHandler) UpdateFieldByID(ctx *fiber.Ctx) error {
 
 	return successResult(ctx, "Halısaha başarıyla güncellendi!")
 }
+
+// GetFieldSchedule sahanın slot süresini, saat dilimini ve haftalık açılış saatlerini getirir
+func (h FieldHandler) GetFieldSchedule(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz saha id"))
+	}
+
+	field, err := h.fieldRepository.GetByFieldID(ctx.Context(), id)
+	if err != nil {
+		return notFoundResult(ctx)
+	}
+
+	hours, err := h.fieldRepository.GetFieldOpeningHours(ctx.Context(), uint(id))
+	if err != nil {
+		return errorResult(ctx, errors.New("Açılış saatleri getirilirken bir hata oluştu"))
+	}
+
+	vm := models.FieldScheduleVM{}
+	return successResult(ctx, vm.FromDBModel(*field, hours))
+}
+
+// UpdateFieldSchedule sahanın slot süresini, saat dilimini ve haftalık açılış saatlerini değiştirir
+func (h FieldHandler) UpdateFieldSchedule(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz saha id"))
+	}
+
+	field, err := h.fieldRepository.GetByFieldID(ctx.Context(), id)
+	if err != nil {
+		return notFoundResult(ctx)
+	}
+
+	var vm models.FieldScheduleVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	updatedField, hours, err := vm.ToDBModel(*field)
+	if err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	if err := h.fieldRepository.UpdateFieldSchedule(ctx.Context(), updatedField, hours); err != nil {
+		return errorResult(ctx, errors.New("Açılış saatleri güncellenirken bir hata oluştu"))
+	}
+
+	return successResult(ctx, models.FieldScheduleVM{}.FromDBModel(updatedField, hours))
+}
+
+// GetFieldBlackouts sahanın bugünden sonraki kapalı dönemlerini getirir
+func (h FieldHandler) GetFieldBlackouts(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz saha id"))
+	}
+
+	now := time.Now()
+	blackouts, err := h.fieldRepository.GetFieldBlackouts(ctx.Context(), uint(id), now, now.AddDate(10, 0, 0))
+	if err != nil {
+		return errorResult(ctx, errors.New("Kapalı dönemler getirilirken bir hata oluştu"))
+	}
+
+	return successResult(ctx, blackouts)
+}
+
+// CreateFieldBlackout sahayı bakım vb. sebeplerle bir dönem için kapatır, o dönemde oyun varsa 409 döner
+func (h FieldHandler) CreateFieldBlackout(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz saha id"))
+	}
+
+	var vm models.FieldBlackoutCreateVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	blackout := vm.ToDBModel(models.FieldBlackout{FieldID: uint(id)})
+	if !blackout.EndsAt.After(blackout.StartsAt) {
+		return badRequestResult(ctx, ErrInvalidBlackout)
+	}
+
+	if err := h.fieldRepository.CreateFieldBlackout(ctx.Context(), &blackout); err != nil {
+		if errors.Is(err, repository.ErrBlackoutHasGames) {
+			return conflictResult(ctx, err)
+		}
+		return errorResult(ctx, errors.New("Kapalı dönem eklenirken bir hata oluştu"))
+	}
+
+	return successResult(ctx, blackout)
+}
+
+func (h FieldHandler) DeleteFieldBlackout(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz saha id"))
+	}
+
+	blackoutID, err := strconv.ParseInt(ctx.Params("blackoutID"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz kapalı dönem id"))
+	}
+
+	if err := h.fieldRepository.DeleteFieldBlackout(ctx.Context(), uint(id), blackoutID); err != nil {
+		if errors.Is(err, repository.ErrFieldBlackoutNotFound) {
+			return notFoundResult(ctx)
+		}
+		return errorResult(ctx, errors.New("Kapalı dönem silinirken bir hata oluştu"))
+	}
+
+	return successResult(ctx, "Kapalı dönem silindi!")
+}
+
+// GetFieldAvailability sahanın [from, to) aralığındaki boş slotlarını açılış saatleri, kapalı dönemler ve
+// mevcut oyunlara göre hesaplar. from ve to RFC3339 ya da sahanın saat diliminde "2006-01-02" olabilir.
+func (h FieldHandler) GetFieldAvailability(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz saha id"))
+	}
+
+	field, err := h.fieldRepository.GetByFieldID(ctx.Context(), id)
+	if err != nil {
+		return notFoundResult(ctx)
+	}
+
+	hours, err := h.fieldRepository.GetFieldOpeningHours(ctx.Context(), uint(id))
+	if err != nil {
+		return errorResult(ctx, errors.New("Açılış saatleri getirilirken bir hata oluştu"))
+	}
+	schedule := field.Schedule(hours)
+
+	now := time.Now()
+	from, err := parseAvailabilityTime(ctx.Query("from"), schedule.Location, now)
+	if err != nil {
+		return badRequestResult(ctx, err)
+	}
+	to, err := parseAvailabilityTime(ctx.Query("to"), schedule.Location, from.AddDate(0, 0, defaultAvailabilityDays))
+	if err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if !to.After(from) {
+		return badRequestResult(ctx, errors.New("to, from'dan sonra olmalı"))
+	}
+	if to.Sub(from) > maxAvailabilityDays*24*time.Hour {
+		return badRequestResult(ctx, errors.New("en fazla 31 günlük aralık sorgulanabilir"))
+	}
+
+	result := models.FieldAvailabilityVM{
+		FieldID:     uint(field.ID),
+		Timezone:    field.Timezone,
+		SlotMinutes: field.SlotMinutes,
+		From:        from,
+		To:          to,
+		Slots:       []availability.Slot{},
+	}
+
+	// Geçmiş slotlar ve rezervasyona kapalı sahalar boş sayılmaz
+	if now.After(from) {
+		from = now
+	}
+	if !field.Available || !to.After(from) {
+		return successResult(ctx, result)
+	}
+
+	games, err := h.fieldRepository.GetFieldGames(ctx.Context(), uint(id), from, to)
+	if err != nil {
+		return errorResult(ctx, errors.New("Saha rezervasyonları getirilirken bir hata oluştu"))
+	}
+	blackouts, err := h.fieldRepository.GetFieldBlackouts(ctx.Context(), uint(id), from, to)
+	if err != nil {
+		return errorResult(ctx, errors.New("Kapalı dönemler getirilirken bir hata oluştu"))
+	}
+
+	busy := make([]availability.Slot, 0, len(games)+len(blackouts))
+	for _, game := range games {
+		busy = append(busy, availability.Slot{Start: game.StartTime, End: game.EndTime})
+	}
+	for _, blackout := range blackouts {
+		busy = append(busy, blackout.Slot())
+	}
+
+	if free := availability.Free(schedule, from, to, busy); free != nil {
+		result.Slots = free
+	}
+
+	return successResult(ctx, result)
+}
+
+// parseAvailabilityTime sorgudaki zamanı çözer, boşsa fallback döner
+func parseAvailabilityTime(value string, loc *time.Location, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("geçersiz tarih: " + value)
+}
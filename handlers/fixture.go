@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/fixtures"
+	"github.com/personal-project/pitch-league/middleware"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/repository"
+)
+
+var (
+	ErrNoUnplayedRounds      = errors.New("ligde yeniden üretilecek oynanmamış hafta kalmadı")
+	errFixtureLeagueNotFound = errors.New("lig bulunamadı")
+)
+
+type FixtureHandler struct {
+	fixtureRepository repository.IFixtureRepository
+	leagueRepository  repository.ILeagueRepository
+	fieldRepository   repository.IFieldRepository
+}
+
+func NewFixtureHandler(r repository.IFixtureRepository, lr repository.ILeagueRepository, fr repository.IFieldRepository) FixtureHandler {
+	return FixtureHandler{
+		fixtureRepository: r,
+		leagueRepository:  lr,
+		fieldRepository:   fr,
+	}
+}
+
+// fixturePlan bir fikstür isteğinin üretilmiş halidir
+type fixturePlan struct {
+	leagueID  uint
+	teamIDs   []uint
+	rounds    []fixtures.Round
+	scheduled []fixtures.Fixture
+}
+
+// PreviewFixtures fikstürü kaydetmeden üretir ve haftalara göre döner
+func (h FixtureHandler) PreviewFixtures(ctx *fiber.Ctx) error {
+	league, vm, calendar, err := h.readFixtureRequest(ctx)
+	if err != nil {
+		if errors.Is(err, errFixtureLeagueNotFound) {
+			return notFoundResult(ctx)
+		}
+		return badRequestResult(ctx, err)
+	}
+
+	plan, err := h.plan(ctx.Context(), uint(league.ID), vm, calendar, 1)
+	if err != nil {
+		return fixtureErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, models.FixtureRoundsFromSchedule(plan.rounds, plan.scheduled))
+}
+
+// CreateFixtures fikstürü üretip planlanmış maçlar olarak kaydeder, ligin zaten fikstürü varsa 409 döner
+func (h FixtureHandler) CreateFixtures(ctx *fiber.Ctx) error {
+	league, vm, calendar, err := h.readFixtureRequest(ctx)
+	if err != nil {
+		if errors.Is(err, errFixtureLeagueNotFound) {
+			return notFoundResult(ctx)
+		}
+		return badRequestResult(ctx, err)
+	}
+
+	plan, err := h.plan(ctx.Context(), uint(league.ID), vm, calendar, 1)
+	if err != nil {
+		return fixtureErrorResult(ctx, err)
+	}
+
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	// Saha seçildiyse her maç için saha isteği yapan yönetici adına ayrılır
+	matches := models.FixtureMatches(plan.leagueID, plan.scheduled)
+	if err := h.fixtureRepository.CreateFixtures(ctx.Context(), plan.leagueID, plan.teamIDs, matches, vm.Booking(uint(userID))); err != nil {
+		return fixtureErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, models.FixtureRoundsFromSchedule(plan.rounds, plan.scheduled))
+}
+
+// RegenerateFixtures sadece oynanmamış haftaları yeniden üretir. Eşleşmeler aynı takımlar için değişmez,
+// kalan haftalar son oynanan maçtan sonraki ilk uygun günden itibaren yeni takvime yerleştirilir.
+// Oynanmış hafta varken ligin takımları fikstürdekilerden farklıysa eşleşmeler tutmayacağı için 409 döner.
+func (h FixtureHandler) RegenerateFixtures(ctx *fiber.Ctx) error {
+	league, vm, calendar, err := h.readFixtureRequest(ctx)
+	if err != nil {
+		if errors.Is(err, errFixtureLeagueNotFound) {
+			return notFoundResult(ctx)
+		}
+		return badRequestResult(ctx, err)
+	}
+
+	lastRound, lastPlayedAt, err := h.fixtureRepository.GetLastPlayedRound(ctx.Context(), uint(league.ID))
+	if err != nil {
+		return errorResult(ctx, errors.New("Fikstür getirilirken bir hata oluştu"))
+	}
+
+	if !lastPlayedAt.IsZero() {
+		played := lastPlayedAt.In(calendar.Location)
+		nextDay := time.Date(played.Year(), played.Month(), played.Day()+1, 0, 0, 0, 0, calendar.Location)
+		if nextDay.After(calendar.Start) {
+			calendar.Start = nextDay
+		}
+	}
+
+	plan, err := h.plan(ctx.Context(), uint(league.ID), vm, calendar, int(lastRound)+1)
+	if err != nil {
+		return fixtureErrorResult(ctx, err)
+	}
+
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	matches := models.FixtureMatches(plan.leagueID, plan.scheduled)
+	if err := h.fixtureRepository.ReplaceUnplayedFixtures(ctx.Context(), plan.leagueID, lastRound+1, plan.teamIDs, matches, vm.Booking(uint(userID))); err != nil {
+		return fixtureErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, models.FixtureRoundsFromSchedule(plan.rounds, plan.scheduled))
+}
+
+// readFixtureRequest ligi ve istek gövdesini okuyup takvimi kurar. Geçmişe maç yazılmaması için takvim en erken şu andan başlar.
+func (h FixtureHandler) readFixtureRequest(ctx *fiber.Ctx) (*models.League, models.FixtureGenerateVM, fixtures.Calendar, error) {
+	var vm models.FixtureGenerateVM
+
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return nil, vm, fixtures.Calendar{}, errors.New("Geçersiz lig id")
+	}
+
+	league, err := h.leagueRepository.GetByLeagueID(ctx.Context(), id)
+	if err != nil {
+		return nil, vm, fixtures.Calendar{}, errFixtureLeagueNotFound
+	}
+
+	if err := ctx.BodyParser(&vm); err != nil {
+		return nil, vm, fixtures.Calendar{}, err
+	}
+
+	calendar, err := vm.Calendar(*league)
+	if err != nil {
+		return nil, vm, fixtures.Calendar{}, err
+	}
+	if now := time.Now(); now.After(calendar.Start) {
+		calendar.Start = now
+	}
+
+	return league, vm, calendar, nil
+}
+
+// plan ligin takımlarından fromRound ve sonrasındaki haftaları üretip takvime yerleştirir
+func (h FixtureHandler) plan(ctx context.Context, leagueID uint, vm models.FixtureGenerateVM, calendar fixtures.Calendar, fromRound int) (fixturePlan, error) {
+	teamIDs, err := h.fixtureRepository.GetLeagueTeamIDs(ctx, leagueID)
+	if err != nil {
+		return fixturePlan{}, err
+	}
+
+	// Tek sahalı fikstürde sahanın kapalı olduğu saatler, kapalı dönemleri ve dolu saatleri takvimden düşülür
+	if vm.FieldID != 0 {
+		field, err := h.fieldRepository.GetByFieldID(ctx, int64(vm.FieldID))
+		if err != nil {
+			return fixturePlan{}, err
+		}
+		hours, err := h.fieldRepository.GetFieldOpeningHours(ctx, vm.FieldID)
+		if err != nil {
+			return fixturePlan{}, err
+		}
+		calendar.Allow = field.Schedule(hours).Contains
+
+		blackouts, err := h.fieldRepository.GetFieldBlackouts(ctx, vm.FieldID, calendar.Start, calendar.End)
+		if err != nil {
+			return fixturePlan{}, err
+		}
+		for _, blackout := range blackouts {
+			calendar.Busy = append(calendar.Busy, fixtures.Interval{Start: blackout.StartsAt, End: blackout.EndsAt})
+		}
+
+		bookings, err := h.fixtureRepository.GetFieldBookings(ctx, vm.FieldID, calendar.Start, calendar.End, leagueID, int64(fromRound))
+		if err != nil {
+			return fixturePlan{}, err
+		}
+		for _, game := range bookings {
+			calendar.Busy = append(calendar.Busy, fixtures.Interval{Start: game.StartTime, End: game.EndTime})
+		}
+	}
+
+	rounds, err := fixtures.RoundRobin(teamIDs, vm.DoubleRoundRobin)
+	if err != nil {
+		return fixturePlan{}, err
+	}
+
+	if fromRound > len(rounds) {
+		return fixturePlan{}, ErrNoUnplayedRounds
+	}
+	rounds = rounds[fromRound-1:]
+
+	scheduled, err := fixtures.Schedule(rounds, calendar)
+	if err != nil {
+		return fixturePlan{}, err
+	}
+
+	return fixturePlan{leagueID: leagueID, teamIDs: teamIDs, rounds: rounds, scheduled: scheduled}, nil
+}
+
+func fixtureErrorResult(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrFixturesExist),
+		errors.Is(err, repository.ErrFixtureRoundPlayed),
+		errors.Is(err, ErrNoUnplayedRounds),
+		errors.Is(err, repository.ErrFixtureTeamsChanged),
+		errors.Is(err, repository.ErrLeagueTeamsChanged),
+		errors.Is(err, repository.ErrFieldDoubleBooked),
+		errors.Is(err, repository.ErrFieldUnavailable),
+		errors.Is(err, repository.ErrFieldClosed),
+		errors.Is(err, repository.ErrFieldBlackout),
+		errors.Is(err, repository.ErrTeamScheduleClash):
+		return conflictResult(ctx, err)
+	case errors.Is(err, fixtures.ErrNotEnoughTeams),
+		errors.Is(err, fixtures.ErrNotEnoughMatchdays),
+		errors.Is(err, fixtures.ErrNoMatchdays):
+		return badRequestResult(ctx, err)
+	}
+	return errorResult(ctx, errors.New("Fikstür oluşturulurken bir hata oluştu"))
+}
@@ -1,24 +1,39 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/middleware"
 	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/notification"
 	"github.com/personal-project/pitch-league/repository"
 )
 
+var (
+	ErrInvalidGameTime    = errors.New("oyunun bitiş saati başlangıçtan sonra olmalı")
+	ErrInvalidGameStatus  = errors.New("geçersiz oyun durumu")
+	ErrGameStatusNotAllow = errors.New("oyunun durumunu bu şekilde değiştirme yetkiniz yok")
+)
+
 type GameHandler struct {
 	BaseHandler[models.Game]
 	gameRepository repository.IGameRepository
+	notifier       notification.Notifier
 }
 
-func NewGameHandler(r repository.IGameRepository) GameHandler {
+func NewGameHandler(r repository.IGameRepository, notifier notification.Notifier) GameHandler {
 	return GameHandler{
 		BaseHandler: BaseHandler[models.Game]{
 			baseRepository: r,
 		},
 		gameRepository: r,
+		notifier:       notifier,
 	}
 }
 
@@ -28,9 +43,34 @@ func (h GameHandler) CreateGame(ctx *fiber.Ctx) error {
 		return errorResult(ctx, err)
 	}
 
+	return h.createGame(ctx, vm)
+}
+
+// HostGame isteği yapan kullanıcının host olduğu bir oyun açar, body'deki host_id yok sayılır
+func (h GameHandler) HostGame(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	var vm models.GameCreateVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	vm.HostID = uint(userID)
+
+	return h.createGame(ctx, vm)
+}
+
+func (h GameHandler) createGame(ctx *fiber.Ctx, vm models.GameCreateVM) error {
 	game := vm.ToDBModel(models.Game{})
+	if !game.EndTime.After(game.StartTime) {
+		return badRequestResult(ctx, ErrInvalidGameTime)
+	}
+
+	// Aynı sahada çakışan bir oyun varsa 409 döner
 	if err := h.gameRepository.CreateGame(ctx.Context(), game); err != nil {
-		return errorResult(ctx, err)
+		return scheduleErrorResult(ctx, err, err)
 	}
 
 	return successResult(ctx, "Game başarıyla eklendi!")
@@ -97,10 +137,210 @@ func (h GameHandler) UpdateGameByID(ctx *fiber.Ctx) error {
 		return errorResult(ctx, err)
 	}
 
+	// games:manage yetkisi olmayan host oyunu başkasına devredemez
+	if claims, ok := middleware.Claims(ctx); !ok || !claims.Role.HasPermission(models.PermissionGamesManage) {
+		vm.HostID = game.HostID
+	}
+
 	updatedGame := vm.ToDBModel(*game)
+	if !updatedGame.EndTime.After(updatedGame.StartTime) {
+		return badRequestResult(ctx, ErrInvalidGameTime)
+	}
+
 	if err := h.gameRepository.UpdateGame(ctx.Context(), updatedGame); err != nil {
-		return errorResult(ctx, err)
+		return gameErrorResult(ctx, err)
 	}
 
 	return successResult(ctx, "Game başarıyla güncellendi!")
 }
+
+// CancelGame oyunu iptal eder, ChangeGameStatus'un kısayoludur
+func (h GameHandler) CancelGame(ctx *fiber.Ctx) error {
+	return h.changeGameStatus(ctx, models.GameStatusUpdateVM{Status: models.GameStatusCancelled})
+}
+
+// ChangeGameStatus oyunu izin verilen bir sonraki duruma geçirir. Oyunu saha sahibi kabul eder veya reddeder,
+// host iptal eder veya bitirir; saha sahibi de kendi sahasındaki oyunu iptal edebilir.
+func (h GameHandler) ChangeGameStatus(ctx *fiber.Ctx) error {
+	var vm models.GameStatusUpdateVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if !vm.Status.IsValid() {
+		return badRequestResult(ctx, ErrInvalidGameStatus)
+	}
+
+	return h.changeGameStatus(ctx, vm)
+}
+
+// GetGameStatusHistory oyunun durum değişikliklerini eskiden yeniye getirir
+func (h GameHandler) GetGameStatusHistory(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	if _, err := h.gameRepository.GetByGameID(ctx.Context(), id); err != nil {
+		return gameErrorResult(ctx, err)
+	}
+
+	changes, err := h.gameRepository.GetGameStatusHistory(ctx.Context(), id)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+	if changes == nil {
+		changes = []models.GameStatusChange{}
+	}
+
+	return successResult(ctx, changes)
+}
+
+func (h GameHandler) changeGameStatus(ctx *fiber.Ctx, vm models.GameStatusUpdateVM) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	claims, ok := middleware.Claims(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	game, err := h.gameRepository.GetByGameID(ctx.Context(), id)
+	if err != nil {
+		return gameErrorResult(ctx, err)
+	}
+	if !canChangeGameStatus(claims, *game, vm.Status) {
+		return forbiddenResult(ctx, ErrGameStatusNotAllow)
+	}
+
+	change := models.GameStatusChange{
+		ToStatus:  vm.Status,
+		ChangedBy: &claims.UserID,
+		Reason:    vm.Reason,
+	}
+	// İptal ve ret bildirimlerini StartNotificationSweeper commit'ten sonra gönderir
+	if _, err := h.gameRepository.ChangeGameStatus(ctx.Context(), id, change, vm.ToDBModel(id)); err != nil {
+		return gameErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, fmt.Sprintf("Oyunun durumu %s olarak güncellendi!", vm.Status))
+}
+
+// gameNotificationBatch bir taramada alınan en fazla bildirim sayısıdır, kalanlar bir sonraki taramaya kalır
+const gameNotificationBatch = 100
+
+// StartNotificationSweeper iptal edilen veya reddedilen oyunların oyuncularına belirli aralıklarla haber verir, ctx kapanınca durur.
+// Değişiklikler commit edildikten sonra okunduğu için HTTP isteğiyle, süresi dolan rezervasyonla, ertelenen maçla veya
+// yeniden üretilen fikstürle iptal edilen her oyun aynı şekilde bildirilir.
+func (h GameHandler) StartNotificationSweeper(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.sendGameNotifications(ctx)
+			}
+		}
+	}()
+}
+
+func (h GameHandler) sendGameNotifications(ctx context.Context) {
+	for {
+		notifications, err := h.gameRepository.ClaimGameStatusNotifications(ctx, gameNotificationBatch)
+		if err != nil {
+			slog.Warn("oyun bildirimleri alınamadı", "error", err)
+			return
+		}
+		for _, n := range notifications {
+			h.notifyParticipants(ctx, n)
+		}
+		if len(notifications) < gameNotificationBatch {
+			return
+		}
+	}
+}
+
+// notifyParticipants iptal edilen veya reddedilen oyunun oyuncularına haber verir. Bildirim en fazla bir kez denenir,
+// gönderilemeyen mesajlar loglanır.
+func (h GameHandler) notifyParticipants(ctx context.Context, n models.GameStatusNotification) {
+	subject, verb := "Oyun iptal edildi", "iptal edildi"
+	if n.Change.ToStatus == models.GameStatusRejected {
+		subject, verb = "Oyun reddedildi", "saha tarafından reddedildi"
+	}
+	body := fmt.Sprintf("%s tarihli oyununuz %s.", n.Game.StartTime.Format("02.01.2006 15:04"), verb)
+	if n.Change.Reason != "" {
+		body += " Sebep: " + n.Change.Reason
+	}
+
+	for _, user := range n.Users {
+		channel, target := user.VerificationTarget()
+		err := h.notifier.Send(ctx, notification.Message{
+			Channel: string(channel),
+			To:      target,
+			Subject: subject,
+			Body:    body,
+		})
+		if err != nil {
+			slog.Warn("oyun bildirimi gönderilemedi", "game_id", n.Game.ID, "user_id", user.ID, "error", err)
+		}
+	}
+}
+
+// canChangeGameStatus: kabul ve ret saha sahibinin, bitirme hostun kararıdır; iptali ikisi de yapabilir.
+// games:manage yetkisi olanlar her geçişi yapabilir.
+func canChangeGameStatus(claims *models.AccessTokenClaims, game models.Game, status models.GameStatus) bool {
+	isHost := middleware.CanManage(claims, models.PermissionGamesManage, int64(game.HostID))
+
+	var fieldOwnerID int64
+	if game.Field != nil && game.Field.OwnerID != nil {
+		fieldOwnerID = *game.Field.OwnerID
+	}
+	isFieldOwner := claims.Role.HasPermission(models.PermissionGamesManage) ||
+		middleware.CanManage(claims, models.PermissionFieldsManage, fieldOwnerID)
+
+	switch status {
+	case models.GameStatusAccepted, models.GameStatusRejected:
+		return isFieldOwner
+	case models.GameStatusFinished:
+		return isHost
+	case models.GameStatusCancelled:
+		return isHost || isFieldOwner
+	}
+	return false
+}
+
+// gameErrorResult oyun durum hatalarını uygun HTTP durum kodlarına çevirir
+func gameErrorResult(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrGameNotFound):
+		return notFoundResult(ctx)
+	case errors.Is(err, repository.ErrInvalidGameTransition),
+		errors.Is(err, repository.ErrGameClosed),
+		errors.Is(err, repository.ErrGameNotStarted),
+		errors.Is(err, repository.ErrGameMatchNotCompleted),
+		errors.Is(err, repository.ErrGameReserved):
+		return conflictResult(ctx, err)
+	case errors.Is(err, repository.ErrGameResultRequired):
+		return badRequestResult(ctx, err)
+	}
+	return scheduleErrorResult(ctx, err, err)
+}
+
+// scheduleErrorResult saha, takım ve oyuncu çakışmalarını 409 olarak döner, diğer hatalarda fallback ile 500 döner
+func scheduleErrorResult(ctx *fiber.Ctx, err, fallback error) error {
+	switch {
+	case errors.Is(err, repository.ErrFieldDoubleBooked),
+		errors.Is(err, repository.ErrFieldUnavailable),
+		errors.Is(err, repository.ErrFieldClosed),
+		errors.Is(err, repository.ErrFieldBlackout),
+		errors.Is(err, repository.ErrTeamScheduleClash),
+		errors.Is(err, repository.ErrPlayerScheduleClash):
+		return conflictResult(ctx, err)
+	}
+	return errorResult(ctx, fallback)
+}
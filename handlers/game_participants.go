@@ -31,7 +31,10 @@ func (h GameParticipantsHandler) CreateGameParticipants(ctx *fiber.Ctx) error {
 
 	gamePart := vm.ToDBModel(models.GameParticipants{})
 	if err := h.gameParticipantsRepository.CreateGameParticipants(ctx.Context(), gamePart); err != nil {
-		return errorResult(ctx, errors.New("Oyuncu oyuna eklenirken bir hata oluştu"))
+		if errors.Is(err, repository.ErrPlayerSuspended) {
+			return conflictResult(ctx, err)
+		}
+		return scheduleErrorResult(ctx, err, errors.New("Oyuncu oyuna eklenirken bir hata oluştu"))
 	}
 
 	return successResult(ctx, "Oyuncu oyuna başarıyla eklendi!")
@@ -52,23 +55,6 @@ func (h GameParticipantsHandler) GetAllGameParticipants(ctx *fiber.Ctx) error {
 	return successResult(ctx, result)
 }
 
-func (h GameParticipantsHandler) GetByGameParticipantsID(ctx *fiber.Ctx) error {
-	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
-	if err != nil {
-		return errorResult(ctx, errors.New("Geçersiz kullanıcı id"))
-	}
-
-	gamePart, err := h.gameParticipantsRepository.GetByGameParticipantsID(ctx.Context(), id)
-	if err != nil {
-		return errorResult(ctx, errors.New("Oyuncu bilgileri getirilirken hata oluştu"))
-	}
-
-	vm := models.GameParticipantsDetailVM{}
-	result := vm.FromDBModel(*gamePart)
-
-	return successResult(ctx, result)
-}
-
 func (h GameParticipantsHandler) GetGameParticipantsUsers(ctx *fiber.Ctx) error {
 	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
 	if err != nil {
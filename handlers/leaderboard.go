@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/repository"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+type LeaderboardHandler struct {
+	leaderboardRepository repository.ILeaderboardRepository
+}
+
+func NewLeaderboardHandler(r repository.ILeaderboardRepository) LeaderboardHandler {
+	return LeaderboardHandler{leaderboardRepository: r}
+}
+
+// GetLeagueLeaderboards ligin gol, asist, gol yemeden bitirilen maç ve kart sıralamalarını getirir.
+// board verilirse sadece o sıralama döner; page, limit, team_id, from ve to tüm sıralamalara uygulanır.
+func (h LeaderboardHandler) GetLeagueLeaderboards(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseUint(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz lig id"))
+	}
+
+	page := ctx.QueryInt("page", 1)
+	limit := ctx.QueryInt("limit", defaultLeaderboardLimit)
+	if page < 1 || limit < 1 || limit > maxLeaderboardLimit {
+		return badRequestResult(ctx, errors.New("page en az 1, limit 1 ile 100 arasında olmalı"))
+	}
+
+	teamID, err := strconv.ParseUint(ctx.Query("team_id", "0"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz takım id"))
+	}
+	from, err := parseAvailabilityTime(ctx.Query("from"), time.UTC, time.Time{})
+	if err != nil {
+		return badRequestResult(ctx, err)
+	}
+	to, err := parseAvailabilityTime(ctx.Query("to"), time.UTC, time.Time{})
+	if err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		return badRequestResult(ctx, errors.New("to, from'dan sonra olmalı"))
+	}
+
+	boards := models.Leaderboards
+	if board := models.Leaderboard(ctx.Query("board")); board != "" {
+		if !board.IsValid() {
+			return badRequestResult(ctx, errors.New("geçersiz sıralama: "+string(board)))
+		}
+		boards = []models.Leaderboard{board}
+	}
+
+	filter := models.LeaderboardFilter{
+		LeagueID: uint(id),
+		TeamID:   uint(teamID),
+		From:     from,
+		To:       to,
+		Limit:    limit,
+		Offset:   (page - 1) * limit,
+	}
+	result := models.LeaderboardsVM{LeagueID: uint(id), Page: page, Limit: limit}
+
+	for _, board := range boards {
+		switch board {
+		case models.LeaderboardScorers:
+			scorers, err := h.leaderboardRepository.GetTopScorers(ctx.Context(), filter)
+			if err != nil {
+				return errorResult(ctx, errors.New("Gol krallığı getirilirken bir hata oluştu"))
+			}
+			result.Scorers = &scorers
+		case models.LeaderboardAssists:
+			assists, err := h.leaderboardRepository.GetTopAssists(ctx.Context(), filter)
+			if err != nil {
+				return errorResult(ctx, errors.New("Asist sıralaması getirilirken bir hata oluştu"))
+			}
+			result.Assists = &assists
+		case models.LeaderboardCleanSheets:
+			cleanSheets, err := h.leaderboardRepository.GetCleanSheets(ctx.Context(), filter)
+			if err != nil {
+				return errorResult(ctx, errors.New("Gol yenmeyen maç sıralaması getirilirken bir hata oluştu"))
+			}
+			result.CleanSheets = &cleanSheets
+		case models.LeaderboardCards:
+			cards, err := h.leaderboardRepository.GetCardTable(ctx.Context(), filter)
+			if err != nil {
+				return errorResult(ctx, errors.New("Kart tablosu getirilirken bir hata oluştu"))
+			}
+			result.Cards = &cards
+		}
+	}
+
+	return successResult(ctx, result)
+}
@@ -30,6 +30,10 @@ func (h LeagueHandler) CreateLeague(ctx *fiber.Ctx) error {
 	}
 
 	league := vm.ToDBModel(models.League{})
+	if err := league.ValidateRules(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
 	if err := h.leagueRepository.CreateLeague(ctx.Context(), league); err != nil {
 		return errorResult(ctx, errors.New("Lig oluşturulurken bir hata oluştu"))
 	}
@@ -82,3 +86,33 @@ func (h LeagueHandler) DeleteByLeagueID(ctx *fiber.Ctx) error {
 
 	return successResult(ctx, "Lig başarıyla silindi!")
 }
+
+// UpdateLeagueRules ligin puanlama, eşitlik ve kart cezası kurallarını değiştirir, puan durumu ve cezalar yeni kurallarla yeniden hesaplanır
+func (h LeagueHandler) UpdateLeagueRules(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz lig id"))
+	}
+
+	league, err := h.leagueRepository.GetByLeagueID(ctx.Context(), id)
+	if err != nil {
+		return notFoundResult(ctx)
+	}
+
+	var vm models.LeagueRulesVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	updatedLeague := vm.ToDBModel(*league)
+	if err := updatedLeague.ValidateRules(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	if err := h.leagueRepository.UpdateLeagueRules(ctx.Context(), updatedLeague); err != nil {
+		return errorResult(ctx, errors.New("Lig kuralları güncellenirken bir hata oluştu"))
+	}
+
+	detailVM := models.LeagueDetailVM{}
+	return successResult(ctx, detailVM.FromDBModel(updatedLeague))
+}
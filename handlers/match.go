@@ -1,14 +1,21 @@
 package handlers
 
 import (
+	"database/sql"
 	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/middleware"
 	"github.com/personal-project/pitch-league/models"
 	"github.com/personal-project/pitch-league/repository"
 )
 
+var (
+	ErrInvalidNewMatchStatus = errors.New("maç sadece SCHEDULED veya COMPLETED durumunda oluşturulabilir")
+	ErrNotMatchCaptain       = errors.New("skoru sadece maçta oynayan takımların kaptanları bildirebilir")
+)
+
 type MatchHandler struct {
 	BaseHandler[models.Match]
 	matchRepository repository.IMatchRepository
@@ -30,13 +37,14 @@ func (h *MatchHandler) CreateMatch(ctx *fiber.Ctx) error {
 	}
 
 	match := vm.ToDBModel(models.Match{})
-
-	if err := h.matchRepository.CreateMatch(ctx.Context(), match); err != nil {
-		return errorResult(ctx, errors.New("Maç oluşturulurken bir hata oluştu"))
+	// Diğer durumlara sadece durum değiştirme endpoint'i üzerinden geçilir
+	if match.Status != models.MatchStatusScheduled && match.Status != models.MatchStatusCompleted {
+		return badRequestResult(ctx, ErrInvalidNewMatchStatus)
 	}
 
-	if err := h.matchRepository.UpdateLeagueStandings(ctx.Context(), match); err != nil {
-		return errorResult(ctx, errors.New("Lig sıralaması güncellenirken bir hata oluştu"))
+	// Puan durumu maçla aynı transaction içinde tamamlanmış maçlardan yeniden kurulur
+	if err := h.matchRepository.CreateMatch(ctx.Context(), match); err != nil {
+		return scheduleErrorResult(ctx, err, errors.New("Maç oluşturulurken bir hata oluştu"))
 	}
 
 	return successResult(ctx, "Maç bilgileri başarıyla eklendi!")
@@ -85,3 +93,142 @@ func (h *MatchHandler) DeleteByMatchID(ctx *fiber.Ctx) error {
 
 	return successResult(ctx, "Maç bilgileri başarıyla silindi!")
 }
+
+// ChangeMatchStatus maçı yaşam döngüsündeki bir sonraki durumuna geçirir (canlı, ertelendi, yarıda kaldı, hükmen...)
+func (h *MatchHandler) ChangeMatchStatus(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz match id"))
+	}
+
+	var vm models.MatchStatusUpdateVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	match, err := h.matchRepository.GetByMatchID(ctx.Context(), id)
+	if err != nil {
+		return matchErrorResult(ctx, err)
+	}
+
+	updated, err := vm.ToDBModel(*match)
+	if err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	if err := h.matchRepository.ChangeMatchStatus(ctx.Context(), updated, match.Status, userID); err != nil {
+		return matchErrorResult(ctx, err)
+	}
+
+	return h.matchResult(ctx, id)
+}
+
+// RecordMatchResult maçın skorunu kaydedip maçı tamamlar. Aynı skor iki kez gönderilirse ikincisi bir şey değiştirmez.
+func (h *MatchHandler) RecordMatchResult(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz match id"))
+	}
+
+	var vm models.MatchResultVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if err := vm.Validate(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	if err := h.matchRepository.RecordMatchResult(ctx.Context(), id, *vm.HomeScore, *vm.AwayScore, userID); err != nil {
+		return matchErrorResult(ctx, err)
+	}
+
+	return h.matchResult(ctx, id)
+}
+
+// SubmitMatchResult maçta oynayan takımın kaptanının skoru bildirmesini sağlar. İki kaptan aynı skoru
+// bildirdiğinde maç tamamlanır, farklı skorlar lig yönetimi sonucu girene kadar bekler.
+func (h *MatchHandler) SubmitMatchResult(ctx *fiber.Ctx) error {
+	claims, ok := middleware.Claims(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz match id"))
+	}
+
+	var vm models.MatchResultVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if err := vm.Validate(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	match, err := h.matchRepository.GetByMatchID(ctx.Context(), id)
+	if err != nil {
+		return matchErrorResult(ctx, err)
+	}
+
+	teamID := captainTeamID(claims, *match)
+	if teamID == 0 {
+		return forbiddenResult(ctx, ErrNotMatchCaptain)
+	}
+
+	submission := vm.ToDBModel(models.MatchResultSubmission{
+		MatchID:     id,
+		TeamID:      teamID,
+		SubmittedBy: claims.UserID,
+	})
+	if _, err := h.matchRepository.SubmitMatchResult(ctx.Context(), submission); err != nil {
+		return matchErrorResult(ctx, err)
+	}
+
+	return h.matchResult(ctx, id)
+}
+
+func (h *MatchHandler) matchResult(ctx *fiber.Ctx, id int64) error {
+	match, err := h.matchRepository.GetByMatchID(ctx.Context(), id)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+	return successResult(ctx, models.MatchDetailVM{}.FromDBModel(*match))
+}
+
+// captainTeamID isteği yapanın kaptanı olduğu maç takımını döner, iki takımın da kaptanı değilse 0 döner
+func captainTeamID(claims *models.AccessTokenClaims, match models.Match) uint {
+	for _, team := range []*models.Team{match.HomeTeam, match.AwayTeam} {
+		if team != nil && team.CaptainID == claims.UserID {
+			return uint(team.ID)
+		}
+	}
+	return 0
+}
+
+// matchErrorResult maç hatalarını uygun HTTP durum kodlarına çevirir
+func matchErrorResult(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrMatchNotFound),
+		errors.Is(err, sql.ErrNoRows):
+		return notFoundResult(ctx)
+	case errors.Is(err, repository.ErrInvalidMatchTransition),
+		errors.Is(err, repository.ErrMatchNotPlayed),
+		errors.Is(err, repository.ErrMatchResultFinal),
+		errors.Is(err, repository.ErrMatchScoreMismatch):
+		return conflictResult(ctx, err)
+	case errors.Is(err, repository.ErrNotMatchTeam):
+		return forbiddenResult(ctx, err)
+	}
+	return gameErrorResult(ctx, err)
+}
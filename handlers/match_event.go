@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/middleware"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/repository"
+)
+
+type MatchEventHandler struct {
+	matchEventRepository repository.IMatchEventRepository
+}
+
+func NewMatchEventHandler(r repository.IMatchEventRepository) MatchEventHandler {
+	return MatchEventHandler{matchEventRepository: r}
+}
+
+// GetMatchEvents maçın olaylarını dakika sırasıyla getirir
+func (h MatchEventHandler) GetMatchEvents(ctx *fiber.Ctx) error {
+	matchID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz match id"))
+	}
+
+	events, err := h.matchEventRepository.GetMatchEvents(ctx.Context(), matchID)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+	return successResult(ctx, events)
+}
+
+// CreateMatchEvent maça gol, kart veya oyuncu değişikliği ekler
+func (h MatchEventHandler) CreateMatchEvent(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	matchID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz match id"))
+	}
+
+	var vm models.MatchEventVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if err := vm.Validate(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	event := vm.ToDBModel(models.MatchEvent{MatchID: matchID, CreatedBy: userID})
+	if err := h.matchEventRepository.CreateMatchEvent(ctx.Context(), &event); err != nil {
+		return matchEventErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, event)
+}
+
+// UpdateMatchEvent yanlış girilmiş bir olayı düzeltir
+func (h MatchEventHandler) UpdateMatchEvent(ctx *fiber.Ctx) error {
+	matchID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz match id"))
+	}
+	eventID, err := strconv.ParseInt(ctx.Params("eventID"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz olay id"))
+	}
+
+	var vm models.MatchEventVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if err := vm.Validate(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	event := vm.ToDBModel(models.MatchEvent{ID: eventID, MatchID: matchID})
+	if err := h.matchEventRepository.UpdateMatchEvent(ctx.Context(), &event); err != nil {
+		return matchEventErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, event)
+}
+
+// DeleteMatchEvent maçtan bir olayı siler, gol silinirse skor ve puan durumu yeniden hesaplanır
+func (h MatchEventHandler) DeleteMatchEvent(ctx *fiber.Ctx) error {
+	matchID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz match id"))
+	}
+	eventID, err := strconv.ParseInt(ctx.Params("eventID"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz olay id"))
+	}
+
+	if err := h.matchEventRepository.DeleteMatchEvent(ctx.Context(), matchID, eventID); err != nil {
+		return matchEventErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Maç olayı silindi")
+}
+
+// matchEventErrorResult maç olayı hatalarını uygun HTTP durum kodlarına çevirir
+func matchEventErrorResult(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrMatchEventNotFound):
+		return notFoundResult(ctx)
+	case errors.Is(err, repository.ErrEventParticipant),
+		errors.Is(err, repository.ErrEventParticipantTeam):
+		return badRequestResult(ctx, err)
+	case errors.Is(err, repository.ErrMatchEventsClosed),
+		errors.Is(err, repository.ErrMatchHasNoGame):
+		return conflictResult(ctx, err)
+	}
+	return matchErrorResult(ctx, err)
+}
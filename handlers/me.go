@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/middleware"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/repository"
+	"github.com/personal-project/pitch-league/utils"
+)
+
+var (
+	ErrUserNameTaken        = errors.New("bu kullanıcı adı zaten kullanılıyor")
+	ErrWrongCurrentPassword = errors.New("mevcut parola hatalı")
+	errUnauthorized         = errors.New("yetkisiz erişim")
+)
+
+// MeHandler isteği yapan kullanıcının kendi bilgilerini yönetir, kullanıcı her zaman access tokendan okunur
+type MeHandler struct {
+	userRepository   repository.IUserRepository
+	authRepository   repository.IAuthRepository
+	revocationStore  repository.ITokenRevocationStore
+	teamRepository   repository.ITeamRepository
+	gameRepository   repository.IGameRepository
+	matchRepository  repository.IMatchRepository
+	leagueRepository repository.ILeagueRepository
+}
+
+func NewMeHandler(ur repository.IUserRepository, ar repository.IAuthRepository, rs repository.ITokenRevocationStore, tr repository.ITeamRepository, gr repository.IGameRepository, mr repository.IMatchRepository, lr repository.ILeagueRepository) MeHandler {
+	return MeHandler{
+		userRepository:   ur,
+		authRepository:   ar,
+		revocationStore:  rs,
+		teamRepository:   tr,
+		gameRepository:   gr,
+		matchRepository:  mr,
+		leagueRepository: lr,
+	}
+}
+
+// GetMe isteği yapan kullanıcının bilgilerini getirir
+func (h MeHandler) GetMe(ctx *fiber.Ctx) error {
+	user, err := h.currentUser(ctx)
+	if err != nil {
+		return meErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, models.ToUserResponse(user))
+}
+
+// UpdateMe isteği yapan kullanıcının adını, soyadını ve kullanıcı adını günceller
+func (h MeHandler) UpdateMe(ctx *fiber.Ctx) error {
+	user, err := h.currentUser(ctx)
+	if err != nil {
+		return meErrorResult(ctx, err)
+	}
+
+	var vm models.UserProfileUpdate
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if err := vm.Validate(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	updated := vm.ToModel(user)
+	if updated.UserName != user.UserName {
+		existing, err := h.userRepository.GetByUserName(ctx.Context(), updated.UserName)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return errorResult(ctx, err)
+		}
+		if err == nil && existing.ID != user.ID {
+			return conflictResult(ctx, ErrUserNameTaken)
+		}
+	}
+
+	if err := h.userRepository.Update(ctx.Context(), updated); err != nil {
+		return errorResult(ctx, errors.New("Bilgileriniz güncellenirken bir hata oluştu"))
+	}
+
+	return successResult(ctx, models.ToUserResponse(updated))
+}
+
+// ChangeMyPassword mevcut parolayı doğrulayıp yeni parolayı kaydeder, mevcut oturum dışındaki tüm oturumlar kapatılır
+func (h MeHandler) ChangeMyPassword(ctx *fiber.Ctx) error {
+	claims, ok := middleware.Claims(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	user, err := h.currentUser(ctx)
+	if err != nil {
+		return meErrorResult(ctx, err)
+	}
+
+	var vm models.UserPasswordUpdate
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if err := vm.Validate(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	if !utils.CheckPasswordHash(strings.TrimSpace(vm.CurrentPassword), user.Password) {
+		return badRequestResult(ctx, ErrWrongCurrentPassword)
+	}
+
+	// Login parolayı boşluklardan temizleyerek kontrol ettiği için yeni parola da temizlenerek saklanır
+	hashedPassword, err := utils.HashPassword(strings.TrimSpace(vm.NewPassword))
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+	user.Password = hashedPassword
+	if err := h.userRepository.Update(ctx.Context(), user); err != nil {
+		return errorResult(ctx, errors.New("Parolanız güncellenirken bir hata oluştu"))
+	}
+
+	if err := revokeOtherSessions(ctx.Context(), h.authRepository, h.revocationStore, claims); err != nil {
+		return errorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Parolanız güncellendi, diğer tüm oturumlar kapatıldı")
+}
+
+// GetMyTeams isteği yapan kullanıcının aktif üyesi olduğu takımları ve takımdaki rolünü getirir
+func (h MeHandler) GetMyTeams(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	members, err := h.teamRepository.GetUserTeams(ctx.Context(), userID)
+	if err != nil {
+		return errorResult(ctx, errors.New("Takımlar getirilirken bir hata oluştu"))
+	}
+
+	result := make([]models.UserTeamVM, 0, len(members))
+	for _, member := range members {
+		result = append(result, models.UserTeamVM{}.FromDBModel(member))
+	}
+
+	return successResult(ctx, result)
+}
+
+// GetMyUpcomingGames isteği yapan kullanıcının oyuncu veya host olduğu, henüz bitmemiş oyunları getirir
+func (h MeHandler) GetMyUpcomingGames(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	games, err := h.gameRepository.GetUserUpcomingGames(ctx.Context(), userID)
+	if err != nil {
+		return errorResult(ctx, errors.New("Oyunlar getirilirken bir hata oluştu"))
+	}
+
+	result := make([]models.GameDetailVM, 0, len(games))
+	for _, game := range games {
+		vm := models.GameDetailVM{}
+		result = append(result, vm.FromDBModel(game))
+	}
+
+	return successResult(ctx, result)
+}
+
+// GetMyMatches isteği yapan kullanıcının takımlarının lig maçlarını getirir, upcoming=true ile sadece sonuçlanmamış maçlar döner
+func (h MeHandler) GetMyMatches(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	matches, err := h.matchRepository.GetUserMatches(ctx.Context(), userID, ctx.QueryBool("upcoming"))
+	if err != nil {
+		return errorResult(ctx, errors.New("Maçlar getirilirken bir hata oluştu"))
+	}
+
+	result := make([]models.MatchDetailVM, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, models.MatchDetailVM{}.FromDBModel(match))
+	}
+
+	return successResult(ctx, result)
+}
+
+// GetMyLeagues isteği yapan kullanıcının takımlarının kayıtlı olduğu ligleri getirir
+func (h MeHandler) GetMyLeagues(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	leagues, err := h.leagueRepository.GetUserLeagues(ctx.Context(), userID)
+	if err != nil {
+		return errorResult(ctx, errors.New("Ligler getirilirken bir hata oluştu"))
+	}
+
+	result := make([]models.LeagueDetailVM, 0, len(leagues))
+	for _, league := range leagues {
+		vm := models.LeagueDetailVM{}
+		result = append(result, vm.FromDBModel(league))
+	}
+
+	return successResult(ctx, result)
+}
+
+// currentUser access tokendaki kullanıcıyı getirir
+func (h MeHandler) currentUser(ctx *fiber.Ctx) (models.User, error) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return models.User{}, errUnauthorized
+	}
+	return h.userRepository.GetByID(ctx.Context(), userID)
+}
+
+func meErrorResult(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errUnauthorized):
+		return unauthorizedResult(ctx, err)
+	case errors.Is(err, sql.ErrNoRows):
+		// Token geçerli ama kullanıcı silinmiş
+		return unauthorizedResult(ctx, errUnauthorized)
+	}
+	return errorResult(ctx, err)
+}
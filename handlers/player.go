@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/middleware"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/repository"
+)
+
+type PlayerHandler struct {
+	playerRepository repository.IPlayerRepository
+}
+
+func NewPlayerHandler(r repository.IPlayerRepository) PlayerHandler {
+	return PlayerHandler{playerRepository: r}
+}
+
+// GetPlayer oyuncunun herkese açık profilini kariyer istatistikleriyle getirir
+func (h PlayerHandler) GetPlayer(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz oyuncu id"))
+	}
+
+	return h.playerResult(ctx, id)
+}
+
+// GetMyPlayer isteği yapan kullanıcının oyuncu profilini kariyer istatistikleriyle getirir
+func (h PlayerHandler) GetMyPlayer(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	return h.playerResult(ctx, userID)
+}
+
+// UpdateMyPlayerProfile isteği yapan kullanıcının oyuncu profilini günceller
+func (h PlayerHandler) UpdateMyPlayerProfile(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	var vm models.PlayerProfileUpdateVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if err := vm.Validate(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	_, profile, err := h.playerRepository.GetPlayer(ctx.Context(), userID)
+	if err != nil {
+		return playerErrorResult(ctx, err)
+	}
+
+	updated := vm.ToDBModel(*profile)
+	if err := h.playerRepository.UpdatePlayerProfile(ctx.Context(), &updated); err != nil {
+		return errorResult(ctx, errors.New("Profil güncellenirken bir hata oluştu"))
+	}
+
+	return h.playerResult(ctx, userID)
+}
+
+func (h PlayerHandler) playerResult(ctx *fiber.Ctx, id int64) error {
+	user, profile, err := h.playerRepository.GetPlayer(ctx.Context(), id)
+	if err != nil {
+		return playerErrorResult(ctx, err)
+	}
+
+	stats, err := h.playerRepository.GetPlayerCareerStats(ctx.Context(), id)
+	if err != nil {
+		return errorResult(ctx, errors.New("Oyuncu istatistikleri getirilirken bir hata oluştu"))
+	}
+
+	return successResult(ctx, models.PlayerDetailVM{}.FromDBModel(*user, *profile, stats))
+}
+
+func playerErrorResult(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, repository.ErrPlayerNotFound) {
+		return notFoundResult(ctx)
+	}
+	return errorResult(ctx, err)
+}
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/booking"
+	"github.com/personal-project/pitch-league/middleware"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/payment"
+	"github.com/personal-project/pitch-league/repository"
+)
+
+var (
+	ErrReservationInPast  = errors.New("geçmiş bir saat için rezervasyon yapılamaz")
+	ErrNotReservationUser = errors.New("bu işlemi sadece rezervasyonu yapan kullanıcı yapabilir")
+)
+
+type ReservationHandler struct {
+	reservationRepository repository.IReservationRepository
+	fieldRepository       repository.IFieldRepository
+	paymentProvider       payment.Provider
+	peaks                 []booking.PeakWindow
+	refundPolicy          booking.RefundPolicy
+	holdTTL               time.Duration
+}
+
+func NewReservationHandler(r repository.IReservationRepository, fr repository.IFieldRepository, provider payment.Provider, peaks []booking.PeakWindow, refundPolicy booking.RefundPolicy, holdTTL time.Duration) ReservationHandler {
+	return ReservationHandler{
+		reservationRepository: r,
+		fieldRepository:       fr,
+		paymentProvider:       provider,
+		peaks:                 peaks,
+		refundPolicy:          refundPolicy,
+		holdTTL:               holdTTL,
+	}
+}
+
+// QuoteReservation rezervasyon yapmadan sahanın verilen saat aralığı için fiyatını hesaplar
+func (h ReservationHandler) QuoteReservation(ctx *fiber.Ctx) error {
+	var vm models.ReservationCreateVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	field, err := h.reservationField(ctx.Context(), vm)
+	if err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	base := models.Field{PricePerHour: field.PricePerHour, Timezone: field.Timezone}
+	return successResult(ctx, models.ReservationQuoteVM{
+		FieldID:      vm.FieldID,
+		StartTime:    vm.StartTime,
+		EndTime:      vm.EndTime,
+		PricePerHour: field.PricePerHour,
+		BasePrice:    base.Pricing(nil).Price(vm.StartTime, vm.EndTime),
+		TotalPrice:   field.Pricing(h.peaks).Price(vm.StartTime, vm.EndTime),
+		Currency:     payment.CurrencyTRY,
+	})
+}
+
+// CreateReservation sahayı isteği yapan kullanıcı adına holdTTL süresince tutar, bu sürede onaylanmazsa saha serbest kalır
+func (h ReservationHandler) CreateReservation(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	var vm models.ReservationCreateVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	field, err := h.reservationField(ctx.Context(), vm)
+	if err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	reservation := models.Reservation{
+		FieldID:       vm.FieldID,
+		UserID:        userID,
+		StartTime:     vm.StartTime,
+		EndTime:       vm.EndTime,
+		TotalPrice:    field.Pricing(h.peaks).Price(vm.StartTime, vm.EndTime),
+		Currency:      payment.CurrencyTRY,
+		HoldExpiresAt: time.Now().Add(h.holdTTL),
+	}
+	if err := h.reservationRepository.CreateReservation(ctx.Context(), &reservation, vm.Game(*field, userID)); err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	return h.reservationResult(ctx, reservation.ID)
+}
+
+// GetMyReservations isteği yapan kullanıcının yaptığı ve payı olduğu rezervasyonları getirir
+func (h ReservationHandler) GetMyReservations(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	reservations, err := h.reservationRepository.GetReservationsByUser(ctx.Context(), userID)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	result := make([]models.ReservationDetailVM, 0, len(reservations))
+	for _, reservation := range reservations {
+		vm := models.ReservationDetailVM{}
+		result = append(result, vm.FromDBModel(reservation))
+	}
+
+	return successResult(ctx, result)
+}
+
+// GetReservation rezervasyonu sadece rezervasyonu yapana ve payı olan oyunculara gösterir
+func (h ReservationHandler) GetReservation(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	reservation, err := h.reservation(ctx, userID, false)
+	if err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	vm := models.ReservationDetailVM{}
+	return successResult(ctx, vm.FromDBModel(*reservation))
+}
+
+// ConfirmReservation ücretin tamamını rezervasyonu yapandan çekip rezervasyonu onaylar
+func (h ReservationHandler) ConfirmReservation(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	reservation, err := h.reservation(ctx, userID, true)
+	if err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	err = h.reservationRepository.ConfirmReservation(ctx.Context(), reservation.ID, func(r models.Reservation) (string, error) {
+		// Reference sayesinde aynı rezervasyon iki kez onaylanmaya çalışılsa da ücret bir kez çekilir
+		return h.paymentProvider.Charge(context.Background(), payment.ChargeRequest{
+			Reference: fmt.Sprintf("reservation-%d", r.ID),
+			UserID:    r.UserID,
+			Amount:    r.TotalPrice,
+			Currency:  r.Currency,
+		})
+	}, func(r models.Reservation, paymentID string) error {
+		_, err := h.paymentProvider.Refund(context.Background(), payment.RefundRequest{
+			Reference: fmt.Sprintf("reservation-%d-void", r.ID),
+			ChargeID:  paymentID,
+			Amount:    r.TotalPrice,
+		})
+		return err
+	})
+	if err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	return h.reservationResult(ctx, reservation.ID)
+}
+
+// CancelReservation rezervasyonu iptal eder, onaylanmış rezervasyonlarda iade politikası kadar ücret geri ödenir
+func (h ReservationHandler) CancelReservation(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	reservation, err := h.reservation(ctx, userID, true)
+	if err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	err = h.reservationRepository.CancelReservation(ctx.Context(), reservation.ID, func(r models.Reservation) int64 {
+		return h.refundPolicy.Refund(r.TotalPrice, r.StartTime, time.Now())
+	}, func(r models.Reservation, amount int64) (string, error) {
+		// İade yarıda kalıp iptal tekrarlanırsa Reference sayesinde iade bir kez yapılır
+		return h.paymentProvider.Refund(context.Background(), payment.RefundRequest{
+			Reference: fmt.Sprintf("reservation-%d-refund", r.ID),
+			ChargeID:  r.PaymentID,
+			Amount:    amount,
+		})
+	})
+	if err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	return h.reservationResult(ctx, reservation.ID)
+}
+
+// SplitReservation ücreti rezervasyonu yapan ve seçilen oyuncular arasında eşit böler
+func (h ReservationHandler) SplitReservation(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	reservation, err := h.reservation(ctx, userID, true)
+	if err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	var vm models.ReservationSplitVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	if err := h.reservationRepository.SplitReservation(ctx.Context(), reservation.ID, vm.UserIDs); err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	return h.reservationResult(ctx, reservation.ID)
+}
+
+// MarkSharePaid rezervasyonu yapanın, oyuncunun payını kendisine ödediğini kaydetmesini sağlar
+func (h ReservationHandler) MarkSharePaid(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	reservation, err := h.reservation(ctx, userID, true)
+	if err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	payerID, err := strconv.ParseInt(ctx.Params("userID"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	if err := h.reservationRepository.MarkReservationSharePaid(ctx.Context(), reservation.ID, payerID); err != nil {
+		return reservationErrorResult(ctx, err)
+	}
+
+	return h.reservationResult(ctx, reservation.ID)
+}
+
+// reservationField istenen saat aralığını doğrulayıp rezervasyon yapılacak sahayı getirir
+func (h ReservationHandler) reservationField(ctx context.Context, vm models.ReservationCreateVM) (*models.Field, error) {
+	if !vm.EndTime.After(vm.StartTime) {
+		return nil, ErrInvalidGameTime
+	}
+	if !vm.StartTime.After(time.Now()) {
+		return nil, ErrReservationInPast
+	}
+	return h.fieldRepository.GetByFieldID(ctx, int64(vm.FieldID))
+}
+
+// reservation ":id" parametresindeki rezervasyonu getirir. Rezervasyon sadece onu yapana ve payı olan oyunculara
+// görünür; own true ise sadece rezervasyonu yapan kullanıcıya döner.
+func (h ReservationHandler) reservation(ctx *fiber.Ctx, userID int64, own bool) (*models.Reservation, error) {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrReservationNotFound
+	}
+
+	reservation, err := h.reservationRepository.GetReservation(ctx.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.UserID == userID {
+		return reservation, nil
+	}
+
+	for _, share := range reservation.Shares {
+		if share.UserID == userID {
+			if own {
+				return nil, ErrNotReservationUser
+			}
+			return reservation, nil
+		}
+	}
+	return nil, repository.ErrReservationNotFound
+}
+
+func (h ReservationHandler) reservationResult(ctx *fiber.Ctx, id int64) error {
+	reservation, err := h.reservationRepository.GetReservation(ctx.Context(), id)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	vm := models.ReservationDetailVM{}
+	return successResult(ctx, vm.FromDBModel(*reservation))
+}
+
+// reservationErrorResult rezervasyon hatalarını uygun HTTP durum kodlarına çevirir
+func reservationErrorResult(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrReservationNotFound),
+		errors.Is(err, repository.ErrReservationShareNotFound),
+		errors.Is(err, sql.ErrNoRows):
+		return notFoundResult(ctx)
+	case errors.Is(err, ErrNotReservationUser):
+		return forbiddenResult(ctx, err)
+	case errors.Is(err, ErrInvalidGameTime),
+		errors.Is(err, ErrReservationInPast):
+		return badRequestResult(ctx, err)
+	case errors.Is(err, repository.ErrReservationExpired),
+		errors.Is(err, repository.ErrReservationNotHeld),
+		errors.Is(err, repository.ErrReservationNotConfirmed),
+		errors.Is(err, repository.ErrReservationClosed),
+		errors.Is(err, repository.ErrReservationStarted),
+		errors.Is(err, repository.ErrReservationSharesPaid):
+		return conflictResult(ctx, err)
+	case errors.Is(err, payment.ErrPaymentDeclined):
+		return badRequestResult(ctx, err)
+	}
+	return scheduleErrorResult(ctx, err, err)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/repository"
+)
+
+type StandingsHandler struct {
+	standingsRepository repository.IStandingsRepository
+}
+
+func NewStandingsHandler(r repository.IStandingsRepository) StandingsHandler {
+	return StandingsHandler{
+		standingsRepository: r,
+	}
+}
+
+// RebuildLeagueStandings ligin puan durumunu tamamlanmış maçlardan baştan kurar
+func (h StandingsHandler) RebuildLeagueStandings(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseUint(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz lig id"))
+	}
+
+	if err := h.standingsRepository.RebuildLeagueStandings(ctx.Context(), uint(id)); err != nil {
+		return errorResult(ctx, errors.New("Lig sıralaması güncellenirken bir hata oluştu"))
+	}
+
+	return successResult(ctx, "Lig sıralaması yeniden hesaplandı!")
+}
+
+// GetLeagueStandings ligin tam puan tablosunu getirir
+func (h StandingsHandler) GetLeagueStandings(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseUint(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz lig id"))
+	}
+
+	table, err := h.standingsRepository.GetLeagueStandings(ctx.Context(), uint(id))
+	if err != nil {
+		return errorResult(ctx, errors.New("Puan durumu getirilirken bir hata oluştu"))
+	}
+
+	result := make([]models.LeagueStandingVM, 0, len(table))
+	for _, row := range table {
+		vm := models.LeagueStandingVM{}
+		result = append(result, vm.FromDBModel(row))
+	}
+
+	return successResult(ctx, result)
+}
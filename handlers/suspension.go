@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/middleware"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/repository"
+)
+
+type SuspensionHandler struct {
+	suspensionRepository repository.ISuspensionRepository
+}
+
+func NewSuspensionHandler(r repository.ISuspensionRepository) SuspensionHandler {
+	return SuspensionHandler{suspensionRepository: r}
+}
+
+// GetLeagueSuspensions ligin cezalarını kapsadıkları maçlarla getirir, active=true ile sadece süren cezalar döner
+func (h SuspensionHandler) GetLeagueSuspensions(ctx *fiber.Ctx) error {
+	leagueID, err := strconv.ParseUint(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz lig id"))
+	}
+
+	suspensions, servings, err := h.suspensionRepository.GetLeagueSuspensions(ctx.Context(), uint(leagueID))
+	if err != nil {
+		return errorResult(ctx, errors.New("Cezalar getirilirken bir hata oluştu"))
+	}
+
+	activeOnly := ctx.QueryBool("active")
+	result := make([]models.SuspensionDetailVM, 0, len(suspensions))
+	for _, suspension := range suspensions {
+		vm := models.SuspensionDetailVM{}.FromDBModel(suspension, servings[suspension.ID])
+		if activeOnly && !vm.Active {
+			continue
+		}
+		result = append(result, vm)
+	}
+
+	return successResult(ctx, result)
+}
+
+// CreateSuspension oyuncuya elle ceza verir
+func (h SuspensionHandler) CreateSuspension(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	leagueID, err := strconv.ParseUint(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz lig id"))
+	}
+
+	var vm models.SuspensionCreateVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if err := vm.Validate(); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	suspension := vm.ToDBModel(models.Suspension{LeagueID: uint(leagueID), CreatedBy: userID})
+	if err := h.suspensionRepository.CreateSuspension(ctx.Context(), &suspension); err != nil {
+		return suspensionErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, suspension)
+}
+
+// LiftSuspension cezayı kaldırır, oyuncu kalan ceza maçlarında oynayabilir
+func (h SuspensionHandler) LiftSuspension(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	leagueID, err := strconv.ParseUint(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz lig id"))
+	}
+	suspensionID, err := strconv.ParseInt(ctx.Params("suspensionID"), 10, 64)
+	if err != nil {
+		return badRequestResult(ctx, errors.New("Geçersiz ceza id"))
+	}
+
+	if err := h.suspensionRepository.LiftSuspension(ctx.Context(), uint(leagueID), suspensionID, userID); err != nil {
+		return suspensionErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Ceza kaldırıldı")
+}
+
+// suspensionErrorResult ceza hatalarını uygun HTTP durum kodlarına çevirir
+func suspensionErrorResult(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrSuspensionNotFound):
+		return notFoundResult(ctx)
+	case errors.Is(err, repository.ErrTeamNotInLeague):
+		return badRequestResult(ctx, err)
+	case errors.Is(err, repository.ErrSuspensionLifted):
+		return conflictResult(ctx, err)
+	}
+	return errorResult(ctx, err)
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/middleware"
 	"github.com/personal-project/pitch-league/models"
 	"github.com/personal-project/pitch-league/repository"
 )
@@ -100,6 +103,13 @@ func (h TeamHandler) UpdateTeamByID(ctx *fiber.Ctx) error {
 		return errorResult(ctx, err)
 	}
 
+	// Kaptanlık kadroyla birlikte değişmeli, bu yüzden sadece /captain üzerinden devredilir
+	vm.CaptainID = m.CaptainID
+
+	if vm.Capacity < m.MemberCount {
+		return conflictResult(ctx, errors.New("kapasite aktif üye sayısından az olamaz"))
+	}
+
 	updatedTeam := vm.ToDBModel(*m)
 	if err := h.teamRepository.UpdateTeam(ctx.Context(), updatedTeam); err != nil {
 		return errorResult(ctx, err)
@@ -108,7 +118,63 @@ func (h TeamHandler) UpdateTeamByID(ctx *fiber.Ctx) error {
 	return successResult(ctx, updatedTeam)
 }
 
-func (h TeamHandler) JoinTeam(ctx *fiber.Ctx) error {
+func (h TeamHandler) GetTeamMembers(ctx *fiber.Ctx) error {
+	teamID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	members, err := h.teamRepository.GetTeamMembers(ctx.Context(), teamID)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	result := make([]models.TeamMemberDetailVM, 0, len(members))
+	for _, member := range members {
+		vm := models.TeamMemberDetailVM{}
+		result = append(result, vm.FromDBModel(member))
+	}
+
+	return successResult(ctx, result)
+}
+
+func (h TeamHandler) LeaveTeam(ctx *fiber.Ctx) error {
+	teamID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	if err := h.teamRepository.RemoveTeamMember(ctx.Context(), teamID, userID, models.TeamMemberStatusLeft); err != nil {
+		return teamMemberErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Takımdan ayrıldınız")
+}
+
+func (h TeamHandler) KickTeamMember(ctx *fiber.Ctx) error {
+	teamID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	userID, err := strconv.ParseInt(ctx.Params("userID"), 10, 64)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	if err := h.teamRepository.RemoveTeamMember(ctx.Context(), teamID, userID, models.TeamMemberStatusKicked); err != nil {
+		return teamMemberErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Oyuncu takımdan çıkarıldı")
+}
+
+func (h TeamHandler) UpdateTeamMember(ctx *fiber.Ctx) error {
 	teamID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
 	if err != nil {
 		return errorResult(ctx, err)
@@ -119,9 +185,59 @@ func (h TeamHandler) JoinTeam(ctx *fiber.Ctx) error {
 		return errorResult(ctx, err)
 	}
 
-	if err := h.teamRepository.AddUserToTeam(ctx.Context(), userID, teamID); err != nil {
+	var vm models.TeamMemberUpdateVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if !vm.Role.IsValid() {
+		return badRequestResult(ctx, errors.New("geçersiz takım rolü"))
+	}
+
+	member, err := h.teamRepository.GetTeamMember(ctx.Context(), teamID, userID)
+	if err != nil {
+		return teamMemberErrorResult(ctx, err)
+	}
+
+	updatedMember := vm.ToDBModel(*member)
+	if err := h.teamRepository.UpdateTeamMember(ctx.Context(), updatedMember); err != nil {
+		return teamMemberErrorResult(ctx, err)
+	}
+
+	detailVM := models.TeamMemberDetailVM{}
+	return successResult(ctx, detailVM.FromDBModel(updatedMember))
+}
+
+func (h TeamHandler) TransferCaptaincy(ctx *fiber.Ctx) error {
+	teamID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
 		return errorResult(ctx, err)
 	}
 
-	return successResult(ctx, "Successfully joined the team")
+	var vm models.TeamCaptainTransferVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+
+	// Kaptanlık teams.captain_id'den okunur, kullanıcının rolü değişmez
+	if err := h.teamRepository.TransferCaptaincy(ctx.Context(), teamID, vm.UserID); err != nil {
+		return teamMemberErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Kaptanlık devredildi")
+}
+
+// teamMemberErrorResult üyelik hatalarını uygun HTTP durum kodlarına çevirir
+func teamMemberErrorResult(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrTeamMemberNotFound), errors.Is(err, sql.ErrNoRows):
+		return notFoundResult(ctx)
+	case errors.Is(err, repository.ErrTeamFull),
+		errors.Is(err, repository.ErrAlreadyTeamMember),
+		errors.Is(err, repository.ErrJerseyNumberTaken),
+		errors.Is(err, repository.ErrCaptainCannotLeave),
+		errors.Is(err, repository.ErrCaptainRoleReserved):
+		return conflictResult(ctx, err)
+	default:
+		return errorResult(ctx, err)
+	}
 }
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/middleware"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/notification"
+	"github.com/personal-project/pitch-league/repository"
+)
+
+const teamInvitationTTL = 7 * 24 * time.Hour
+
+type TeamInvitationHandler struct {
+	invitationRepository repository.ITeamInvitationRepository
+	teamRepository       repository.ITeamRepository
+	userRepository       repository.IUserRepository
+	notifier             notification.Notifier
+}
+
+func NewTeamInvitationHandler(ir repository.ITeamInvitationRepository, tr repository.ITeamRepository, ur repository.IUserRepository, notifier notification.Notifier) TeamInvitationHandler {
+	return TeamInvitationHandler{
+		invitationRepository: ir,
+		teamRepository:       tr,
+		userRepository:       ur,
+		notifier:             notifier,
+	}
+}
+
+// InviteToTeam kaptanın kullanıcı adı veya e-posta ile bir oyuncuyu takıma davet etmesini sağlar
+func (h TeamInvitationHandler) InviteToTeam(ctx *fiber.Ctx) error {
+	teamID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	claims, ok := middleware.Claims(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	var vm models.TeamInviteVM
+	if err := ctx.BodyParser(&vm); err != nil {
+		return badRequestResult(ctx, err)
+	}
+	if vm.UserName == "" && vm.Email == "" {
+		return badRequestResult(ctx, errors.New("kullanıcı adı veya e-posta gerekli"))
+	}
+
+	var user models.User
+	if vm.UserName != "" {
+		user, err = h.userRepository.GetByUserName(ctx.Context(), vm.UserName)
+	} else {
+		user, err = h.userRepository.GetByEmail(ctx.Context(), vm.Email)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFoundResult(ctx)
+	}
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	team, err := h.teamRepository.GetByTeamID(ctx.Context(), teamID)
+	if err != nil {
+		return teamMemberErrorResult(ctx, err)
+	}
+
+	invitation := models.TeamInvitation{
+		TeamID:    teamID,
+		UserID:    user.ID,
+		Kind:      models.TeamInvitationKindInvite,
+		CreatedBy: claims.UserID,
+		ExpiresAt: time.Now().Add(teamInvitationTTL),
+	}
+	if err := h.invitationRepository.CreateTeamInvitation(ctx.Context(), &invitation); err != nil {
+		return teamInvitationErrorResult(ctx, err)
+	}
+
+	h.notify(ctx.Context(), user, "Takım daveti", fmt.Sprintf("%s takımına davet edildiniz.", team.Name))
+
+	vmResult := models.TeamInvitationDetailVM{}
+	return successResult(ctx, vmResult.FromDBModel(invitation))
+}
+
+// RequestToJoinTeam oyuncunun takıma katılma isteği göndermesini sağlar, kaptan onaylayınca üye olur
+func (h TeamInvitationHandler) RequestToJoinTeam(ctx *fiber.Ctx) error {
+	teamID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	// Kullanıcı sadece kendisi için istek gönderebilir
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	team, err := h.teamRepository.GetByTeamID(ctx.Context(), teamID)
+	if err != nil {
+		return teamMemberErrorResult(ctx, err)
+	}
+
+	invitation := models.TeamInvitation{
+		TeamID:    teamID,
+		UserID:    userID,
+		Kind:      models.TeamInvitationKindRequest,
+		CreatedBy: userID,
+		ExpiresAt: time.Now().Add(teamInvitationTTL),
+	}
+	if err := h.invitationRepository.CreateTeamInvitation(ctx.Context(), &invitation); err != nil {
+		return teamInvitationErrorResult(ctx, err)
+	}
+
+	if team.Captain != nil {
+		h.notify(ctx.Context(), *team.Captain, "Takıma katılım isteği", fmt.Sprintf("%s takımınıza katılmak için yeni bir istek var.", team.Name))
+	}
+
+	vm := models.TeamInvitationDetailVM{}
+	return successResult(ctx, vm.FromDBModel(invitation))
+}
+
+// GetTeamInvitations takımın bekleyen davetlerini ve katılım isteklerini getirir
+func (h TeamInvitationHandler) GetTeamInvitations(ctx *fiber.Ctx) error {
+	teamID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	invitations, err := h.invitationRepository.GetPendingTeamInvitationsByTeam(ctx.Context(), teamID)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	return successResult(ctx, toTeamInvitationVMs(invitations))
+}
+
+// GetMyInvitations bana gelen davetleri ve gönderdiğim katılım isteklerini getirir
+func (h TeamInvitationHandler) GetMyInvitations(ctx *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	invitations, err := h.invitationRepository.GetPendingTeamInvitationsByUser(ctx.Context(), userID)
+	if err != nil {
+		return errorResult(ctx, err)
+	}
+
+	return successResult(ctx, toTeamInvitationVMs(invitations))
+}
+
+func (h TeamInvitationHandler) AcceptInvitation(ctx *fiber.Ctx) error {
+	claims, ok := middleware.Claims(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	invitation, err := h.invitationFromRequest(ctx)
+	if err != nil {
+		return teamInvitationErrorResult(ctx, err)
+	}
+
+	if !canRespondTeamInvitation(claims, *invitation) {
+		return forbiddenResult(ctx, errors.New("bu daveti yanıtlama yetkiniz yok"))
+	}
+
+	if err := h.invitationRepository.AcceptTeamInvitation(ctx.Context(), invitation.ID, claims.UserID); err != nil {
+		return teamInvitationErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Oyuncu takıma katıldı")
+}
+
+func (h TeamInvitationHandler) DeclineInvitation(ctx *fiber.Ctx) error {
+	claims, ok := middleware.Claims(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	invitation, err := h.invitationFromRequest(ctx)
+	if err != nil {
+		return teamInvitationErrorResult(ctx, err)
+	}
+
+	if !canRespondTeamInvitation(claims, *invitation) {
+		return forbiddenResult(ctx, errors.New("bu daveti yanıtlama yetkiniz yok"))
+	}
+
+	if err := h.invitationRepository.RespondTeamInvitation(ctx.Context(), invitation.ID, claims.UserID, models.TeamInvitationStatusDeclined); err != nil {
+		return teamInvitationErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Davet reddedildi")
+}
+
+// CancelInvitation daveti gönderen tarafın geri çekmesini sağlar
+func (h TeamInvitationHandler) CancelInvitation(ctx *fiber.Ctx) error {
+	claims, ok := middleware.Claims(ctx)
+	if !ok {
+		return unauthorizedResult(ctx, errors.New("yetkisiz erişim"))
+	}
+
+	invitation, err := h.invitationFromRequest(ctx)
+	if err != nil {
+		return teamInvitationErrorResult(ctx, err)
+	}
+
+	if !canCancelTeamInvitation(claims, *invitation) {
+		return forbiddenResult(ctx, errors.New("bu daveti iptal etme yetkiniz yok"))
+	}
+
+	if err := h.invitationRepository.RespondTeamInvitation(ctx.Context(), invitation.ID, claims.UserID, models.TeamInvitationStatusCancelled); err != nil {
+		return teamInvitationErrorResult(ctx, err)
+	}
+
+	return successResult(ctx, "Davet iptal edildi")
+}
+
+func (h TeamInvitationHandler) invitationFromRequest(ctx *fiber.Ctx) (*models.TeamInvitation, error) {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.invitationRepository.GetTeamInvitation(ctx.Context(), id)
+}
+
+// notify bildirim hatasını loglar, davet akışını bozmaz
+func (h TeamInvitationHandler) notify(ctx context.Context, user models.User, subject, body string) {
+	channel, target := user.VerificationTarget()
+	err := h.notifier.Send(ctx, notification.Message{
+		Channel: string(channel),
+		To:      target,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		slog.Warn("takım daveti bildirimi gönderilemedi", "user_id", user.ID, "error", err)
+	}
+}
+
+// canRespondTeamInvitation: davetleri davet edilen oyuncu, katılım isteklerini takım kaptanı yanıtlar
+func canRespondTeamInvitation(claims *models.AccessTokenClaims, invitation models.TeamInvitation) bool {
+	if invitation.Kind == models.TeamInvitationKindInvite {
+		return invitation.UserID == claims.UserID
+	}
+	return invitation.Team != nil &&
+		middleware.CanManage(claims, models.PermissionTeamsManage, invitation.Team.CaptainID)
+}
+
+// canCancelTeamInvitation: davetleri takım kaptanı, katılım isteklerini isteği gönderen oyuncu iptal eder
+func canCancelTeamInvitation(claims *models.AccessTokenClaims, invitation models.TeamInvitation) bool {
+	if invitation.Kind == models.TeamInvitationKindRequest {
+		return invitation.UserID == claims.UserID
+	}
+	return invitation.Team != nil &&
+		middleware.CanManage(claims, models.PermissionTeamsManage, invitation.Team.CaptainID)
+}
+
+func toTeamInvitationVMs(invitations []models.TeamInvitation) []models.TeamInvitationDetailVM {
+	result := make([]models.TeamInvitationDetailVM, 0, len(invitations))
+	for _, invitation := range invitations {
+		vm := models.TeamInvitationDetailVM{}
+		result = append(result, vm.FromDBModel(invitation))
+	}
+	return result
+}
+
+// teamInvitationErrorResult davet hatalarını uygun HTTP durum kodlarına çevirir
+func teamInvitationErrorResult(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrTeamInvitationNotFound):
+		return notFoundResult(ctx)
+	case errors.Is(err, repository.ErrTeamInvitationExists),
+		errors.Is(err, repository.ErrTeamInvitationNotPending),
+		errors.Is(err, repository.ErrTeamInvitationExpired):
+		return conflictResult(ctx, err)
+	default:
+		return teamMemberErrorResult(ctx, err)
+	}
+}
@@ -1,21 +1,27 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gofiber/fiber/v2"
 	"github.com/personal-project/pitch-league/models"
 	"github.com/personal-project/pitch-league/repository"
 	"strconv"
+	"time"
 )
 
 type UserHandler struct {
 	BaseHandler[models.User]
+	authRepository  repository.IAuthRepository
+	revocationStore repository.ITokenRevocationStore
 }
 
-func NewUserHandler(repo repository.IBaseRepository[models.User]) UserHandler {
+func NewUserHandler(repo repository.IBaseRepository[models.User], ar repository.IAuthRepository, rs repository.ITokenRevocationStore) UserHandler {
 	return UserHandler{
-		BaseHandler[models.User]{
+		BaseHandler: BaseHandler[models.User]{
 			baseRepository: repo,
 		},
+		authRepository:  ar,
+		revocationStore: rs,
 	}
 }
 
@@ -27,6 +33,9 @@ func (h UserHandler) CreateUser(ctx *fiber.Ctx) error {
 
 	user := createModel.ToModel()
 	user.Role = models.UserRoleNormal
+	// Admin tarafından oluşturulan hesaplar doğrulanmış kabul edilir
+	now := time.Now()
+	user.VerifiedAt = &now
 
 	createdUser, err := h.baseRepository.Create(ctx.Context(), user)
 	if err != nil {
@@ -44,6 +53,9 @@ func (h UserHandler) CreateAdmin(ctx *fiber.Ctx) error {
 
 	user := createModel.ToModel()
 	user.Role = models.UserRoleAdmin
+	// Admin tarafından oluşturulan hesaplar doğrulanmış kabul edilir
+	now := time.Now()
+	user.VerifiedAt = &now
 
 	createdUser, err := h.baseRepository.Create(ctx.Context(), user)
 	if err != nil {
@@ -92,6 +104,14 @@ func (h UserHandler) DeleteByUserID(ctx *fiber.Ctx) error {
 		return errorResult(ctx, err)
 	}
 
+	// Silinen kullanıcının açık tokenları hemen geçersiz olmalı
+	if err := revokeUserAccessTokens(ctx.Context(), h.authRepository, h.revocationStore, id); err != nil {
+		return errorResult(ctx, err)
+	}
+	if err := h.authRepository.DeleteAuthRefreshToken(ctx.Context(), id); err != nil {
+		return errorResult(ctx, err)
+	}
+
 	return successResult(ctx, "User deleted successfully")
 }
 
@@ -111,11 +131,23 @@ func (h UserHandler) UpdateUserByID(ctx *fiber.Ctx) error {
 		return errorResult(ctx, err)
 	}
 
+	if !updateModel.Role.IsValid() {
+		return badRequestResult(ctx, errors.New("geçersiz rol"))
+	}
+
+	previousRole := user.Role
 	updatedUser := updateModel.ToModel(user)
 	err = h.baseRepository.Update(ctx.Context(), updatedUser)
 	if err != nil {
 		return errorResult(ctx, err)
 	}
 
+	// Rol değiştiyse eski roldeki access tokenlar iptal edilir, refresh ile yeni rol alınır
+	if previousRole != updatedUser.Role {
+		if err := revokeUserAccessTokens(ctx.Context(), h.authRepository, h.revocationStore, updatedUser.ID); err != nil {
+			return errorResult(ctx, err)
+		}
+	}
+
 	return successResult(ctx, models.ToUserResponse(updatedUser))
 }
@@ -4,11 +4,40 @@ import (
 	"github.com/gofiber/fiber/v2"
 	jwtware "github.com/gofiber/jwt/v3"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/repository"
 )
 
-func JWTMiddleware(secret string) fiber.Handler {
+func JWTMiddleware(secret string, revocationStore repository.ITokenRevocationStore) fiber.Handler {
 	return jwtware.New(jwtware.Config{
 		SigningKey: []byte(secret),
+		Claims:     &models.AccessTokenClaims{},
+		SuccessHandler: func(c *fiber.Ctx) error {
+			claims, ok := Claims(c)
+			if !ok {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"success": false,
+					"error":   "Yetkisiz erişim",
+				})
+			}
+
+			// Çıkış yapılmış veya yetkisi değişmiş kullanıcıların tokenları iptal listesindedir
+			revoked, err := revocationStore.IsRevoked(c.Context(), claims)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"success": false,
+					"error":   err.Error(),
+				})
+			}
+			if revoked {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"success": false,
+					"error":   "Token iptal edilmiş",
+				})
+			}
+
+			return c.Next()
+		},
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"success": false,
@@ -18,25 +47,35 @@ func JWTMiddleware(secret string) fiber.Handler {
 	})
 }
 
-func AdminControl(c *fiber.Ctx) error {
-	user := c.Locals("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-
-	role, ok := claims["role"].(float64)
-
+// Claims JWTMiddleware tarafından doğrulanan access token bilgilerini döner
+func Claims(c *fiber.Ctx) (*models.AccessTokenClaims, bool) {
+	token, ok := c.Locals("user").(*jwt.Token)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"error":   "Yetkiniz bulunmamaktadır",
-		})
+		return nil, false
 	}
 
-	if role != 10 {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"success": false,
-			"error":   "Yetkiniz yok",
-		})
-	}
+	claims, ok := token.Claims.(*models.AccessTokenClaims)
+	return claims, ok
+}
 
-	return c.Next()
+// RequirePermission kullanıcının rolü verilen yetkilerden en az birine sahip değilse isteği reddeder
+func RequirePermission(permissions ...models.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := Claims(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   "Yetkiniz bulunmamaktadır",
+			})
+		}
+
+		if !claims.Role.HasAnyPermission(permissions...) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"error":   "Yetkiniz yok",
+			})
+		}
+
+		return c.Next()
+	}
 }
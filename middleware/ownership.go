@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/repository"
+)
+
+// CurrentUserID JWT'den isteği yapan kullanıcının id'sini döner
+func CurrentUserID(c *fiber.Ctx) (int64, bool) {
+	claims, ok := Claims(c)
+	if !ok {
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+// RequireTeamCaptain ":id" parametresindeki takımın kaptanı değilse isteği reddeder, teams:manage yetkisi olanlar her takımı yönetebilir
+func RequireTeamCaptain(teamRepository repository.ITeamRepository) fiber.Handler {
+	return requireOwner(models.PermissionTeamsManage, func(c *fiber.Ctx, id int64) (int64, error) {
+		team, err := teamRepository.GetByTeamID(c.Context(), id)
+		if err != nil {
+			return 0, err
+		}
+		return team.CaptainID, nil
+	})
+}
+
+// RequireGameHost ":id" parametresindeki oyunun hostu değilse isteği reddeder, games:manage yetkisi olanlar her oyunu yönetebilir
+func RequireGameHost(gameRepository repository.IGameRepository) fiber.Handler {
+	return requireOwner(models.PermissionGamesManage, func(c *fiber.Ctx, id int64) (int64, error) {
+		game, err := gameRepository.GetByGameID(c.Context(), id)
+		if err != nil {
+			return 0, err
+		}
+		return int64(game.HostID), nil
+	})
+}
+
+// RequireFieldOwner ":id" parametresindeki sahanın sahibi değilse isteği reddeder, fields:manage yetkisi olanlar her sahayı yönetebilir
+func RequireFieldOwner(fieldRepository repository.IFieldRepository) fiber.Handler {
+	return requireOwner(models.PermissionFieldsManage, func(c *fiber.Ctx, id int64) (int64, error) {
+		field, err := fieldRepository.GetByFieldID(c.Context(), id)
+		if err != nil {
+			return 0, err
+		}
+		if field.OwnerID == nil {
+			return 0, nil
+		}
+		return *field.OwnerID, nil
+	})
+}
+
+// CanManage kullanıcının kaynağı yönetip yönetemeyeceğini söyler: ya genel yetkisi vardır ya da kaynağın sahibidir.
+// Sahiplik rolden bağımsızdır, kaptan veya host olan her kullanıcı kendi kaynağını yönetebilir.
+func CanManage(claims *models.AccessTokenClaims, anyPermission models.Permission, ownerID int64) bool {
+	if claims.Role.HasPermission(anyPermission) {
+		return true
+	}
+	return ownerID != 0 && ownerID == claims.UserID
+}
+
+func requireOwner(anyPermission models.Permission, ownerOf func(c *fiber.Ctx, id int64) (int64, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := Claims(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   "Yetkiniz bulunmamaktadır",
+			})
+		}
+
+		if claims.Role.HasPermission(anyPermission) {
+			return c.Next()
+		}
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Geçersiz id",
+			})
+		}
+
+		ownerID, err := ownerOf(c, id)
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"success": false,
+				"error":   "not found",
+			})
+		}
+
+		if !CanManage(claims, anyPermission, ownerID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"error":   "Bu kaynak size ait değil",
+			})
+		}
+
+		return c.Next()
+	}
+}
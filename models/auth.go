@@ -9,41 +9,90 @@ import (
 )
 
 type AuthRefreshToken struct {
-	bun.BaseModel `bun:"table:auth_refresh_tokens"`
-	TokenID       uuid.UUID `bun:"token_id,pk"`
-	UserID        int64     `bun:"user_id"`
-	Role          float64   `bun:"role"`
-	ExpiresAt     time.Time `bun:"expires_at"`
+	bun.BaseModel   `bun:"table:auth_refresh_tokens"`
+	TokenID         uuid.UUID  `bun:"token_id,pk"`
+	FamilyID        uuid.UUID  `bun:"family_id,notnull"` // aynı login'den rotasyonla türeyen tokenlar, yani oturum (session) id'si
+	UserID          int64      `bun:"user_id"`
+	Role            UserRole   `bun:"role"`
+	ExpiresAt       time.Time  `bun:"expires_at"`
+	ConsumedAt      *time.Time `bun:"consumed_at,nullzero"`
+	RevokedAt       *time.Time `bun:"revoked_at,nullzero"`
+	AccessTokenID   uuid.UUID  `bun:"access_token_id,nullzero"` // bu refresh token ile birlikte üretilen access token'ın jti'si
+	AccessExpiresAt time.Time  `bun:"access_expires_at,nullzero"`
+	DeviceName      string     `bun:"device_name"`
+	UserAgent       string     `bun:"user_agent"`
+	IP              string     `bun:"ip"`
+	LastUsedAt      time.Time  `bun:"last_used_at,nullzero,notnull,default:current_timestamp"`
+	CreatedAt       time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 }
 
 func (AuthRefreshToken) ModelName() string {
 	return "user_refresh_token"
 }
 
+func (t AuthRefreshToken) IsConsumed() bool {
+	return t.ConsumedAt != nil
+}
+
+func (t AuthRefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
 // AuthTokenPair defines the structure for access and refresh tokens
 type AuthTokenPair struct {
-	AccessToken  string
-	RefreshToken string
+	AccessToken          string
+	AccessTokenID        uuid.UUID
+	AccessTokenExpiresAt time.Time
+	RefreshToken         string
+}
+
+// RevokedAccessToken süresi dolmadan geçersiz kılınan access tokenları tutar
+type RevokedAccessToken struct {
+	bun.BaseModel `bun:"table:revoked_access_tokens"`
+	TokenID       uuid.UUID `bun:"token_id,pk"`
+	UserID        int64     `bun:"user_id,notnull"`
+	ExpiresAt     time.Time `bun:"expires_at,notnull"`
+	RevokedAt     time.Time `bun:"revoked_at,nullzero,notnull,default:current_timestamp"`
+}
+
+func (RevokedAccessToken) ModelName() string {
+	return "revoked_access_tokens"
+}
+
+// AccessTokenCutoff kullanıcının RevokedBefore anından önce üretilen access tokenlarını geçersiz kılar.
+// SessionID uuid.Nil ise kullanıcının tüm oturumları, değilse yalnızca o oturum etkilenir.
+type AccessTokenCutoff struct {
+	bun.BaseModel `bun:"table:access_token_cutoffs"`
+	UserID        int64     `bun:"user_id,pk"`
+	SessionID     uuid.UUID `bun:"session_id,pk"`
+	RevokedBefore time.Time `bun:"revoked_before,notnull"`
+	ExpiresAt     time.Time `bun:"expires_at,notnull"`
+}
+
+func (AccessTokenCutoff) ModelName() string {
+	return "access_token_cutoffs"
 }
 
 type AccessTokenClaims struct {
 	jwt.RegisteredClaims
-	ID     uuid.UUID `json:"id"`
-	UserID int64     `json:"uid"`
-	Role   float64   `json:"role"`
+	ID        uuid.UUID `json:"id"`
+	SessionID uuid.UUID `json:"sid"`
+	UserID    int64     `json:"uid"`
+	Role      UserRole  `json:"role"`
 }
 
 type RefreshTokenClaims struct {
 	jwt.RegisteredClaims
 	ID     uuid.UUID `json:"id"`
 	UserID int64     `json:"uid"`
-	Role   float64   `json:"role"`
+	Role   UserRole  `json:"role"`
 }
 
 type AuthLoginVM struct {
-	Email    string `json:"email" validate:"required_without=Phone,omitempty,max=64,email"`
-	Phone    string `json:"phone" validate:"required_without=Email,omitempty,max=11,numeric"`
-	Password string `json:"password" validate:"required" label:"Parola"`
+	Email      string `json:"email" validate:"required_without=Phone,omitempty,max=64,email"`
+	Phone      string `json:"phone" validate:"required_without=Email,omitempty,max=11,numeric"`
+	Password   string `json:"password" validate:"required" label:"Parola"`
+	DeviceName string `json:"device_name" validate:"max=100"`
 }
 
 type AuthTokenVM struct {
@@ -54,3 +103,35 @@ type AuthTokenVM struct {
 type AuthRefreshVM struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
+
+type AuthVerifyVM struct {
+	Email string `json:"email" validate:"required_without=Phone,omitempty,max=64,email"`
+	Phone string `json:"phone" validate:"required_without=Email,omitempty,max=11,numeric"`
+	Code  string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type AuthResendVerificationVM struct {
+	Email string `json:"email" validate:"required_without=Phone,omitempty,max=64,email"`
+	Phone string `json:"phone" validate:"required_without=Email,omitempty,max=11,numeric"`
+}
+
+type AuthSessionVM struct {
+	ID         uuid.UUID `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+func (vm AuthSessionVM) FromDBModel(m AuthRefreshToken, currentSessionID uuid.UUID) AuthSessionVM {
+	vm.ID = m.FamilyID
+	vm.DeviceName = m.DeviceName
+	vm.UserAgent = m.UserAgent
+	vm.IP = m.IP
+	vm.LastUsedAt = m.LastUsedAt
+	vm.ExpiresAt = m.ExpiresAt
+	vm.Current = m.FamilyID == currentSessionID
+	return vm
+}
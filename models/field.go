@@ -12,6 +12,9 @@ type Field struct {
 	PricePerHour  float64 `bun:"price_per_hour,notnull" json:"price_per_hour"`
 	Capacity      int64   `bun:"capacity,notnull" json:"capacity"`
 	Available     bool    `bun:"available,notnull" json:"available"`
+	OwnerID       *int64  `bun:"owner_id,nullzero" json:"owner_id"`
+	SlotMinutes   int64   `bun:"slot_minutes,notnull,default:60" json:"slot_minutes"`
+	Timezone      string  `bun:"timezone,notnull,default:'Europe/Istanbul'" json:"timezone"`
 }
 
 type FieldCreateVM struct {
@@ -20,6 +23,7 @@ type FieldCreateVM struct {
 	PricePerHour float64 `json:"price_per_hour" validate:"required"`
 	Capacity     int64   `json:"capacity" validate:"required"`
 	Available    bool    `json:"available" validate:"omitempty"`
+	OwnerID      *int64  `json:"owner_id" validate:"omitempty"`
 }
 
 func (vm FieldCreateVM) ToDBModel(m Field) Field {
@@ -28,6 +32,7 @@ func (vm FieldCreateVM) ToDBModel(m Field) Field {
 	m.PricePerHour = vm.PricePerHour
 	m.Capacity = vm.Capacity
 	m.Available = vm.Available
+	m.OwnerID = vm.OwnerID
 	return m
 }
 
@@ -38,6 +43,9 @@ type FieldDetailVM struct {
 	PricePerHour float64 `json:"price_per_hour"`
 	Capacity     int64   `json:"capacity"`
 	Available    bool    `json:"available"`
+	OwnerID      *int64  `json:"owner_id"`
+	SlotMinutes  int64   `json:"slot_minutes"`
+	Timezone     string  `json:"timezone"`
 }
 
 func (vm FieldDetailVM) FromDBModel(m Field) FieldDetailVM {
@@ -47,6 +55,9 @@ func (vm FieldDetailVM) FromDBModel(m Field) FieldDetailVM {
 	vm.PricePerHour = m.PricePerHour
 	vm.Capacity = m.Capacity
 	vm.Available = m.Available
+	vm.OwnerID = m.OwnerID
+	vm.SlotMinutes = m.SlotMinutes
+	vm.Timezone = m.Timezone
 	return vm
 }
 
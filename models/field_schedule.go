@@ -0,0 +1,171 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/personal-project/pitch-league/availability"
+	"github.com/personal-project/pitch-league/utils"
+	"github.com/uptrace/bun"
+)
+
+// FieldOpeningHours bir sahanın bir hafta günündeki açılış aralığıdır, saatler gün başından itibaren dakikadır
+type FieldOpeningHours struct {
+	bun.BaseModel `bun:"table:field_opening_hours,alias:foh"`
+	ID            int64        `bun:"id,pk,autoincrement" json:"id"`
+	FieldID       uint         `bun:"field_id,notnull" json:"field_id"`
+	Weekday       time.Weekday `bun:"weekday,notnull" json:"weekday"`
+	OpensAt       int64        `bun:"opens_at,notnull" json:"opens_at"`
+	ClosesAt      int64        `bun:"closes_at,notnull" json:"closes_at"`
+}
+
+// FieldBlackout sahanın bakım, turnuva vb. sebeplerle kapalı olduğu dönemdir
+type FieldBlackout struct {
+	bun.BaseModel `bun:"table:field_blackouts,alias:fb"`
+	ID            int64     `bun:"id,pk,autoincrement" json:"id"`
+	FieldID       uint      `bun:"field_id,notnull" json:"field_id"`
+	StartsAt      time.Time `bun:"starts_at,notnull" json:"starts_at"`
+	EndsAt        time.Time `bun:"ends_at,notnull" json:"ends_at"`
+	Reason        string    `bun:"reason,notnull,default:''" json:"reason"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// Schedule sahanın ayarlarından ve açılış saatlerinden haftalık çalışma düzenini kurar
+func (f Field) Schedule(hours []FieldOpeningHours) availability.Schedule {
+	loc, err := time.LoadLocation(f.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	schedule := availability.Schedule{
+		Location:   loc,
+		SlotLength: time.Duration(f.SlotMinutes) * time.Minute,
+	}
+	for _, h := range hours {
+		schedule.Windows = append(schedule.Windows, availability.Window{
+			Weekday: h.Weekday,
+			Opens:   time.Duration(h.OpensAt) * time.Minute,
+			Closes:  time.Duration(h.ClosesAt) * time.Minute,
+		})
+	}
+	return schedule
+}
+
+func (b FieldBlackout) Slot() availability.Slot {
+	return availability.Slot{Start: b.StartsAt, End: b.EndsAt}
+}
+
+type FieldOpeningHoursVM struct {
+	Weekday  string `json:"weekday"`   // örn. "monday" veya "pazartesi"
+	OpensAt  string `json:"opens_at"`  // örn. "09:00"
+	ClosesAt string `json:"closes_at"` // örn. "23:00", gece yarısına kadar açıksa "24:00"
+}
+
+// FieldScheduleVM sahanın slot uzunluğu, saat dilimi ve haftalık açılış saatleridir.
+// OpeningHours boş bırakılırsa saha her gün 24 saat açık sayılır.
+type FieldScheduleVM struct {
+	SlotMinutes  int64                 `json:"slot_minutes" validate:"required"`
+	Timezone     string                `json:"timezone" validate:"required"`
+	OpeningHours []FieldOpeningHoursVM `json:"opening_hours"`
+}
+
+func (vm FieldScheduleVM) FromDBModel(f Field, hours []FieldOpeningHours) FieldScheduleVM {
+	vm.SlotMinutes = f.SlotMinutes
+	vm.Timezone = f.Timezone
+	vm.OpeningHours = make([]FieldOpeningHoursVM, 0, len(hours))
+	for _, h := range hours {
+		vm.OpeningHours = append(vm.OpeningHours, FieldOpeningHoursVM{
+			Weekday:  strings.ToLower(h.Weekday.String()),
+			OpensAt:  formatMinutes(h.OpensAt),
+			ClosesAt: formatMinutes(h.ClosesAt),
+		})
+	}
+	return vm
+}
+
+// ToDBModel isteği doğrulayıp sahanın ayarlarını ve açılış saatlerini döner
+func (vm FieldScheduleVM) ToDBModel(f Field) (Field, []FieldOpeningHours, error) {
+	if vm.SlotMinutes < 15 || vm.SlotMinutes > 24*60 {
+		return f, nil, errors.New("slot süresi 15 dakika ile 24 saat arasında olmalı")
+	}
+	if _, err := time.LoadLocation(vm.Timezone); err != nil || vm.Timezone == "" {
+		return f, nil, errors.New("geçersiz saat dilimi")
+	}
+
+	hours := make([]FieldOpeningHours, 0, len(vm.OpeningHours))
+	for _, h := range vm.OpeningHours {
+		weekday, err := utils.ParseWeekday(h.Weekday)
+		if err != nil {
+			return f, nil, err
+		}
+		opens, err := parseMinutes(h.OpensAt)
+		if err != nil {
+			return f, nil, err
+		}
+		closes, err := parseMinutes(h.ClosesAt)
+		if err != nil {
+			return f, nil, err
+		}
+		if closes <= opens {
+			return f, nil, fmt.Errorf("%s için kapanış saati açılıştan sonra olmalı", h.Weekday)
+		}
+
+		// Aynı gündeki aralıklar çakışamaz
+		for _, other := range hours {
+			if other.Weekday == weekday && opens < other.ClosesAt && other.OpensAt < closes {
+				return f, nil, fmt.Errorf("%s için açılış saatleri çakışıyor", h.Weekday)
+			}
+		}
+
+		hours = append(hours, FieldOpeningHours{
+			FieldID:  uint(f.ID),
+			Weekday:  weekday,
+			OpensAt:  opens,
+			ClosesAt: closes,
+		})
+	}
+
+	f.SlotMinutes = vm.SlotMinutes
+	f.Timezone = vm.Timezone
+	return f, hours, nil
+}
+
+type FieldBlackoutCreateVM struct {
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required"`
+	Reason   string    `json:"reason" validate:"max=255"`
+}
+
+func (vm FieldBlackoutCreateVM) ToDBModel(m FieldBlackout) FieldBlackout {
+	m.StartsAt = vm.StartsAt
+	m.EndsAt = vm.EndsAt
+	m.Reason = vm.Reason
+	return m
+}
+
+type FieldAvailabilityVM struct {
+	FieldID     uint                `json:"field_id"`
+	Timezone    string              `json:"timezone"`
+	SlotMinutes int64               `json:"slot_minutes"`
+	From        time.Time           `json:"from"`
+	To          time.Time           `json:"to"`
+	Slots       []availability.Slot `json:"slots"`
+}
+
+// parseMinutes "HH:MM" saatini gün başından itibaren dakikaya çevirir, gün sonu için "24:00" kabul edilir
+func parseMinutes(s string) (int64, error) {
+	if strings.TrimSpace(s) == "24:00" {
+		return 24 * 60, nil
+	}
+	d, err := utils.ParseClock(s)
+	if err != nil {
+		return 0, fmt.Errorf("geçersiz saat: %s", s)
+	}
+	return int64(d / time.Minute), nil
+}
+
+func formatMinutes(m int64) string {
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/personal-project/pitch-league/fixtures"
+	"github.com/personal-project/pitch-league/utils"
+)
+
+// FixtureGenerateVM fikstür üretme isteğidir, aynı gövde önizleme, kaydetme ve yeniden üretmede kullanılır
+type FixtureGenerateVM struct {
+	DoubleRoundRobin bool `json:"double_round_robin"`
+	// Weekdays maç oynanabilecek günlerdir, örn. ["saturday", "sunday"] veya ["cumartesi"]
+	Weekdays []string `json:"weekdays" validate:"required"`
+	// KickoffTimes bir maç günündeki başlama saatleridir, örn. ["19:00", "21:00"]
+	KickoffTimes []string `json:"kickoff_times" validate:"required"`
+	// Timezone saatlerin yorumlanacağı bölgedir, verilmezse sunucunun bölgesi kullanılır
+	Timezone string `json:"timezone"`
+	// FieldID verilirse bütün maçlar bu sahada oynanır: sahanın dolu saatleri atlanır ve kaydederken saha ayrılır
+	FieldID uint `json:"field_id"`
+	// MatchMinutes sahada bir maç için ayrılan süredir, verilmezse DefaultFixtureMatchMinutes kullanılır
+	MatchMinutes int64 `json:"match_minutes"`
+}
+
+const DefaultFixtureMatchMinutes = 90
+
+// FixtureBooking fikstür maçları için sahada açılacak oyunların bilgisidir
+type FixtureBooking struct {
+	FieldID  uint
+	HostID   uint
+	Duration time.Duration
+}
+
+// Booking fikstür tek bir sahaya yerleşecekse o saha için açılacak oyunların bilgisini döner, saha seçilmediyse nil
+func (vm FixtureGenerateVM) Booking(hostID uint) *FixtureBooking {
+	if vm.FieldID == 0 {
+		return nil
+	}
+	return &FixtureBooking{FieldID: vm.FieldID, HostID: hostID, Duration: vm.MatchDuration()}
+}
+
+func (vm FixtureGenerateVM) MatchDuration() time.Duration {
+	if vm.MatchMinutes <= 0 {
+		return DefaultFixtureMatchMinutes * time.Minute
+	}
+	return time.Duration(vm.MatchMinutes) * time.Minute
+}
+
+// Calendar isteği ligin başlangıç ve bitiş tarihleri arasındaki bir takvime çevirir, bitiş günü dahildir
+func (vm FixtureGenerateVM) Calendar(league League) (fixtures.Calendar, error) {
+	loc := time.Local
+	if vm.Timezone != "" {
+		l, err := time.LoadLocation(vm.Timezone)
+		if err != nil {
+			return fixtures.Calendar{}, errors.New("geçersiz saat dilimi")
+		}
+		loc = l
+	}
+
+	calendar := fixtures.Calendar{Location: loc}
+	for _, d := range vm.Weekdays {
+		weekday, err := utils.ParseWeekday(d)
+		if err != nil {
+			return fixtures.Calendar{}, err
+		}
+		calendar.Weekdays = append(calendar.Weekdays, weekday)
+	}
+	for _, k := range vm.KickoffTimes {
+		kickoff, err := utils.ParseClock(k)
+		if err != nil {
+			return fixtures.Calendar{}, err
+		}
+		calendar.KickoffTimes = append(calendar.KickoffTimes, kickoff)
+	}
+
+	if vm.FieldID != 0 {
+		calendar.MatchDuration = vm.MatchDuration()
+	}
+
+	start := league.StartDate.In(loc)
+	end := league.EndDate.In(loc)
+	calendar.Start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	calendar.End = time.Date(end.Year(), end.Month(), end.Day(), 23, 59, 59, 0, loc)
+	return calendar, nil
+}
+
+type FixtureMatchVM struct {
+	HomeTeamID uint      `json:"home_team_id"`
+	AwayTeamID uint      `json:"away_team_id"`
+	MatchTime  time.Time `json:"match_time"`
+}
+
+type FixtureRoundVM struct {
+	Round     int              `json:"round"`
+	ByeTeamID uint             `json:"bye_team_id,omitempty"`
+	Matches   []FixtureMatchVM `json:"matches"`
+}
+
+// FixtureRoundsFromSchedule üretilen maçları haftalara göre gruplar, bay geçen takımlar haftaya eklenir
+func FixtureRoundsFromSchedule(rounds []fixtures.Round, scheduled []fixtures.Fixture) []FixtureRoundVM {
+	byRound := make(map[int][]FixtureMatchVM, len(rounds))
+	for _, f := range scheduled {
+		byRound[f.Round] = append(byRound[f.Round], FixtureMatchVM{
+			HomeTeamID: f.HomeTeamID,
+			AwayTeamID: f.AwayTeamID,
+			MatchTime:  f.MatchTime,
+		})
+	}
+
+	result := make([]FixtureRoundVM, 0, len(rounds))
+	for _, round := range rounds {
+		matches, ok := byRound[round.Number]
+		if !ok {
+			continue
+		}
+		result = append(result, FixtureRoundVM{
+			Round:     round.Number,
+			ByeTeamID: round.ByeTeamID,
+			Matches:   matches,
+		})
+	}
+	return result
+}
+
+// FixtureMatches üretilen fikstürü lige ait planlanmış maçlara çevirir
+func FixtureMatches(leagueID uint, scheduled []fixtures.Fixture) []Match {
+	matches := make([]Match, 0, len(scheduled))
+	for _, f := range scheduled {
+		matches = append(matches, Match{
+			LeagueID:   leagueID,
+			HomeTeamID: f.HomeTeamID,
+			AwayTeamID: f.AwayTeamID,
+			MatchTime:  f.MatchTime,
+			Status:     MatchStatusScheduled,
+			Round:      int64(f.Round),
+		})
+	}
+	return matches
+}
@@ -16,26 +16,87 @@ const (
 	GameStatusFinished  GameStatus = "FINISHED"
 )
 
+// gameStatusTransitions her durumdan geçilebilecek durumlardır. Reddedilen, iptal edilen ve biten oyunların durumu bir daha değişmez.
+var gameStatusTransitions = map[GameStatus][]GameStatus{
+	GameStatusPending:  {GameStatusAccepted, GameStatusRejected, GameStatusCancelled},
+	GameStatusAccepted: {GameStatusCancelled, GameStatusFinished},
+}
+
+// CanTransitionTo oyunun s durumundan next durumuna geçip geçemeyeceğini söyler
+func (s GameStatus) CanTransitionTo(next GameStatus) bool {
+	for _, allowed := range gameStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s GameStatus) IsValid() bool {
+	switch s {
+	case GameStatusPending, GameStatusAccepted, GameStatusRejected, GameStatusCancelled, GameStatusFinished:
+		return true
+	}
+	return false
+}
+
 type Game struct {
 	bun.BaseModel `bun:"table:games,alias:g"`
+	ID            int64       `bun:"id,pk,autoincrement" json:"id"`
+	FieldID       uint        `bun:"field_id,notnull" json:"field_id"`
+	HostID        uint        `bun:"host_id,notnull" json:"host_id"`
+	StartTime     time.Time   `bun:"start_time,notnull" json:"start_time"`
+	EndTime       time.Time   `bun:"end_time,notnull" json:"end_time"`
+	MaxPlayers    int64       `bun:"max_players,notnull" json:"max_players"`
+	Status        GameStatus  `bun:"status,notnull" json:"status"`
+	Host          *User       `bun:"rel:has-one,join:host_id=id" json:"host"`
+	Field         *Field      `bun:"rel:has-one,join:field_id=id" json:"field"`
+	Scores        []GameScore `bun:"rel:has-many,join:id=game_id" json:"scores"`
+}
+
+// GameScore biten bir oyunda bir takımın attığı gol sayısıdır
+type GameScore struct {
+	bun.BaseModel `bun:"table:game_scores,alias:gs"`
+	ID            int64 `bun:"id,pk,autoincrement" json:"-"`
+	GameID        int64 `bun:"game_id,notnull" json:"-"`
+	TeamID        uint  `bun:"team_id,notnull" json:"team_id"`
+	Score         int64 `bun:"score,notnull" json:"score"`
+}
+
+// GameStatusChange oyunun durumunu kimin, ne zaman, hangi durumdan hangi duruma değiştirdiğini tutar.
+// ChangedBy boşsa değişikliği sistem yapmıştır (süresi dolan rezervasyon, yeniden üretilen fikstür...).
+type GameStatusChange struct {
+	bun.BaseModel `bun:"table:game_status_changes,alias:gsc"`
 	ID            int64      `bun:"id,pk,autoincrement" json:"id"`
-	FieldID       uint       `bun:"field_id,notnull" json:"field_id"`
-	HostID        uint       `bun:"host_id,notnull" json:"host_id"`
-	StartTime     time.Time  `bun:"start_time,notnull" json:"start_time"`
-	EndTime       time.Time  `bun:"end_time,notnull" json:"end_time"`
-	MaxPlayers    int64      `bun:"max_players,notnull" json:"max_players"`
-	Status        GameStatus `bun:"status,notnull" json:"status"`
-	Host          *User      `bun:"rel:has-one,join:host_id=id" json:"host"`
-	Field         *Field     `bun:"rel:has-one,join:field_id=id" json:"field"`
+	GameID        int64      `bun:"game_id,notnull" json:"game_id"`
+	FromStatus    GameStatus `bun:"from_status,notnull" json:"from_status"`
+	ToStatus      GameStatus `bun:"to_status,notnull" json:"to_status"`
+	ChangedBy     *int64     `bun:"changed_by,nullzero" json:"changed_by"`
+	Reason        string     `bun:"reason,notnull,default:''" json:"reason"`
+	CreatedAt     time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	// NotifiedAt oyunculara bildirim gönderilmek üzere alındığı zamandır, bildirim gerektirmeyen değişikliklerde boş kalır
+	NotifiedAt *time.Time `bun:"notified_at,nullzero" json:"-"`
 }
 
+// NotifiedGameStatuses oyunculara bildirilen durumlardır. Değişikliği kimin yaptığına bakılmaz, sistemin iptal
+// ettiği oyunlar da (süresi dolan rezervasyon, ertelenen maç, yeniden üretilen fikstür) bildirilir.
+var NotifiedGameStatuses = []GameStatus{GameStatusCancelled, GameStatusRejected}
+
+// GameStatusNotification oyuncularına bildirilecek bir durum değişikliğini oyun ve oyuncularıyla birlikte tutar
+type GameStatusNotification struct {
+	Change GameStatusChange
+	Game   Game
+	Users  []User
+}
+
+// GameCreateVM oyunu oluşturur veya günceller. Durum buradan değiştirilemez: yeni oyunlar PENDING başlar,
+// sonrası GameStatusUpdateVM ile izin verilen geçişlerle ilerler.
 type GameCreateVM struct {
-	FieldID    uint       `json:"field_id" validate:"required"`
-	HostID     uint       `json:"host_id" validate:"required"`
-	StartTime  time.Time  `json:"start_time" validate:"required"`
-	EndTime    time.Time  `json:"end_time" validate:"required"`
-	MaxPlayers int64      `json:"max_players" validate:"required"`
-	Status     GameStatus `json:"status" validate:"required"`
+	FieldID    uint      `json:"field_id" validate:"required"`
+	HostID     uint      `json:"host_id" validate:"required"`
+	StartTime  time.Time `json:"start_time" validate:"required"`
+	EndTime    time.Time `json:"end_time" validate:"required"`
+	MaxPlayers int64     `json:"max_players" validate:"required"`
 }
 
 func (vm GameCreateVM) ToDBModel(m Game) Game {
@@ -44,10 +105,33 @@ func (vm GameCreateVM) ToDBModel(m Game) Game {
 	m.StartTime = vm.StartTime
 	m.EndTime = vm.EndTime
 	m.MaxPlayers = vm.MaxPlayers
-	m.Status = vm.Status
+	if m.Status == "" {
+		m.Status = GameStatusPending
+	}
 	return m
 }
 
+type GameScoreVM struct {
+	TeamID uint  `json:"team_id" validate:"required"`
+	Score  int64 `json:"score"`
+}
+
+// GameStatusUpdateVM oyunun yeni durumudur. FINISHED için oyundaki her takımın skoru girilmelidir,
+// lig maçına bağlı oyunlarda skor maçın sonucundan gelir.
+type GameStatusUpdateVM struct {
+	Status GameStatus    `json:"status" validate:"required"`
+	Reason string        `json:"reason" validate:"max=255"`
+	Scores []GameScoreVM `json:"scores"`
+}
+
+func (vm GameStatusUpdateVM) ToDBModel(gameID int64) []GameScore {
+	scores := make([]GameScore, 0, len(vm.Scores))
+	for _, s := range vm.Scores {
+		scores = append(scores, GameScore{GameID: gameID, TeamID: s.TeamID, Score: s.Score})
+	}
+	return scores
+}
+
 type GameDetailVM struct {
 	ID         int64       `json:"id"`
 	FieldID    uint        `json:"field_id"`
@@ -58,6 +142,7 @@ type GameDetailVM struct {
 	Status     GameStatus  `json:"status"`
 	Host       interface{} `json:"host"`
 	Field      *Field      `json:"field"`
+	Scores     []GameScore `json:"scores,omitempty"`
 }
 
 func (vm GameDetailVM) FromDBModel(m Game) GameDetailVM {
@@ -70,6 +155,7 @@ func (vm GameDetailVM) FromDBModel(m Game) GameDetailVM {
 	vm.Status = m.Status
 	vm.Host = m.Host
 	vm.Field = m.Field
+	vm.Scores = m.Scores
 	return vm
 }
 
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+type Leaderboard string
+
+const (
+	LeaderboardScorers     Leaderboard = "scorers"
+	LeaderboardAssists     Leaderboard = "assists"
+	LeaderboardCleanSheets Leaderboard = "clean_sheets"
+	LeaderboardCards       Leaderboard = "cards"
+)
+
+var Leaderboards = []Leaderboard{LeaderboardScorers, LeaderboardAssists, LeaderboardCleanSheets, LeaderboardCards}
+
+func (l Leaderboard) IsValid() bool {
+	for _, board := range Leaderboards {
+		if board == l {
+			return true
+		}
+	}
+	return false
+}
+
+// LeaderboardFilter sıralamaların hesaplanacağı maçları daraltır. Sıfır zaman ve sıfır takım filtre uygulanmaz demektir.
+type LeaderboardFilter struct {
+	LeagueID uint
+	TeamID   uint
+	From     time.Time // dahil
+	To       time.Time // hariç
+	Limit    int
+	Offset   int
+}
+
+// PlayerLeaderboardRow gol veya asist sıralamasında bir oyuncunun bir takım adına satırıdır
+type PlayerLeaderboardRow struct {
+	Rank     int64  `bun:"rank" json:"rank"`
+	UserID   uint   `bun:"user_id" json:"user_id"`
+	Name     string `bun:"name" json:"name"`
+	Surname  string `bun:"surname" json:"surname"`
+	UserName string `bun:"username" json:"username"`
+	TeamID   uint   `bun:"team_id" json:"team_id"`
+	TeamName string `bun:"team_name" json:"team_name"`
+	Count    int64  `bun:"count" json:"count"`     // gol veya asist sayısı
+	Matches  int64  `bun:"matches" json:"matches"` // gol veya asist yapılan maç sayısı
+}
+
+// CleanSheetRow takımın gol yemediği tamamlanmış maçlarıdır
+type CleanSheetRow struct {
+	Rank        int64  `bun:"rank" json:"rank"`
+	TeamID      uint   `bun:"team_id" json:"team_id"`
+	TeamName    string `bun:"team_name" json:"team_name"`
+	CleanSheets int64  `bun:"clean_sheets" json:"clean_sheets"`
+	Matches     int64  `bun:"matches" json:"matches"`
+}
+
+// CardRow oyuncunun gördüğü kartlar ve bunların disiplin puanıdır
+type CardRow struct {
+	Rank        int64  `bun:"rank" json:"rank"`
+	UserID      uint   `bun:"user_id" json:"user_id"`
+	Name        string `bun:"name" json:"name"`
+	Surname     string `bun:"surname" json:"surname"`
+	UserName    string `bun:"username" json:"username"`
+	TeamID      uint   `bun:"team_id" json:"team_id"`
+	TeamName    string `bun:"team_name" json:"team_name"`
+	YellowCards int64  `bun:"yellow_cards" json:"yellow_cards"`
+	RedCards    int64  `bun:"red_cards" json:"red_cards"`
+	Points      int64  `bun:"points" json:"points"`
+}
+
+// LeaderboardPage bir sıralamanın istenen sayfasıdır, Total filtreye uyan toplam satır sayısıdır
+type LeaderboardPage[T any] struct {
+	Total int64 `json:"total"`
+	Rows  []T   `json:"rows"`
+}
+
+type LeaderboardsVM struct {
+	LeagueID    uint                                   `json:"league_id"`
+	Page        int                                    `json:"page"`
+	Limit       int                                    `json:"limit"`
+	Scorers     *LeaderboardPage[PlayerLeaderboardRow] `json:"scorers,omitempty"`
+	Assists     *LeaderboardPage[PlayerLeaderboardRow] `json:"assists,omitempty"`
+	CleanSheets *LeaderboardPage[CleanSheetRow]        `json:"clean_sheets,omitempty"`
+	Cards       *LeaderboardPage[CardRow]              `json:"cards,omitempty"`
+}
@@ -1,8 +1,11 @@
 package models
 
 import (
+	"errors"
 	"time"
 
+	"github.com/personal-project/pitch-league/discipline"
+	"github.com/personal-project/pitch-league/standings"
 	"github.com/uptrace/bun"
 )
 
@@ -13,6 +16,17 @@ type League struct {
 	Location      string    `bun:"location,notnull" json:"location"`
 	StartDate     time.Time `bun:"start_date,notnull" json:"start_date"`
 	EndDate       time.Time `bun:"end_date,notnull" json:"end_date"`
+	// Puanlama ve eşitlik kuralları, puan durumu bu kurallarla hesaplanır
+	PointsPerWin           int64    `bun:"points_per_win,notnull,default:3" json:"points_per_win"`
+	PointsPerDraw          int64    `bun:"points_per_draw,notnull,default:1" json:"points_per_draw"`
+	PointsPerLoss          int64    `bun:"points_per_loss,notnull,default:0" json:"points_per_loss"`
+	ForfeitGoalsFor        int64    `bun:"forfeit_goals_for,notnull,default:3" json:"forfeit_goals_for"`
+	ForfeitGoalsAgainst    int64    `bun:"forfeit_goals_against,notnull,default:0" json:"forfeit_goals_against"`
+	ForfeitPointsDeduction int64    `bun:"forfeit_points_deduction,notnull,default:0" json:"forfeit_points_deduction"`
+	Tiebreakers            []string `bun:"tiebreakers,array" json:"tiebreakers"`
+	// Kart cezası kuralları
+	YellowCardsPerBan int64 `bun:"yellow_cards_per_ban,notnull,default:3" json:"yellow_cards_per_ban"`
+	RedCardBanMatches int64 `bun:"red_card_ban_matches,notnull,default:1" json:"red_card_ban_matches"`
 }
 
 type LeagueCreateVM struct {
@@ -20,6 +34,8 @@ type LeagueCreateVM struct {
 	Location  string    `json:"location" validate:"required"`
 	StartDate time.Time `json:"start_date" validate:"required"`
 	EndDate   time.Time `json:"end_date" validate:"required"`
+	// Rules verilmezse varsayılan kurallar (3/1/0, averaj, atılan gol) kullanılır
+	Rules *LeagueRulesVM `json:"rules"`
 }
 
 func (vm LeagueCreateVM) ToDBModel(m League) League {
@@ -27,15 +43,85 @@ func (vm LeagueCreateVM) ToDBModel(m League) League {
 	m.Location = vm.Location
 	m.StartDate = vm.StartDate
 	m.EndDate = vm.EndDate
+
+	// Önce varsayılan kurallar yazılır, Rules içinde verilmeyen alanlar varsayılan kalır
+	m = DefaultLeagueRulesVM().ToDBModel(m)
+	if vm.Rules != nil {
+		m = vm.Rules.ToDBModel(m)
+	}
+	return m
+}
+
+// LeagueRulesVM kısmi güncellemedir: verilmeyen (null) alanlarda ligin mevcut (yeni ligde varsayılan) kuralı korunur
+type LeagueRulesVM struct {
+	PointsPerWin           *int64 `json:"points_per_win"`
+	PointsPerDraw          *int64 `json:"points_per_draw"`
+	PointsPerLoss          *int64 `json:"points_per_loss"`
+	ForfeitGoalsFor        *int64 `json:"forfeit_goals_for"`
+	ForfeitGoalsAgainst    *int64 `json:"forfeit_goals_against"`
+	ForfeitPointsDeduction *int64 `json:"forfeit_points_deduction"`
+	// Tiebreakers verilmezse ligin mevcut (yeni ligde varsayılan) eşitlik sırası korunur, boş liste eşitlik kuralı olmadığı anlamına gelir
+	Tiebreakers       []string `json:"tiebreakers"`
+	YellowCardsPerBan *int64   `json:"yellow_cards_per_ban"`
+	RedCardBanMatches *int64   `json:"red_card_ban_matches"`
+}
+
+func DefaultLeagueRulesVM() LeagueRulesVM {
+	return LeagueRulesVM{}.FromRules(standings.DefaultRules()).FromDisciplineRules(discipline.DefaultRules())
+}
+
+func (vm LeagueRulesVM) FromRules(r standings.Rules) LeagueRulesVM {
+	vm.PointsPerWin = &r.PointsPerWin
+	vm.PointsPerDraw = &r.PointsPerDraw
+	vm.PointsPerLoss = &r.PointsPerLoss
+	vm.ForfeitGoalsFor = &r.ForfeitGoalsFor
+	vm.ForfeitGoalsAgainst = &r.ForfeitGoalsAgainst
+	vm.ForfeitPointsDeduction = &r.ForfeitPointsDeduction
+	vm.Tiebreakers = make([]string, 0, len(r.Tiebreakers))
+	for _, t := range r.Tiebreakers {
+		vm.Tiebreakers = append(vm.Tiebreakers, string(t))
+	}
+	return vm
+}
+
+func (vm LeagueRulesVM) FromDisciplineRules(r discipline.Rules) LeagueRulesVM {
+	vm.YellowCardsPerBan = &r.YellowCardsPerBan
+	vm.RedCardBanMatches = &r.RedCardBanMatches
+	return vm
+}
+
+func (vm LeagueRulesVM) ToDBModel(m League) League {
+	setIfGiven(&m.PointsPerWin, vm.PointsPerWin)
+	setIfGiven(&m.PointsPerDraw, vm.PointsPerDraw)
+	setIfGiven(&m.PointsPerLoss, vm.PointsPerLoss)
+	setIfGiven(&m.ForfeitGoalsFor, vm.ForfeitGoalsFor)
+	setIfGiven(&m.ForfeitGoalsAgainst, vm.ForfeitGoalsAgainst)
+	setIfGiven(&m.ForfeitPointsDeduction, vm.ForfeitPointsDeduction)
+	// tiebreakers kolonu NOT NULL olduğu için nil liste yazılmaz
+	switch {
+	case vm.Tiebreakers != nil:
+		m.Tiebreakers = vm.Tiebreakers
+	case m.Tiebreakers == nil:
+		m.Tiebreakers = DefaultLeagueRulesVM().Tiebreakers
+	}
+	setIfGiven(&m.YellowCardsPerBan, vm.YellowCardsPerBan)
+	setIfGiven(&m.RedCardBanMatches, vm.RedCardBanMatches)
 	return m
 }
 
+func setIfGiven(dst *int64, v *int64) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
 type LeagueDetailVM struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Location  string    `json:"location"`
-	StartDate time.Time `json:"start_date"`
-	EndDate   time.Time `json:"end_date"`
+	ID        int64         `json:"id"`
+	Name      string        `json:"name"`
+	Location  string        `json:"location"`
+	StartDate time.Time     `json:"start_date"`
+	EndDate   time.Time     `json:"end_date"`
+	Rules     LeagueRulesVM `json:"rules"`
 }
 
 func (vm LeagueDetailVM) FromDBModel(m League) LeagueDetailVM {
@@ -44,6 +130,7 @@ func (vm LeagueDetailVM) FromDBModel(m League) LeagueDetailVM {
 	vm.Location = m.Location
 	vm.StartDate = m.StartDate
 	vm.EndDate = m.EndDate
+	vm.Rules = LeagueRulesVM{}.FromRules(m.StandingsRules()).FromDisciplineRules(m.DisciplineRules())
 	return vm
 }
 
@@ -51,6 +138,37 @@ func (League) ModelName() string {
 	return "leagues"
 }
 
+// StandingsRules ligin kurallarını puan durumu motorunun kullandığı yapıya çevirir
+func (l League) StandingsRules() standings.Rules {
+	rules := standings.Rules{
+		PointsPerWin:           l.PointsPerWin,
+		PointsPerDraw:          l.PointsPerDraw,
+		PointsPerLoss:          l.PointsPerLoss,
+		ForfeitGoalsFor:        l.ForfeitGoalsFor,
+		ForfeitGoalsAgainst:    l.ForfeitGoalsAgainst,
+		ForfeitPointsDeduction: l.ForfeitPointsDeduction,
+		Tiebreakers:            make([]standings.Tiebreaker, 0, len(l.Tiebreakers)),
+		LotSeed:                l.ID,
+	}
+	for _, t := range l.Tiebreakers {
+		rules.Tiebreakers = append(rules.Tiebreakers, standings.Tiebreaker(t))
+	}
+	return rules
+}
+
+// ValidateRules ligin puanlama ve kart cezası kurallarını doğrular
+func (l League) ValidateRules() error {
+	return errors.Join(l.StandingsRules().Validate(), l.DisciplineRules().Validate())
+}
+
+// DisciplineRules ligin kart cezası kurallarını ceza motorunun kullandığı yapıya çevirir
+func (l League) DisciplineRules() discipline.Rules {
+	return discipline.Rules{
+		YellowCardsPerBan: l.YellowCardsPerBan,
+		RedCardBanMatches: l.RedCardBanMatches,
+	}
+}
+
 func (l League) String() string {
 	return l.Name + " " + l.Location
 }
@@ -8,13 +8,23 @@ import (
 
 type LeagueTeam struct {
 	bun.BaseModel `bun:"table:league_teams,alias:lt"`
-	ID            int64   `bun:"id,pk,autoincrement" json:"id"`
-	LeagueID      uint    `bun:"league_id,notnull" json:"league_id"`
-	TeamID        uint    `bun:"team_id,notnull" json:"team_id"`
-	Points        int64   `bun:"points,default:0" json:"points"`
-	Rank          int64   `bun:"rank" json:"rank"`
-	League        *League `bun:"rel:has-one,join:league_id=id" json:"league"`
-	Team          *Team   `bun:"rel:has-one,join:team_id=id" json:"team"`
+	ID            int64  `bun:"id,pk,autoincrement" json:"id"`
+	LeagueID      uint   `bun:"league_id,notnull" json:"league_id"`
+	TeamID        uint   `bun:"team_id,notnull" json:"team_id"`
+	Points        int64  `bun:"points,default:0" json:"points"`
+	Rank          int64  `bun:"rank" json:"rank"`
+	Played        int64  `bun:"played,notnull,default:0" json:"played"`
+	Won           int64  `bun:"won,notnull,default:0" json:"won"`
+	Drawn         int64  `bun:"drawn,notnull,default:0" json:"drawn"`
+	Lost          int64  `bun:"lost,notnull,default:0" json:"lost"`
+	GoalsFor      int64  `bun:"goals_for,notnull,default:0" json:"goals_for"`
+	GoalsAgainst  int64  `bun:"goals_against,notnull,default:0" json:"goals_against"`
+	GoalDiff      int64  `bun:"goal_difference,notnull,default:0" json:"goal_difference"`
+	Form          string `bun:"form,notnull,default:''" json:"form"` // son 5 maç, en eskiden en yeniye (W/D/L)
+	// DecidedBy takım üstündeki takımla puanca eşitse sırayı belirleyen eşitlik kuralıdır
+	DecidedBy string  `bun:"decided_by,notnull,default:''" json:"decided_by"`
+	League    *League `bun:"rel:has-one,join:league_id=id" json:"league"`
+	Team      *Team   `bun:"rel:has-one,join:team_id=id" json:"team"`
 }
 
 type LeagueTeamCreateVM struct {
@@ -33,13 +43,21 @@ func (vm LeagueTeamCreateVM) ToDBModel(m LeagueTeam) LeagueTeam {
 }
 
 type LeagueTeamDetailVM struct {
-	ID       int64   `json:"id"`
-	LeagueID uint    `json:"league_id"`
-	TeamID   uint    `json:"team_id"`
-	Points   int64   `json:"points"`
-	Rank     int64   `json:"rank"`
-	League   *League `json:"league"`
-	Team     *Team   `json:"team"`
+	ID           int64   `json:"id"`
+	LeagueID     uint    `json:"league_id"`
+	TeamID       uint    `json:"team_id"`
+	Points       int64   `json:"points"`
+	Rank         int64   `json:"rank"`
+	Played       int64   `json:"played"`
+	Won          int64   `json:"won"`
+	Drawn        int64   `json:"drawn"`
+	Lost         int64   `json:"lost"`
+	GoalsFor     int64   `json:"goals_for"`
+	GoalsAgainst int64   `json:"goals_against"`
+	GoalDiff     int64   `json:"goal_difference"`
+	Form         string  `json:"form"`
+	League       *League `json:"league"`
+	Team         *Team   `json:"team"`
 }
 
 func (vm LeagueTeamDetailVM) FromDBModel(m LeagueTeam) LeagueTeamDetailVM {
@@ -48,11 +66,55 @@ func (vm LeagueTeamDetailVM) FromDBModel(m LeagueTeam) LeagueTeamDetailVM {
 	vm.TeamID = m.TeamID
 	vm.Points = m.Points
 	vm.Rank = m.Rank
+	vm.Played = m.Played
+	vm.Won = m.Won
+	vm.Drawn = m.Drawn
+	vm.Lost = m.Lost
+	vm.GoalsFor = m.GoalsFor
+	vm.GoalsAgainst = m.GoalsAgainst
+	vm.GoalDiff = m.GoalDiff
+	vm.Form = m.Form
 	vm.League = m.League
 	vm.Team = m.Team
 	return vm
 }
 
+// LeagueStandingVM puan tablosunun tek satırıdır
+type LeagueStandingVM struct {
+	Rank         int64  `json:"rank"`
+	TeamID       uint   `json:"team_id"`
+	TeamName     string `json:"team_name"`
+	Played       int64  `json:"played"`
+	Won          int64  `json:"won"`
+	Drawn        int64  `json:"drawn"`
+	Lost         int64  `json:"lost"`
+	GoalsFor     int64  `json:"goals_for"`
+	GoalsAgainst int64  `json:"goals_against"`
+	GoalDiff     int64  `json:"goal_difference"`
+	Points       int64  `json:"points"`
+	Form         string `json:"form"`
+	DecidedBy    string `json:"decided_by,omitempty"`
+}
+
+func (vm LeagueStandingVM) FromDBModel(m LeagueTeam) LeagueStandingVM {
+	vm.Rank = m.Rank
+	vm.TeamID = m.TeamID
+	if m.Team != nil {
+		vm.TeamName = m.Team.Name
+	}
+	vm.Played = m.Played
+	vm.Won = m.Won
+	vm.Drawn = m.Drawn
+	vm.Lost = m.Lost
+	vm.GoalsFor = m.GoalsFor
+	vm.GoalsAgainst = m.GoalsAgainst
+	vm.GoalDiff = m.GoalDiff
+	vm.Points = m.Points
+	vm.Form = m.Form
+	vm.DecidedBy = m.DecidedBy
+	return vm
+}
+
 func (LeagueTeam) ModelName() string {
 	return "league_teams"
 }
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/uptrace/bun"
@@ -10,24 +12,74 @@ type MatchStatus string
 
 const (
 	MatchStatusScheduled MatchStatus = "SCHEDULED"
+	MatchStatusLive      MatchStatus = "LIVE"
 	MatchStatusCompleted MatchStatus = "COMPLETED"
+	MatchStatusPostponed MatchStatus = "POSTPONED" // ileri bir tarihe ertelendi, yeni tarih verilince tekrar SCHEDULED olur
+	MatchStatusAbandoned MatchStatus = "ABANDONED" // yarıda kaldı, tekrar oynanır veya sonucu sonradan verilir
+	MatchStatusForfeit   MatchStatus = "FORFEIT"   // bir takım hükmen kaybetti, ligin hükmen puan silme cezası da uygulanır
+	MatchStatusWalkover  MatchStatus = "WALKOVER"  // rakip gelmediği için maç verildi, hükmen skor yazılır ama puan silinmez
 )
 
+// matchStatusTransitions her durumdan geçilebilecek durumlardır. Hükmen sonuçlanan maçların durumu bir daha değişmez.
+var matchStatusTransitions = map[MatchStatus][]MatchStatus{
+	MatchStatusScheduled: {MatchStatusLive, MatchStatusCompleted, MatchStatusPostponed, MatchStatusForfeit, MatchStatusWalkover},
+	MatchStatusLive:      {MatchStatusCompleted, MatchStatusAbandoned, MatchStatusForfeit},
+	MatchStatusPostponed: {MatchStatusScheduled, MatchStatusForfeit, MatchStatusWalkover},
+	MatchStatusAbandoned: {MatchStatusScheduled, MatchStatusCompleted, MatchStatusForfeit},
+	MatchStatusCompleted: {MatchStatusForfeit},
+}
+
+func (s MatchStatus) CanTransitionTo(next MatchStatus) bool {
+	for _, allowed := range matchStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s MatchStatus) IsValid() bool {
+	switch s {
+	case MatchStatusScheduled, MatchStatusLive, MatchStatusCompleted, MatchStatusPostponed,
+		MatchStatusAbandoned, MatchStatusForfeit, MatchStatusWalkover:
+		return true
+	}
+	return false
+}
+
+// CountsInStandings maçın puan durumuna sayılıp sayılmadığını söyler
+func (s MatchStatus) CountsInStandings() bool {
+	return s == MatchStatusCompleted || s == MatchStatusForfeit || s == MatchStatusWalkover
+}
+
+// StandingsMatchStatuses puan durumuna sayılan maç durumlarıdır
+var StandingsMatchStatuses = []MatchStatus{MatchStatusCompleted, MatchStatusForfeit, MatchStatusWalkover}
+
+// UpcomingMatchStatuses sonucu henüz belli olmayan, oynanacak veya oynanmakta olan maç durumlarıdır
+var UpcomingMatchStatuses = []MatchStatus{MatchStatusScheduled, MatchStatusPostponed, MatchStatusLive}
+
+// UnplayedMatchStatuses henüz oynanmamış maç durumlarıdır, fikstür yeniden üretilirken bu maçlar silinebilir
+var UnplayedMatchStatuses = []MatchStatus{MatchStatusScheduled, MatchStatusPostponed}
+
 type Match struct {
 	bun.BaseModel `bun:"table:matches,alias:m"`
-	ID            int64     `bun:"id,pk,autoincrement" json:"id"`
-	LeagueID      uint      `bun:"league_id,notnull" json:"league_id"`
-	HomeTeamID    uint      `bun:"home_team_id,notnull" json:"home_team_id"`
-	AwayTeamID    uint      `bun:"away_team_id,notnull" json:"away_team_id"`
-	MatchTime     time.Time `bun:"match_time,notnull" json:"match_time"`
-	HomeScore     int64     `bun:"home_score,default:0" json:"home_score"`
-	AwayScore     int64     `bun:"away_score,default:0" json:"away_score"`
-	Status        string    `bun:"status,notnull" json:"status"`
-	GameID        uint      `bun:"game_id,notnull" json:"game_id"`
-	Game          *Game     `bun:"rel:has-one,join:game_id=id" json:"game"`
-	League        *League   `bun:"rel:has-one,join:league_id=id" json:"league"`
-	HomeTeam      *Team     `bun:"rel:has-one,join:home_team_id=id" json:"home_team"`
-	AwayTeam      *Team     `bun:"rel:has-one,join:away_team_id=id" json:"away_team"`
+	ID            int64       `bun:"id,pk,autoincrement" json:"id"`
+	LeagueID      uint        `bun:"league_id,notnull" json:"league_id"`
+	HomeTeamID    uint        `bun:"home_team_id,notnull" json:"home_team_id"`
+	AwayTeamID    uint        `bun:"away_team_id,notnull" json:"away_team_id"`
+	MatchTime     time.Time   `bun:"match_time,notnull" json:"match_time"`
+	HomeScore     int64       `bun:"home_score,default:0" json:"home_score"`
+	AwayScore     int64       `bun:"away_score,default:0" json:"away_score"`
+	Status        MatchStatus `bun:"status,notnull" json:"status"`
+	GameID        uint        `bun:"game_id,nullzero" json:"game_id"` // fikstürden üretilen maçlarda saha ayarlanana kadar boştur
+	Round         int64       `bun:"round,nullzero" json:"round,omitempty"`
+	ForfeitedBy   uint        `bun:"forfeited_by,nullzero" json:"forfeited_by,omitempty"` // FORFEIT ve WALKOVER maçlarda kaybeden takım
+	Game          *Game       `bun:"rel:has-one,join:game_id=id" json:"game"`
+	// ResultSubmissions kaptanların bildirdiği skorlardır
+	ResultSubmissions []MatchResultSubmission `bun:"rel:has-many,join:id=match_id" json:"result_submissions,omitempty"`
+	League            *League                 `bun:"rel:has-one,join:league_id=id" json:"league"`
+	HomeTeam          *Team                   `bun:"rel:has-one,join:home_team_id=id" json:"home_team"`
+	AwayTeam          *Team                   `bun:"rel:has-one,join:away_team_id=id" json:"away_team"`
 }
 
 type MatchCreateVM struct {
@@ -38,7 +90,8 @@ type MatchCreateVM struct {
 	GameID     uint      `json:"game_id" validate:"required"`
 	HomeScore  int64     `json:"home_score"`
 	AwayScore  int64     `json:"away_score"`
-	Status     string    `json:"status"`
+	// Status SCHEDULED (varsayılan) veya oynanmış bir maçı skoruyla kaydetmek için COMPLETED olabilir
+	Status MatchStatus `json:"status"`
 }
 
 func (vm MatchCreateVM) ToDBModel(m Match) Match {
@@ -50,23 +103,77 @@ func (vm MatchCreateVM) ToDBModel(m Match) Match {
 	m.HomeScore = vm.HomeScore
 	m.AwayScore = vm.AwayScore
 	m.Status = vm.Status
+	if m.Status == "" {
+		m.Status = MatchStatusScheduled
+	}
+	// Sadece tamamlanmış maçın skoru olur
+	if m.Status != MatchStatusCompleted {
+		m.HomeScore, m.AwayScore = 0, 0
+	}
 	return m
 }
 
+// MatchStatusUpdateVM maçı bir sonraki durumuna geçirir. FORFEIT ve WALKOVER için kaybeden takım, COMPLETED için
+// skor, ertelenen veya yarıda kalan maçı tekrar SCHEDULED yapmak için yeni maç zamanı verilmelidir.
+type MatchStatusUpdateVM struct {
+	Status      MatchStatus `json:"status" validate:"required"`
+	ForfeitedBy uint        `json:"forfeited_by"`
+	HomeScore   *int64      `json:"home_score"`
+	AwayScore   *int64      `json:"away_score"`
+	MatchTime   *time.Time  `json:"match_time"`
+}
+
+// ToDBModel isteği doğrulayıp maçın yeni halini döner
+func (vm MatchStatusUpdateVM) ToDBModel(m Match) (Match, error) {
+	if !vm.Status.IsValid() {
+		return m, fmt.Errorf("geçersiz maç durumu: %s", vm.Status)
+	}
+	m.Status = vm.Status
+	m.ForfeitedBy = 0
+
+	switch vm.Status {
+	case MatchStatusForfeit, MatchStatusWalkover:
+		if vm.ForfeitedBy != m.HomeTeamID && vm.ForfeitedBy != m.AwayTeamID {
+			return m, errors.New("forfeited_by maçın ev sahibi veya deplasman takımı olmalı")
+		}
+		m.ForfeitedBy = vm.ForfeitedBy
+		m.HomeScore, m.AwayScore = 0, 0
+	case MatchStatusCompleted:
+		if vm.HomeScore == nil || vm.AwayScore == nil {
+			return m, errors.New("maçı tamamlamak için home_score ve away_score gerekli")
+		}
+		if *vm.HomeScore < 0 || *vm.AwayScore < 0 {
+			return m, errors.New("skor negatif olamaz")
+		}
+		m.HomeScore, m.AwayScore = *vm.HomeScore, *vm.AwayScore
+	case MatchStatusScheduled:
+		if vm.MatchTime == nil || vm.MatchTime.IsZero() {
+			return m, errors.New("maçı yeniden planlamak için match_time gerekli")
+		}
+		m.MatchTime = *vm.MatchTime
+		m.HomeScore, m.AwayScore = 0, 0
+	}
+	return m, nil
+}
+
 type MatchDetailVM struct {
-	ID         int64     `json:"id"`
-	LeagueID   uint      `json:"league_id"`
-	HomeTeamID uint      `json:"home_team_id"`
-	AwayTeamID uint      `json:"away_team_id"`
-	MatchTime  time.Time `json:"match_time"`
-	HomeScore  int64     `json:"home_score"`
-	AwayScore  int64     `json:"away_score"`
-	Status     string    `json:"status"`
-	GameID     uint      `json:"game_id"`
-	Game       *Game     `json:"game"`
-	League     *League   `json:"league"`
-	HomeTeam   *Team     `json:"home_team"`
-	AwayTeam   *Team     `json:"away_team"`
+	ID          int64       `json:"id"`
+	LeagueID    uint        `json:"league_id"`
+	HomeTeamID  uint        `json:"home_team_id"`
+	AwayTeamID  uint        `json:"away_team_id"`
+	MatchTime   time.Time   `json:"match_time"`
+	HomeScore   int64       `json:"home_score"`
+	AwayScore   int64       `json:"away_score"`
+	Status      MatchStatus `json:"status"`
+	GameID      uint        `json:"game_id"`
+	Round       int64       `json:"round,omitempty"`
+	ForfeitedBy uint        `json:"forfeited_by,omitempty"`
+	Game        *Game       `json:"game"`
+	// ResultSubmissions kaptanların bildirdiği skorlardır
+	ResultSubmissions []MatchResultSubmission `json:"result_submissions,omitempty"`
+	League            *League                 `json:"league"`
+	HomeTeam          *Team                   `json:"home_team"`
+	AwayTeam          *Team                   `json:"away_team"`
 }
 
 func (vm MatchDetailVM) FromDBModel(m Match) MatchDetailVM {
@@ -79,7 +186,10 @@ func (vm MatchDetailVM) FromDBModel(m Match) MatchDetailVM {
 	vm.AwayScore = m.AwayScore
 	vm.Status = m.Status
 	vm.GameID = m.GameID
+	vm.Round = m.Round
+	vm.ForfeitedBy = m.ForfeitedBy
 	vm.Game = m.Game
+	vm.ResultSubmissions = m.ResultSubmissions
 	vm.League = m.League
 	vm.HomeTeam = m.HomeTeam
 	vm.AwayTeam = m.AwayTeam
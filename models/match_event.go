@@ -0,0 +1,120 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// MaxMatchEventMinute uzatmalarla birlikte bir olaya girilebilecek en geç dakikadır
+const MaxMatchEventMinute = 150
+
+type MatchEventType string
+
+const (
+	MatchEventGoal         MatchEventType = "GOAL"     // asist yapan oyuncu golle birlikte kaydedilir
+	MatchEventOwnGoal      MatchEventType = "OWN_GOAL" // gol oyuncunun rakibine yazılır
+	MatchEventYellowCard   MatchEventType = "YELLOW_CARD"
+	MatchEventRedCard      MatchEventType = "RED_CARD"
+	MatchEventSubstitution MatchEventType = "SUBSTITUTION" // oyuncu çıkar, yerine SubstituteParticipantID girer
+)
+
+// IsGoal olayın skoru değiştirip değiştirmediğini söyler
+func (t MatchEventType) IsGoal() bool {
+	return t == MatchEventGoal || t == MatchEventOwnGoal
+}
+
+func (t MatchEventType) IsValid() bool {
+	switch t {
+	case MatchEventGoal, MatchEventOwnGoal, MatchEventYellowCard, MatchEventRedCard, MatchEventSubstitution:
+		return true
+	}
+	return false
+}
+
+// FairPlayPoints olayın takımına yazılan disiplin puanıdır, puan durumundaki FAIR_PLAY kriterinde az olan önde olur
+func (t MatchEventType) FairPlayPoints() int64 {
+	switch t {
+	case MatchEventYellowCard:
+		return 1
+	case MatchEventRedCard:
+		return 3
+	}
+	return 0
+}
+
+// MatchEventStatuses olay girilebilen maç durumlarıdır
+var MatchEventStatuses = []MatchStatus{MatchStatusLive, MatchStatusCompleted, MatchStatusAbandoned, MatchStatusForfeit}
+
+// MatchEvent maçta bir oyuncunun golü, kartı veya oyundan çıkışıdır. Oyuncular maçın oyunundaki katılımcılardan seçilir.
+type MatchEvent struct {
+	bun.BaseModel           `bun:"table:match_events,alias:me"`
+	ID                      int64             `bun:"id,pk,autoincrement" json:"id"`
+	MatchID                 int64             `bun:"match_id,notnull" json:"match_id"`
+	Type                    MatchEventType    `bun:"type,notnull" json:"type"`
+	Minute                  int64             `bun:"minute,notnull" json:"minute"`
+	ParticipantID           int64             `bun:"participant_id,notnull" json:"participant_id"`
+	TeamID                  uint              `bun:"team_id,notnull" json:"team_id"` // katılımcının takımı, kendi kalesine golde gol rakibe yazılır
+	UserID                  uint              `bun:"user_id,notnull" json:"user_id"`
+	AssistParticipantID     int64             `bun:"assist_participant_id,nullzero" json:"assist_participant_id,omitempty"`
+	SubstituteParticipantID int64             `bun:"substitute_participant_id,nullzero" json:"substitute_participant_id,omitempty"`
+	CreatedBy               int64             `bun:"created_by,nullzero" json:"created_by"`
+	CreatedAt               time.Time         `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	Participant             *GameParticipants `bun:"rel:has-one,join:participant_id=id" json:"participant"`
+	AssistParticipant       *GameParticipants `bun:"rel:has-one,join:assist_participant_id=id" json:"assist_participant,omitempty"`
+	SubstituteParticipant   *GameParticipants `bun:"rel:has-one,join:substitute_participant_id=id" json:"substitute_participant,omitempty"`
+}
+
+// ScoresFor olayın ev sahibi veya deplasman skoruna eklediği golü döner
+func (e MatchEvent) ScoresFor(m Match) (home, away int64) {
+	switch {
+	case e.Type == MatchEventGoal && e.TeamID == m.HomeTeamID,
+		e.Type == MatchEventOwnGoal && e.TeamID == m.AwayTeamID:
+		return 1, 0
+	case e.Type == MatchEventGoal && e.TeamID == m.AwayTeamID,
+		e.Type == MatchEventOwnGoal && e.TeamID == m.HomeTeamID:
+		return 0, 1
+	}
+	return 0, 0
+}
+
+type MatchEventVM struct {
+	Type                    MatchEventType `json:"type" validate:"required"`
+	Minute                  int64          `json:"minute"`
+	ParticipantID           int64          `json:"participant_id" validate:"required"`
+	AssistParticipantID     int64          `json:"assist_participant_id"`
+	SubstituteParticipantID int64          `json:"substitute_participant_id"`
+}
+
+func (vm MatchEventVM) Validate() error {
+	if !vm.Type.IsValid() {
+		return fmt.Errorf("geçersiz olay tipi: %s", vm.Type)
+	}
+	if vm.Minute < 0 || vm.Minute > MaxMatchEventMinute {
+		return fmt.Errorf("dakika 0 ile %d arasında olmalı", MaxMatchEventMinute)
+	}
+	if vm.ParticipantID == 0 {
+		return errors.New("participant_id gerekli")
+	}
+	if vm.AssistParticipantID != 0 && (vm.Type != MatchEventGoal || vm.AssistParticipantID == vm.ParticipantID) {
+		return errors.New("asist sadece golü atan oyuncudan başka bir oyuncuya yazılabilir")
+	}
+	if (vm.Type == MatchEventSubstitution) != (vm.SubstituteParticipantID != 0) {
+		return errors.New("substitute_participant_id sadece oyuncu değişikliğinde verilir ve orada gereklidir")
+	}
+	if vm.SubstituteParticipantID != 0 && vm.SubstituteParticipantID == vm.ParticipantID {
+		return errors.New("oyuncu kendi yerine oyuna giremez")
+	}
+	return nil
+}
+
+func (vm MatchEventVM) ToDBModel(m MatchEvent) MatchEvent {
+	m.Type = vm.Type
+	m.Minute = vm.Minute
+	m.ParticipantID = vm.ParticipantID
+	m.AssistParticipantID = vm.AssistParticipantID
+	m.SubstituteParticipantID = vm.SubstituteParticipantID
+	return m
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// MatchResultSubmission bir takım kaptanının maç için bildirdiği skordur. İki takımın bildirimi aynıysa maç tamamlanır.
+type MatchResultSubmission struct {
+	bun.BaseModel `bun:"table:match_result_submissions,alias:mrs"`
+	ID            int64     `bun:"id,pk,autoincrement" json:"id"`
+	MatchID       int64     `bun:"match_id,notnull" json:"match_id"`
+	TeamID        uint      `bun:"team_id,notnull" json:"team_id"`
+	SubmittedBy   int64     `bun:"submitted_by,notnull" json:"submitted_by"`
+	HomeScore     int64     `bun:"home_score,notnull" json:"home_score"`
+	AwayScore     int64     `bun:"away_score,notnull" json:"away_score"`
+	SubmittedAt   time.Time `bun:"submitted_at,nullzero,notnull,default:current_timestamp" json:"submitted_at"`
+}
+
+// MatchResultVM maçın skorudur, iki skor da verilmelidir
+type MatchResultVM struct {
+	HomeScore *int64 `json:"home_score" validate:"required"`
+	AwayScore *int64 `json:"away_score" validate:"required"`
+}
+
+func (vm MatchResultVM) Validate() error {
+	if vm.HomeScore == nil || vm.AwayScore == nil {
+		return errors.New("home_score ve away_score gerekli")
+	}
+	if *vm.HomeScore < 0 || *vm.AwayScore < 0 {
+		return errors.New("skor negatif olamaz")
+	}
+	return nil
+}
+
+func (vm MatchResultVM) ToDBModel(m MatchResultSubmission) MatchResultSubmission {
+	m.HomeScore = *vm.HomeScore
+	m.AwayScore = *vm.AwayScore
+	return m
+}
+
+// SameScore iki bildirimin aynı skoru verip vermediğini söyler
+func (s MatchResultSubmission) SameScore(other MatchResultSubmission) bool {
+	return s.HomeScore == other.HomeScore && s.AwayScore == other.AwayScore
+}
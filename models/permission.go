@@ -0,0 +1,68 @@
+package models
+
+type Permission string
+
+const (
+	PermissionUsersManage            Permission = "users:manage"
+	PermissionTeamsManage            Permission = "teams:manage"
+	PermissionFieldsManage           Permission = "fields:manage"
+	PermissionGamesManage            Permission = "games:manage"
+	PermissionGamesHost              Permission = "games:host" // oyun açma ve host olunan oyunu yönetme
+	PermissionGameParticipantsManage Permission = "game_participants:manage"
+	PermissionLeaguesManage          Permission = "leagues:manage"
+	PermissionMatchesManage          Permission = "matches:manage"
+)
+
+// rolePermissions her rolün sahip olduğu yetkileri tutar, admin tüm yetkilere sahiptir
+var rolePermissions = map[UserRole][]Permission{
+	UserRoleNormal: {},
+	UserRoleCaptain: {
+		PermissionGamesHost,
+	},
+	UserRoleFieldOwner: {
+		PermissionGamesHost,
+	},
+	UserRoleLeagueOrganizer: {
+		PermissionLeaguesManage,
+		PermissionMatchesManage,
+		PermissionGamesHost,
+	},
+	UserRoleAdmin: {
+		PermissionUsersManage,
+		PermissionTeamsManage,
+		PermissionFieldsManage,
+		PermissionGamesManage,
+		PermissionGamesHost,
+		PermissionGameParticipantsManage,
+		PermissionLeaguesManage,
+		PermissionMatchesManage,
+	},
+}
+
+func (r UserRole) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+func (r UserRole) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+func (r UserRole) HasPermission(p Permission) bool {
+	for _, permission := range rolePermissions[r] {
+		if permission == p {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnyPermission verilen yetkilerden en az birine sahipse true döner
+func (r UserRole) HasAnyPermission(permissions ...Permission) bool {
+	for _, p := range permissions {
+		if r.HasPermission(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"github.com/uptrace/bun"
+)
+
+type PlayerPosition string
+
+const (
+	PlayerPositionGoalkeeper PlayerPosition = "GOALKEEPER"
+	PlayerPositionDefender   PlayerPosition = "DEFENDER"
+	PlayerPositionMidfielder PlayerPosition = "MIDFIELDER"
+	PlayerPositionForward    PlayerPosition = "FORWARD"
+)
+
+func (p PlayerPosition) IsValid() bool {
+	switch p {
+	case PlayerPositionGoalkeeper, PlayerPositionDefender, PlayerPositionMidfielder, PlayerPositionForward:
+		return true
+	}
+	return false
+}
+
+type StrongFoot string
+
+const (
+	StrongFootLeft  StrongFoot = "LEFT"
+	StrongFootRight StrongFoot = "RIGHT"
+	StrongFootBoth  StrongFoot = "BOTH"
+)
+
+func (f StrongFoot) IsValid() bool {
+	switch f {
+	case StrongFootLeft, StrongFootRight, StrongFootBoth:
+		return true
+	}
+	return false
+}
+
+const (
+	playerDateOfBirthLayout = "2006-01-02"
+	MaxPlayerBioLength      = 500
+	MaxPlayerAvatarURLLen   = 512
+)
+
+// PlayerProfile kullanıcının oyuncu bilgileridir, profilini hiç doldurmamış kullanıcıların satırı yoktur
+type PlayerProfile struct {
+	bun.BaseModel `bun:"table:player_profiles,alias:pp"`
+	UserID        int64          `bun:"user_id,pk" json:"user_id"`
+	Position      PlayerPosition `bun:"position,notnull,default:''" json:"position"`
+	StrongFoot    StrongFoot     `bun:"strong_foot,notnull,default:''" json:"strong_foot"`
+	DateOfBirth   *time.Time     `bun:"date_of_birth,type:date,nullzero" json:"date_of_birth"`
+	AvatarURL     string         `bun:"avatar_url,notnull,default:''" json:"avatar_url"`
+	Bio           string         `bun:"bio,notnull,default:''" json:"bio"`
+	UpdatedAt     time.Time      `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// PlayerProfileUpdateVM oyuncunun kendi profilini güncellemesi içindir, boş bırakılan alanlar profilden silinir.
+// date_of_birth "2006-01-02" biçimindedir.
+type PlayerProfileUpdateVM struct {
+	Position    PlayerPosition `json:"position" validate:"omitempty,oneof=GOALKEEPER DEFENDER MIDFIELDER FORWARD"`
+	StrongFoot  StrongFoot     `json:"strong_foot" validate:"omitempty,oneof=LEFT RIGHT BOTH"`
+	DateOfBirth string         `json:"date_of_birth"`
+	AvatarURL   string         `json:"avatar_url" validate:"omitempty,max=512,url"`
+	Bio         string         `json:"bio" validate:"max=500"`
+}
+
+func (vm PlayerProfileUpdateVM) Validate() error {
+	var errs []error
+	if vm.Position != "" && !vm.Position.IsValid() {
+		errs = append(errs, errors.New("geçersiz mevki: "+string(vm.Position)))
+	}
+	if vm.StrongFoot != "" && !vm.StrongFoot.IsValid() {
+		errs = append(errs, errors.New("geçersiz ayak: "+string(vm.StrongFoot)))
+	}
+	if vm.DateOfBirth != "" {
+		dob, err := time.Parse(playerDateOfBirthLayout, vm.DateOfBirth)
+		switch {
+		case err != nil:
+			errs = append(errs, errors.New("date_of_birth YYYY-AA-GG biçiminde olmalı"))
+		case !dob.Before(time.Now()) || dob.Year() < 1900:
+			errs = append(errs, errors.New("geçersiz doğum tarihi"))
+		}
+	}
+	if vm.AvatarURL != "" {
+		u, err := url.ParseRequestURI(vm.AvatarURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(vm.AvatarURL) > MaxPlayerAvatarURLLen {
+			errs = append(errs, errors.New("avatar_url geçerli bir http(s) adresi olmalı"))
+		}
+	}
+	if utf8.RuneCountInString(vm.Bio) > MaxPlayerBioLength {
+		errs = append(errs, errors.New("bio en fazla 500 karakter olabilir"))
+	}
+	return errors.Join(errs...)
+}
+
+// ToDBModel Validate'den geçmiş bir isteği profile uygular
+func (vm PlayerProfileUpdateVM) ToDBModel(m PlayerProfile) PlayerProfile {
+	m.Position = vm.Position
+	m.StrongFoot = vm.StrongFoot
+	m.DateOfBirth = nil
+	if dob, err := time.Parse(playerDateOfBirthLayout, vm.DateOfBirth); err == nil {
+		m.DateOfBirth = &dob
+	}
+	m.AvatarURL = vm.AvatarURL
+	m.Bio = vm.Bio
+	return m
+}
+
+// PlayerTeamStats oyuncunun bir takımda oynadığı biten oyun sayısıdır
+type PlayerTeamStats struct {
+	TeamID   uint   `bun:"team_id" json:"team_id"`
+	TeamName string `bun:"team_name" json:"team_name"`
+	Games    int64  `bun:"games" json:"games"`
+}
+
+// PlayerCareerStats oyuncunun biten oyunlardaki ve lig maçlarındaki toplam istatistikleridir.
+// Goller ve asistler tamamlanmış maçlardan, kartlar lig sıralamasındaki gibi oynanan tüm maçlardan sayılır.
+type PlayerCareerStats struct {
+	GamesPlayed int64             `json:"games_played"`
+	GamesWon    int64             `json:"games_won"`
+	Goals       int64             `json:"goals"`
+	OwnGoals    int64             `json:"own_goals"`
+	Assists     int64             `json:"assists"`
+	YellowCards int64             `json:"yellow_cards"`
+	RedCards    int64             `json:"red_cards"`
+	Teams       []PlayerTeamStats `json:"teams"`
+	// Oyuncunun kadrosunda olduğu sürede takımının oynadığı lig maçlarından kaçında oyuna katıldığı.
+	// AttendanceRate yüzdedir, kadrodayken hiç lig maçı oynanmadıysa boş döner.
+	EligibleMatches int64    `json:"eligible_matches"`
+	AttendedMatches int64    `json:"attended_matches"`
+	AttendanceRate  *float64 `json:"attendance_rate"`
+}
+
+// PlayerDetailVM herkese açık oyuncu profilidir, e-posta ve telefon gibi iletişim bilgileri gösterilmez
+type PlayerDetailVM struct {
+	ID          int64             `json:"id"`
+	Name        string            `json:"name"`
+	Surname     string            `json:"surname"`
+	UserName    string            `json:"username"`
+	Position    PlayerPosition    `json:"position"`
+	StrongFoot  StrongFoot        `json:"strong_foot"`
+	DateOfBirth *string           `json:"date_of_birth"`
+	AvatarURL   string            `json:"avatar_url"`
+	Bio         string            `json:"bio"`
+	Stats       PlayerCareerStats `json:"stats"`
+}
+
+func (vm PlayerDetailVM) FromDBModel(u User, p PlayerProfile, stats PlayerCareerStats) PlayerDetailVM {
+	vm.ID = u.ID
+	vm.Name = u.Name
+	vm.Surname = u.Surname
+	vm.UserName = u.UserName
+	vm.Position = p.Position
+	vm.StrongFoot = p.StrongFoot
+	vm.DateOfBirth = nil
+	if p.DateOfBirth != nil {
+		dob := p.DateOfBirth.Format(playerDateOfBirthLayout)
+		vm.DateOfBirth = &dob
+	}
+	vm.AvatarURL = p.AvatarURL
+	vm.Bio = p.Bio
+	vm.Stats = stats
+	return vm
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"time"
+
+	"github.com/personal-project/pitch-league/booking"
+	"github.com/uptrace/bun"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationStatusHeld      ReservationStatus = "HELD"      // saha tutuldu, ödeme bekleniyor
+	ReservationStatusConfirmed ReservationStatus = "CONFIRMED" // ödeme alındı
+	ReservationStatusCancelled ReservationStatus = "CANCELLED"
+	ReservationStatusExpired   ReservationStatus = "EXPIRED" // süresi içinde onaylanmadı
+)
+
+type ReservationShareStatus string
+
+const (
+	ReservationShareStatusPending   ReservationShareStatus = "PENDING" // oyuncu payını rezervasyonu yapana henüz ödemedi
+	ReservationShareStatusPaid      ReservationShareStatus = "PAID"
+	ReservationShareStatusCancelled ReservationShareStatus = "CANCELLED"
+)
+
+// Reservation sahanın bir oyun için ücretli olarak ayrılmasıdır. Sahayı meşgul eden asıl kayıt Game'dir,
+// rezervasyon onun fiyatını, ödemesini ve iadesini tutar. Tutarlar kuruş cinsindendir.
+type Reservation struct {
+	bun.BaseModel `bun:"table:reservations,alias:r"`
+	ID            int64              `bun:"id,pk,autoincrement" json:"id"`
+	GameID        int64              `bun:"game_id,notnull" json:"game_id"`
+	FieldID       uint               `bun:"field_id,notnull" json:"field_id"`
+	UserID        int64              `bun:"user_id,notnull" json:"user_id"` // rezervasyonu yapan ve ödemeyi yapan kullanıcı
+	Status        ReservationStatus  `bun:"status,notnull" json:"status"`
+	StartTime     time.Time          `bun:"start_time,notnull" json:"start_time"`
+	EndTime       time.Time          `bun:"end_time,notnull" json:"end_time"`
+	TotalPrice    int64              `bun:"total_price,notnull" json:"total_price"`
+	Currency      string             `bun:"currency,notnull" json:"currency"`
+	HoldExpiresAt time.Time          `bun:"hold_expires_at,notnull" json:"hold_expires_at"`
+	PaymentID     string             `bun:"payment_id,nullzero" json:"payment_id,omitempty"`
+	RefundAmount  int64              `bun:"refund_amount,notnull,default:0" json:"refund_amount"`
+	RefundID      string             `bun:"refund_id,nullzero" json:"refund_id,omitempty"`
+	ConfirmedAt   *time.Time         `bun:"confirmed_at,nullzero" json:"confirmed_at"`
+	CancelledAt   *time.Time         `bun:"cancelled_at,nullzero" json:"cancelled_at"`
+	CreatedAt     time.Time          `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	Shares        []ReservationShare `bun:"rel:has-many,join:id=reservation_id" json:"shares"`
+	Field         *Field             `bun:"rel:has-one,join:field_id=id" json:"field"`
+}
+
+// RefundPending iptal edilmiş rezervasyonun iadesinin henüz ödeme sağlayıcısında yapılmadığını söyler
+func (r Reservation) RefundPending() bool {
+	return r.Status == ReservationStatusCancelled && r.RefundAmount > 0 && r.RefundID == ""
+}
+
+// ReservationShare bir oyuncunun rezervasyon ücretinden düşen payıdır
+type ReservationShare struct {
+	bun.BaseModel `bun:"table:reservation_shares,alias:rs"`
+	ID            int64                  `bun:"id,pk,autoincrement" json:"id"`
+	ReservationID int64                  `bun:"reservation_id,notnull" json:"reservation_id"`
+	UserID        int64                  `bun:"user_id,notnull" json:"user_id"`
+	Amount        int64                  `bun:"amount,notnull" json:"amount"`
+	Status        ReservationShareStatus `bun:"status,notnull" json:"status"`
+	RefundAmount  int64                  `bun:"refund_amount,notnull,default:0" json:"refund_amount"`
+	PaidAt        *time.Time             `bun:"paid_at,nullzero" json:"paid_at"`
+}
+
+// Pricing sahanın saatlik ücretini ve saat dilimini yoğun saat çarpanlarıyla birleştirir
+func (f Field) Pricing(peaks []booking.PeakWindow) booking.Pricing {
+	loc, err := time.LoadLocation(f.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return booking.Pricing{
+		PricePerHour: f.PricePerHour,
+		Location:     loc,
+		Peaks:        peaks,
+	}
+}
+
+type ReservationCreateVM struct {
+	FieldID    uint      `json:"field_id" validate:"required"`
+	StartTime  time.Time `json:"start_time" validate:"required"`
+	EndTime    time.Time `json:"end_time" validate:"required"`
+	MaxPlayers int64     `json:"max_players" validate:"omitempty"` // boş bırakılırsa sahanın kapasitesi
+}
+
+// Game rezervasyonun sahayı tutan oyununu kurar, oyun ödeme alınana kadar PENDING kalır
+func (vm ReservationCreateVM) Game(f Field, hostID int64) Game {
+	maxPlayers := vm.MaxPlayers
+	if maxPlayers <= 0 || maxPlayers > f.Capacity {
+		maxPlayers = f.Capacity
+	}
+	return Game{
+		FieldID:    vm.FieldID,
+		HostID:     uint(hostID),
+		StartTime:  vm.StartTime,
+		EndTime:    vm.EndTime,
+		MaxPlayers: maxPlayers,
+		Status:     GameStatusPending,
+	}
+}
+
+type ReservationQuoteVM struct {
+	FieldID      uint      `json:"field_id"`
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+	PricePerHour float64   `json:"price_per_hour"`
+	BasePrice    int64     `json:"base_price"`  // yoğun saat çarpanları olmadan
+	TotalPrice   int64     `json:"total_price"` // kuruş
+	Currency     string    `json:"currency"`
+}
+
+// ReservationSplitVM ücretin eşit bölüneceği oyunculardır, rezervasyonu yapan her zaman dahildir.
+// Boş bırakılırsa oyuna kayıtlı oyuncular kullanılır.
+type ReservationSplitVM struct {
+	UserIDs []int64 `json:"user_ids"`
+}
+
+type ReservationDetailVM struct {
+	ID            int64              `json:"id"`
+	GameID        int64              `json:"game_id"`
+	FieldID       uint               `json:"field_id"`
+	UserID        int64              `json:"user_id"`
+	Status        ReservationStatus  `json:"status"`
+	StartTime     time.Time          `json:"start_time"`
+	EndTime       time.Time          `json:"end_time"`
+	TotalPrice    int64              `json:"total_price"`
+	Currency      string             `json:"currency"`
+	HoldExpiresAt time.Time          `json:"hold_expires_at"`
+	PaymentID     string             `json:"payment_id,omitempty"`
+	RefundAmount  int64              `json:"refund_amount"`
+	ConfirmedAt   *time.Time         `json:"confirmed_at"`
+	CancelledAt   *time.Time         `json:"cancelled_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+	FieldName     string             `json:"field_name,omitempty"`
+	Shares        []ReservationShare `json:"shares"`
+}
+
+func (vm ReservationDetailVM) FromDBModel(m Reservation) ReservationDetailVM {
+	vm.ID = m.ID
+	vm.GameID = m.GameID
+	vm.FieldID = m.FieldID
+	vm.UserID = m.UserID
+	vm.Status = m.Status
+	vm.StartTime = m.StartTime
+	vm.EndTime = m.EndTime
+	vm.TotalPrice = m.TotalPrice
+	vm.Currency = m.Currency
+	vm.HoldExpiresAt = m.HoldExpiresAt
+	vm.PaymentID = m.PaymentID
+	vm.RefundAmount = m.RefundAmount
+	vm.ConfirmedAt = m.ConfirmedAt
+	vm.CancelledAt = m.CancelledAt
+	vm.CreatedAt = m.CreatedAt
+	if m.Field != nil {
+		vm.FieldName = m.Field.Name
+	}
+	vm.Shares = m.Shares
+	if vm.Shares == nil {
+		vm.Shares = []ReservationShare{}
+	}
+	return vm
+}
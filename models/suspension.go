@@ -0,0 +1,93 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/personal-project/pitch-league/discipline"
+	"github.com/uptrace/bun"
+)
+
+// SuspensionServingStatuses ceza çekmeye sayılan maç durumlarıdır, ertelenen ve yarıda kalan maçlar tekrar oynanacağı için sayılmaz
+var SuspensionServingStatuses = []MatchStatus{MatchStatusScheduled, MatchStatusLive, MatchStatusCompleted, MatchStatusForfeit, MatchStatusWalkover}
+
+// Suspension oyuncunun ligde, cezayı aldığı takımın StartsAfter'dan sonraki Matches maçında oynayamamasıdır.
+// Kartlardan gelen cezalar kartlar değiştikçe yeniden hesaplanır, MANUAL cezaları lig yönetimi verir.
+type Suspension struct {
+	bun.BaseModel `bun:"table:suspensions,alias:s"`
+	ID            int64             `bun:"id,pk,autoincrement" json:"id"`
+	LeagueID      uint              `bun:"league_id,notnull" json:"league_id"`
+	UserID        uint              `bun:"user_id,notnull" json:"user_id"`
+	TeamID        uint              `bun:"team_id,notnull" json:"team_id"`
+	MatchID       int64             `bun:"match_id,nullzero" json:"match_id,omitempty"` // kartın görüldüğü maç
+	Reason        discipline.Reason `bun:"reason,notnull" json:"reason"`
+	Matches       int64             `bun:"matches,notnull" json:"matches"`
+	StartsAfter   time.Time         `bun:"starts_after,notnull" json:"starts_after"`
+	Note          string            `bun:"note,notnull,default:''" json:"note"`
+	CreatedBy     int64             `bun:"created_by,nullzero" json:"created_by,omitempty"`
+	CreatedAt     time.Time         `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	LiftedAt      *time.Time        `bun:"lifted_at,nullzero" json:"lifted_at"`
+	LiftedBy      int64             `bun:"lifted_by,nullzero" json:"lifted_by,omitempty"`
+	User          *User             `bun:"rel:has-one,join:user_id=id" json:"user"`
+	Team          *Team             `bun:"rel:has-one,join:team_id=id" json:"team"`
+}
+
+func (s Suspension) Ban() discipline.Ban {
+	return discipline.Ban{
+		ID:          s.ID,
+		UserID:      s.UserID,
+		TeamID:      s.TeamID,
+		MatchID:     s.MatchID,
+		StartsAfter: s.StartsAfter,
+		Matches:     s.Matches,
+		Reason:      s.Reason,
+	}
+}
+
+// SuspensionCreateVM lig yönetiminin elle verdiği cezadır, starts_after verilmezse ceza hemen başlar
+type SuspensionCreateVM struct {
+	UserID      uint       `json:"user_id" validate:"required"`
+	TeamID      uint       `json:"team_id" validate:"required"`
+	Matches     int64      `json:"matches" validate:"required"`
+	StartsAfter *time.Time `json:"starts_after"`
+	Note        string     `json:"note"`
+}
+
+func (vm SuspensionCreateVM) Validate() error {
+	if vm.UserID == 0 || vm.TeamID == 0 {
+		return errors.New("user_id ve team_id gerekli")
+	}
+	if vm.Matches < 1 {
+		return errors.New("ceza en az bir maç olmalı")
+	}
+	return nil
+}
+
+func (vm SuspensionCreateVM) ToDBModel(m Suspension) Suspension {
+	m.UserID = vm.UserID
+	m.TeamID = vm.TeamID
+	m.Reason = discipline.ReasonManual
+	m.Matches = vm.Matches
+	m.StartsAfter = time.Now()
+	if vm.StartsAfter != nil {
+		m.StartsAfter = *vm.StartsAfter
+	}
+	m.Note = vm.Note
+	return m
+}
+
+type SuspensionDetailVM struct {
+	Suspension
+	// MatchIDs cezanın kapsadığı maçlardır, Remaining henüz oynanmamış veya fikstürde olmayan ceza maçı sayısıdır
+	MatchIDs  []int64 `json:"match_ids"`
+	Remaining int64   `json:"remaining"`
+	Active    bool    `json:"active"`
+}
+
+func (vm SuspensionDetailVM) FromDBModel(m Suspension, serving discipline.Serving) SuspensionDetailVM {
+	vm.Suspension = m
+	vm.MatchIDs = serving.MatchIDs
+	vm.Remaining = serving.Remaining
+	vm.Active = m.LiftedAt == nil && serving.Active()
+	return vm
+}
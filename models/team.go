@@ -8,9 +8,10 @@ type Team struct {
 	bun.BaseModel `bun:"table:teams,alias:t"`
 	ID            int64  `bun:"id,pk,autoincrement" json:"id"`
 	Name          string `bun:"name,notnull" json:"name"`
-	Capacity      int64  `bun:"capacity,notnull" json:"capacity"`
+	Capacity      int64  `bun:"capacity,notnull" json:"capacity"` // takımın alabileceği en fazla aktif üye sayısı
 	CaptainID     int64  `bun:"captain_id,notnull" json:"captain_id"`
 	Captain       *User  `bun:"rel:has-one,join:captain_id=id" json:"captain"`
+	MemberCount   int64  `bun:"member_count,scanonly" json:"member_count"`
 }
 
 type TeamCreateVM struct {
@@ -32,6 +33,9 @@ type TeamDetailVM struct {
 	Capacity  int64       `json:"capacity"`
 	CaptainID int64       `json:"captain_id"`
 	Captain   interface{} `json:"captain"`
+	// MemberCount ve FreeSlots aktif üyelerden hesaplanır
+	MemberCount int64 `json:"member_count"`
+	FreeSlots   int64 `json:"free_slots"`
 }
 
 func (vm TeamDetailVM) FromDBModel(m Team) TeamDetailVM {
@@ -40,6 +44,8 @@ func (vm TeamDetailVM) FromDBModel(m Team) TeamDetailVM {
 	vm.Capacity = m.Capacity
 	vm.CaptainID = m.CaptainID
 	vm.Captain = m.Captain
+	vm.MemberCount = m.MemberCount
+	vm.FreeSlots = m.FreeSlots()
 	return vm
 }
 
@@ -47,6 +53,13 @@ func (Team) ModelName() string {
 	return "teams"
 }
 
+func (t Team) FreeSlots() int64 {
+	if t.MemberCount >= t.Capacity {
+		return 0
+	}
+	return t.Capacity - t.MemberCount
+}
+
 func (t Team) String() string {
 	if t.Captain != nil {
 		return "Takım: " + t.Name + " Kaptan: " + t.Captain.Name
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type TeamInvitationKind string
+
+const (
+	TeamInvitationKindInvite  TeamInvitationKind = "INVITE"  // kaptan oyuncuyu davet eder
+	TeamInvitationKindRequest TeamInvitationKind = "REQUEST" // oyuncu takıma katılmak ister
+)
+
+type TeamInvitationStatus string
+
+const (
+	TeamInvitationStatusPending   TeamInvitationStatus = "PENDING"
+	TeamInvitationStatusAccepted  TeamInvitationStatus = "ACCEPTED"
+	TeamInvitationStatusDeclined  TeamInvitationStatus = "DECLINED"
+	TeamInvitationStatusCancelled TeamInvitationStatus = "CANCELLED"
+	TeamInvitationStatusExpired   TeamInvitationStatus = "EXPIRED"
+)
+
+type TeamInvitation struct {
+	bun.BaseModel `bun:"table:team_invitations,alias:ti"`
+	ID            int64                `bun:"id,pk,autoincrement" json:"id"`
+	TeamID        int64                `bun:"team_id,notnull" json:"team_id"`
+	UserID        int64                `bun:"user_id,notnull" json:"user_id"` // davet edilen veya katılmak isteyen oyuncu
+	Kind          TeamInvitationKind   `bun:"kind,notnull" json:"kind"`
+	Status        TeamInvitationStatus `bun:"status,notnull" json:"status"`
+	CreatedBy     int64                `bun:"created_by,notnull" json:"created_by"`
+	RespondedBy   *int64               `bun:"responded_by,nullzero" json:"responded_by"`
+	ExpiresAt     time.Time            `bun:"expires_at,notnull" json:"expires_at"`
+	RespondedAt   *time.Time           `bun:"responded_at,nullzero" json:"responded_at"`
+	CreatedAt     time.Time            `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	Team          *Team                `bun:"rel:has-one,join:team_id=id" json:"team"`
+	User          *User                `bun:"rel:has-one,join:user_id=id" json:"user"`
+}
+
+// TeamInviteVM kaptanın kullanıcı adı veya e-posta ile oyuncu davet etmesi için
+type TeamInviteVM struct {
+	UserName string `json:"username" validate:"required_without=Email,omitempty,max=20"`
+	Email    string `json:"email" validate:"required_without=UserName,omitempty,max=64,email"`
+}
+
+type TeamInvitationDetailVM struct {
+	ID          int64                `json:"id"`
+	TeamID      int64                `json:"team_id"`
+	UserID      int64                `json:"user_id"`
+	Kind        TeamInvitationKind   `json:"kind"`
+	Status      TeamInvitationStatus `json:"status"`
+	CreatedBy   int64                `json:"created_by"`
+	RespondedBy *int64               `json:"responded_by"`
+	ExpiresAt   time.Time            `json:"expires_at"`
+	RespondedAt *time.Time           `json:"responded_at"`
+	CreatedAt   time.Time            `json:"created_at"`
+	TeamName    string               `json:"team_name,omitempty"`
+	User        interface{}          `json:"user,omitempty"`
+}
+
+func (vm TeamInvitationDetailVM) FromDBModel(m TeamInvitation) TeamInvitationDetailVM {
+	vm.ID = m.ID
+	vm.TeamID = m.TeamID
+	vm.UserID = m.UserID
+	vm.Kind = m.Kind
+	vm.Status = m.Status
+	vm.CreatedBy = m.CreatedBy
+	vm.RespondedBy = m.RespondedBy
+	vm.ExpiresAt = m.ExpiresAt
+	vm.RespondedAt = m.RespondedAt
+	vm.CreatedAt = m.CreatedAt
+	if m.Team != nil {
+		vm.TeamName = m.Team.Name
+	}
+	if m.User != nil {
+		vm.User = ToUserResponse(*m.User)
+	}
+	return vm
+}
+
+func (TeamInvitation) ModelName() string {
+	return "team_invitations"
+}
+
+func (m TeamInvitation) IsExpired() bool {
+	return time.Now().After(m.ExpiresAt)
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type TeamMemberRole string
+
+const (
+	TeamMemberRoleCaptain     TeamMemberRole = "CAPTAIN"
+	TeamMemberRoleViceCaptain TeamMemberRole = "VICE_CAPTAIN"
+	TeamMemberRolePlayer      TeamMemberRole = "PLAYER"
+)
+
+type TeamMemberStatus string
+
+const (
+	TeamMemberStatusActive TeamMemberStatus = "ACTIVE"
+	TeamMemberStatusLeft   TeamMemberStatus = "LEFT"
+	TeamMemberStatusKicked TeamMemberStatus = "KICKED"
+)
+
+type TeamMember struct {
+	bun.BaseModel `bun:"table:team_members,alias:tm"`
+	ID            int64            `bun:"id,pk,autoincrement" json:"id"`
+	TeamID        int64            `bun:"team_id,notnull" json:"team_id"`
+	UserID        int64            `bun:"user_id,notnull" json:"user_id"`
+	Role          TeamMemberRole   `bun:"role,notnull" json:"role"`
+	JerseyNumber  *int64           `bun:"jersey_number,nullzero" json:"jersey_number"`
+	Status        TeamMemberStatus `bun:"status,notnull" json:"status"`
+	JoinedAt      time.Time        `bun:"joined_at,nullzero,notnull,default:current_timestamp" json:"joined_at"`
+	LeftAt        *time.Time       `bun:"left_at,nullzero" json:"left_at"`
+	User          *User            `bun:"rel:has-one,join:user_id=id" json:"user"`
+	Team          *Team            `bun:"rel:has-one,join:team_id=id" json:"team"`
+}
+
+// TeamMemberUpdateVM kaptanın bir oyuncunun rolünü ve forma numarasını değiştirmesi için
+type TeamMemberUpdateVM struct {
+	Role         TeamMemberRole `json:"role" validate:"required,oneof=VICE_CAPTAIN PLAYER"`
+	JerseyNumber *int64         `json:"jersey_number" validate:"omitempty,min=1,max=99"`
+}
+
+func (vm TeamMemberUpdateVM) ToDBModel(m TeamMember) TeamMember {
+	m.Role = vm.Role
+	m.JerseyNumber = vm.JerseyNumber
+	return m
+}
+
+type TeamCaptainTransferVM struct {
+	UserID int64 `json:"user_id" validate:"required"`
+}
+
+type TeamMemberDetailVM struct {
+	ID           int64            `json:"id"`
+	TeamID       int64            `json:"team_id"`
+	UserID       int64            `json:"user_id"`
+	Role         TeamMemberRole   `json:"role"`
+	JerseyNumber *int64           `json:"jersey_number"`
+	Status       TeamMemberStatus `json:"status"`
+	JoinedAt     time.Time        `json:"joined_at"`
+	LeftAt       *time.Time       `json:"left_at"`
+	User         interface{}      `json:"user"`
+}
+
+func (vm TeamMemberDetailVM) FromDBModel(m TeamMember) TeamMemberDetailVM {
+	vm.ID = m.ID
+	vm.TeamID = m.TeamID
+	vm.UserID = m.UserID
+	vm.Role = m.Role
+	vm.JerseyNumber = m.JerseyNumber
+	vm.Status = m.Status
+	vm.JoinedAt = m.JoinedAt
+	vm.LeftAt = m.LeftAt
+	if m.User != nil {
+		vm.User = ToUserResponse(*m.User)
+	}
+	return vm
+}
+
+// UserTeamVM kullanıcının aktif üyesi olduğu takım ve takımdaki rolüdür
+type UserTeamVM struct {
+	Team         TeamDetailVM   `json:"team"`
+	Role         TeamMemberRole `json:"role"`
+	JerseyNumber *int64         `json:"jersey_number"`
+	JoinedAt     time.Time      `json:"joined_at"`
+}
+
+func (vm UserTeamVM) FromDBModel(m TeamMember) UserTeamVM {
+	if m.Team != nil {
+		vm.Team = TeamDetailVM{}.FromDBModel(*m.Team)
+	}
+	vm.Role = m.Role
+	vm.JerseyNumber = m.JerseyNumber
+	vm.JoinedAt = m.JoinedAt
+	return vm
+}
+
+func (TeamMember) ModelName() string {
+	return "team_members"
+}
+
+func (r TeamMemberRole) IsValid() bool {
+	switch r {
+	case TeamMemberRoleCaptain, TeamMemberRoleViceCaptain, TeamMemberRolePlayer:
+		return true
+	}
+	return false
+}
@@ -1,12 +1,22 @@
 package models
 
-import "github.com/personal-project/pitch-league/utils"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/personal-project/pitch-league/utils"
+)
 
 type UserRole int
 
 const (
-	UserRoleNormal UserRole = 1
-	UserRoleAdmin  UserRole = 10
+	UserRoleNormal          UserRole = 1 // oyuncu
+	UserRoleCaptain         UserRole = 2
+	UserRoleFieldOwner      UserRole = 3
+	UserRoleLeagueOrganizer UserRole = 4
+	UserRoleAdmin           UserRole = 10
 )
 
 type User struct {
@@ -18,8 +28,16 @@ type User struct {
 	UserName string   `json:"username" bun:"username"`
 	Password string   `json:"-" bun:"password"`
 	Role     UserRole `json:"role" bun:"role"`
+	// Kayıt olan kullanıcılar e-posta/telefon doğrulanana kadar nil kalır
+	VerifiedAt *time.Time `json:"verified_at" bun:"verified_at,nullzero"`
 }
 
+// Parola uzunluk sınırları kayıt ve parola değişikliğinde aynıdır
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 100
+)
+
 // Create için kullanılacak model
 type UserCreate struct {
 	Email    string `json:"email" validate:"required_without=Phone,omitempty,max=64,email"`
@@ -27,7 +45,20 @@ type UserCreate struct {
 	Name     string `json:"name" validate:"required,max=100"`
 	Surname  string `json:"surname" validate:"required,max=100"`
 	UserName string `json:"username" validate:"required,max=20"`
-	Password string `json:"password" validate:"required,min=3,max=100"`
+	Password string `json:"password" validate:"required,min=8,max=100"`
+}
+
+func (u UserCreate) Validate() error {
+	if strings.TrimSpace(u.Email) == "" && strings.TrimSpace(u.Phone) == "" {
+		return errors.New("e-posta veya telefon zorunludur")
+	}
+	if strings.TrimSpace(u.Name) == "" || strings.TrimSpace(u.Surname) == "" || strings.TrimSpace(u.UserName) == "" {
+		return errors.New("ad, soyad ve kullanıcı adı zorunludur")
+	}
+	if len(u.Name) > 100 || len(u.Surname) > 100 || len(u.UserName) > 20 {
+		return errors.New("ad ve soyad en fazla 100, kullanıcı adı en fazla 20 karakter olabilir")
+	}
+	return validatePassword(u.Password)
 }
 
 // ToModel creates a User from UserCreate
@@ -71,6 +102,52 @@ func (u UserUpdate) ToModel(existing User) User {
 	return existing
 }
 
+// Kullanıcının kendi bilgilerini güncellemesi için kullanılacak model. E-posta ve telefon doğrulama
+// gerektirdiği için buradan değiştirilemez.
+type UserProfileUpdate struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Surname  string `json:"surname" validate:"required,max=100"`
+	UserName string `json:"username" validate:"required,max=20"`
+}
+
+func (u UserProfileUpdate) Validate() error {
+	if strings.TrimSpace(u.Name) == "" || strings.TrimSpace(u.Surname) == "" || strings.TrimSpace(u.UserName) == "" {
+		return errors.New("name, surname ve username gerekli")
+	}
+	if len(u.Name) > 100 || len(u.Surname) > 100 || len(u.UserName) > 20 {
+		return errors.New("name ve surname en fazla 100, username en fazla 20 karakter olabilir")
+	}
+	return nil
+}
+
+// ToModel updates an existing User from UserProfileUpdate
+func (u UserProfileUpdate) ToModel(existing User) User {
+	existing.Name = utils.ToTitle(u.Name)
+	existing.Surname = utils.ToTitle(u.Surname)
+	existing.UserName = strings.TrimSpace(u.UserName)
+	return existing
+}
+
+// Kullanıcının parolasını değiştirmesi için kullanılacak model, mevcut parola doğrulanır
+type UserPasswordUpdate struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=100"`
+}
+
+func (u UserPasswordUpdate) Validate() error {
+	if u.CurrentPassword == "" {
+		return errors.New("current_password gerekli")
+	}
+	return validatePassword(u.NewPassword)
+}
+
+func validatePassword(password string) error {
+	if n := len(strings.TrimSpace(password)); n < MinPasswordLength || n > MaxPasswordLength {
+		return fmt.Errorf("parola %d ile %d karakter arasında olmalı", MinPasswordLength, MaxPasswordLength)
+	}
+	return nil
+}
+
 // Response için kullanılacak model
 type UserResponse struct {
 	ID       int64    `json:"id"`
@@ -80,6 +157,7 @@ type UserResponse struct {
 	Surname  string   `json:"surname"`
 	UserName string   `json:"username"`
 	Role     UserRole `json:"role"`
+	Verified bool     `json:"verified"`
 }
 
 func ToUserResponse(u User) UserResponse {
@@ -91,6 +169,7 @@ func ToUserResponse(u User) UserResponse {
 		Surname:  u.Surname,
 		UserName: u.UserName,
 		Role:     u.Role,
+		Verified: u.IsVerified(),
 	}
 }
 
@@ -98,6 +177,18 @@ func (User) ModelName() string {
 	return "user"
 }
 
+func (u User) IsVerified() bool {
+	return u.VerifiedAt != nil
+}
+
+// VerificationTarget doğrulama kodunun gönderileceği kanalı ve adresi döner, e-posta önceliklidir
+func (u User) VerificationTarget() (VerificationChannel, string) {
+	if u.Email != "" {
+		return VerificationChannelEmail, u.Email
+	}
+	return VerificationChannelPhone, u.Phone
+}
+
 func (u User) String() string {
 	return u.Name + " " + u.Surname
 }
@@ -105,7 +196,13 @@ func (u User) String() string {
 func (r UserRole) String() string {
 	switch r {
 	case UserRoleNormal:
-		return "normal"
+		return "player"
+	case UserRoleCaptain:
+		return "captain"
+	case UserRoleFieldOwner:
+		return "field_owner"
+	case UserRoleLeagueOrganizer:
+		return "league_organizer"
 	case UserRoleAdmin:
 		return "admin"
 	default:
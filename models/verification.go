@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type VerificationChannel string
+
+const (
+	VerificationChannelEmail VerificationChannel = "EMAIL"
+	VerificationChannelPhone VerificationChannel = "PHONE"
+)
+
+type VerificationCode struct {
+	bun.BaseModel `bun:"table:verification_codes,alias:vc"`
+	ID            int64               `bun:"id,pk,autoincrement" json:"id"`
+	UserID        int64               `bun:"user_id,notnull" json:"user_id"`
+	Channel       VerificationChannel `bun:"channel,notnull" json:"channel"`
+	Target        string              `bun:"target,notnull" json:"target"`
+	CodeHash      string              `bun:"code_hash,notnull" json:"-"`
+	Attempts      int64               `bun:"attempts,notnull,default:0" json:"attempts"`
+	ExpiresAt     time.Time           `bun:"expires_at,notnull" json:"expires_at"`
+	ConsumedAt    *time.Time          `bun:"consumed_at,nullzero" json:"consumed_at"`
+	CreatedAt     time.Time           `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+}
+
+func (VerificationCode) ModelName() string {
+	return "verification_codes"
+}
+
+func (v VerificationCode) IsExpired() bool {
+	return v.ExpiresAt.Before(time.Now())
+}
+
+func (c VerificationChannel) String() string {
+	switch c {
+	case VerificationChannelEmail:
+		return "email"
+	case VerificationChannelPhone:
+		return "phone"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,33 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileNotifier bildirimleri verilen dosyanın sonuna ekler, lokal testlerde kodları okumak için kullanılır
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) Notifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) Send(ctx context.Context, msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("bildirim dosyası açılamadı: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\t%s\t%s\n", time.Now().Format(time.RFC3339), msg.Channel, msg.To, msg.Subject, msg.Body)
+	return err
+}
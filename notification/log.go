@@ -0,0 +1,18 @@
+package notification
+
+import (
+	"context"
+	"log"
+)
+
+// LogNotifier bildirimleri sadece loga yazar, lokal geliştirme için kullanılır
+type LogNotifier struct{}
+
+func NewLogNotifier() Notifier {
+	return LogNotifier{}
+}
+
+func (LogNotifier) Send(ctx context.Context, msg Message) error {
+	log.Printf("[notification] channel=%s to=%s subject=%q body=%q", msg.Channel, msg.To, msg.Subject, msg.Body)
+	return nil
+}
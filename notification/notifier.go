@@ -0,0 +1,23 @@
+package notification
+
+import (
+	"context"
+)
+
+const (
+	ChannelEmail = "EMAIL"
+	ChannelPhone = "PHONE"
+)
+
+// Message kullanıcıya gönderilecek bildirimi tanımlar
+type Message struct {
+	Channel string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier bildirimlerin hangi yolla gönderileceğini soyutlar (e-posta, sms, log...)
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
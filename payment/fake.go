@@ -0,0 +1,72 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// FakeProvider ödemeleri bellekte tutar, lokal geliştirme ve testler içindir. Aynı Reference ile gelen
+// ikinci çekim veya iade ilkinin id'sini döner. Uygulama yeniden başlayınca eski çekimler unutulur, bunların iadesi
+// kontrol edilmeden kabul edilir.
+type FakeProvider struct {
+	mu       sync.Mutex
+	charges  map[string]int64
+	refunded map[string]int64
+	byRef    map[string]string
+}
+
+func NewFakeProvider() Provider {
+	return &FakeProvider{
+		charges:  make(map[string]int64),
+		refunded: make(map[string]int64),
+		byRef:    make(map[string]string),
+	}
+}
+
+func (p *FakeProvider) Charge(ctx context.Context, req ChargeRequest) (string, error) {
+	if req.Amount < 0 {
+		return "", ErrPaymentDeclined
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if id, ok := p.byRef[req.Reference]; ok && req.Reference != "" {
+		return id, nil
+	}
+
+	id := "fake_ch_" + uuid.NewString()
+	p.charges[id] = req.Amount
+	if req.Reference != "" {
+		p.byRef[req.Reference] = id
+	}
+	log.Printf("[payment] charge id=%s user=%d amount=%d %s", id, req.UserID, req.Amount, req.Currency)
+	return id, nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, req RefundRequest) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if req.Amount < 0 {
+		return "", errors.New("iade tutarı negatif olamaz")
+	}
+	if id, ok := p.byRef[req.Reference]; ok && req.Reference != "" {
+		return id, nil
+	}
+	if charged, ok := p.charges[req.ChargeID]; ok && p.refunded[req.ChargeID]+req.Amount > charged {
+		return "", errors.New("iade tutarı ödenen tutarı aşamaz")
+	}
+
+	p.refunded[req.ChargeID] += req.Amount
+	id := "fake_re_" + uuid.NewString()
+	if req.Reference != "" {
+		p.byRef[req.Reference] = id
+	}
+	log.Printf("[payment] refund id=%s charge=%s amount=%d", id, req.ChargeID, req.Amount)
+	return id, nil
+}
@@ -0,0 +1,34 @@
+package payment
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFakeProviderReferences(t *testing.T) {
+	ctx := context.Background()
+	p := NewFakeProvider()
+
+	charge := ChargeRequest{Reference: "reservation-1", UserID: 7, Amount: 1000, Currency: CurrencyTRY}
+	first, err := p.Charge(ctx, charge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := p.Charge(ctx, charge); again != first {
+		t.Errorf("second charge with the same reference got %s, want %s", again, first)
+	}
+
+	refund := RefundRequest{Reference: "reservation-1-refund", ChargeID: first, Amount: 600}
+	refundID, err := p.Refund(ctx, refund)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Aynı referansla tekrarlanan iade ikinci kez düşülmez, aksi halde toplam 1200 olup reddedilirdi
+	if again, err := p.Refund(ctx, refund); err != nil || again != refundID {
+		t.Errorf("second refund with the same reference got %s, %v; want %s", again, err, refundID)
+	}
+
+	if _, err := p.Refund(ctx, RefundRequest{Reference: "reservation-1-void", ChargeID: first, Amount: 500}); err == nil {
+		t.Error("refunds over the charged amount are accepted")
+	}
+}
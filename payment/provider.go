@@ -0,0 +1,33 @@
+package payment
+
+import (
+	"context"
+	"errors"
+)
+
+const CurrencyTRY = "TRY"
+
+var ErrPaymentDeclined = errors.New("ödeme reddedildi")
+
+// ChargeRequest bir kullanıcıdan çekilecek tutarı tanımlar, tutar kuruş cinsindendir
+type ChargeRequest struct {
+	// Reference aynı isteğin iki kez çekilmesini engellemek için sağlayıcıya gönderilen anahtardır
+	Reference string
+	UserID    int64
+	Amount    int64
+	Currency  string
+}
+
+// RefundRequest bir çekimin iade edilecek kısmını tanımlar, tutar kuruş cinsindendir
+type RefundRequest struct {
+	// Reference aynı iadenin iki kez yapılmasını engellemek için sağlayıcıya gönderilen anahtardır
+	Reference string
+	ChargeID  string
+	Amount    int64
+}
+
+// Provider ödeme altyapısını soyutlar (iyzico, stripe, sahte sağlayıcı...)
+type Provider interface {
+	Charge(ctx context.Context, req ChargeRequest) (string, error)
+	Refund(ctx context.Context, req RefundRequest) (string, error)
+}
@@ -11,13 +11,25 @@ import (
 	"github.com/uptrace/bun"
 )
 
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenExpired  = errors.New("refresh token expired")
+	ErrRefreshTokenRevoked  = errors.New("refresh token revoked")
+	ErrRefreshTokenReused   = errors.New("refresh token reuse detected, all sessions of this token family are revoked")
+	ErrSessionNotFound      = errors.New("session not found")
+)
+
 type IAuthRepository interface {
 	GetAuthRefreshToken(ctx context.Context, refreshTokenID uuid.UUID) (models.AuthRefreshToken, error)
 	CreateAuthRefreshToken(ctx context.Context, token models.AuthRefreshToken) error
-	UpdateAuthRefreshTokenExpires(ctx context.Context, tokenID uuid.UUID, expiresAt time.Time) error
+	RotateAuthRefreshToken(ctx context.Context, oldTokenID uuid.UUID, next models.AuthRefreshToken) error
+	RevokeTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	ListActiveSessions(ctx context.Context, userID int64) ([]models.AuthRefreshToken, error)
+	RevokeSession(ctx context.Context, userID int64, sessionID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, userID int64, currentSessionID uuid.UUID) error
 	DeleteAuthRefreshToken(ctx context.Context, userID int64) error
-	GenerateTokenPair(userID int64, refreshTokenID uuid.UUID, role float64) (models.AuthTokenPair, error)
-	ParseRefreshToken(refreshToken string) (refreshTokenID uuid.UUID, userID int64, role float64, err error)
+	GenerateTokenPair(userID int64, refreshTokenID, sessionID uuid.UUID, role models.UserRole) (models.AuthTokenPair, error)
+	ParseRefreshToken(refreshToken string) (refreshTokenID uuid.UUID, userID int64, role models.UserRole, err error)
 }
 
 type AuthRepository struct {
@@ -49,11 +61,11 @@ func (r AuthRepository) GetAuthRefreshToken(ctx context.Context, refreshTokenID
 		Scan(ctx)
 
 	if err != nil {
-		return token, errors.New("refresh token not found")
+		return token, ErrRefreshTokenNotFound
 	}
 
 	if token.ExpiresAt.Before(time.Now()) {
-		return token, errors.New("refresh token expired")
+		return token, ErrRefreshTokenExpired
 	}
 
 	return token, nil
@@ -70,15 +82,111 @@ func (r AuthRepository) CreateAuthRefreshToken(ctx context.Context, token models
 	return nil
 }
 
-func (r AuthRepository) UpdateAuthRefreshTokenExpires(ctx context.Context, tokenID uuid.UUID, expiresAt time.Time) error {
+// CheckRefreshable tokenın yeni bir token çifti almak için kullanılabileceğini doğrular. Ailesi iptal edilmiş token
+// ErrRefreshTokenRevoked, daha önce kullanılmış token ErrRefreshTokenReused döner; ikincisinde aileyi iptal etmek çağırana kalır.
+func CheckRefreshable(token models.AuthRefreshToken) error {
+	if token.IsRevoked() {
+		return ErrRefreshTokenRevoked
+	}
+	if token.IsConsumed() {
+		return ErrRefreshTokenReused
+	}
+	return nil
+}
+
+// RotateAuthRefreshToken eski tokenı kullanıldı olarak işaretler ve aynı aileye yeni tokenı ekler.
+// Eski token daha önce kullanılmışsa ErrRefreshTokenReused döner, aileyi iptal etmek çağırana kalır.
+func (r AuthRepository) RotateAuthRefreshToken(ctx context.Context, oldTokenID uuid.UUID, next models.AuthRefreshToken) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewUpdate().
+			Model((*models.AuthRefreshToken)(nil)).
+			Set("consumed_at = ?", time.Now()).
+			Where("token_id = ?", oldTokenID).
+			Where("consumed_at IS NULL").
+			Where("revoked_at IS NULL").
+			Exec(ctx)
+		if err != nil {
+			return errors.New("failed to consume auth refresh token: " + err.Error())
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+
+		_, err = tx.NewInsert().
+			Model(&next).
+			Exec(ctx)
+		if err != nil {
+			return errors.New("failed to create auth refresh token: " + err.Error())
+		}
+		return nil
+	})
+}
+
+func (r AuthRepository) RevokeTokenFamily(ctx context.Context, familyID uuid.UUID) error {
 	_, err := r.db.NewUpdate().
 		Model((*models.AuthRefreshToken)(nil)).
-		Set("expires_at = ?", expiresAt).
-		Where("token_id = ?", tokenID).
+		Set("revoked_at = ?", time.Now()).
+		Where("family_id = ?", familyID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+
+	if err != nil {
+		return errors.New("failed to revoke auth refresh token family: " + err.Error())
+	}
+	return nil
+}
+
+// ListActiveSessions kullanıcının her oturumu için henüz kullanılmamış, iptal edilmemiş son tokenı döner
+func (r AuthRepository) ListActiveSessions(ctx context.Context, userID int64) ([]models.AuthRefreshToken, error) {
+	var tokens []models.AuthRefreshToken
+	err := r.db.NewSelect().
+		Model(&tokens).
+		Where("user_id = ?", userID).
+		Where("consumed_at IS NULL").
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Order("last_used_at DESC").
+		Scan(ctx)
+	return tokens, err
+}
+
+func (r AuthRepository) RevokeSession(ctx context.Context, userID int64, sessionID uuid.UUID) error {
+	result, err := r.db.NewUpdate().
+		Model((*models.AuthRefreshToken)(nil)).
+		Set("revoked_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("family_id = ?", sessionID).
+		Where("revoked_at IS NULL").
 		Exec(ctx)
+	if err != nil {
+		return errors.New("failed to revoke session: " + err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
 
+func (r AuthRepository) RevokeOtherSessions(ctx context.Context, userID int64, currentSessionID uuid.UUID) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.AuthRefreshToken)(nil)).
+		Set("revoked_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("family_id != ?", currentSessionID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
 	if err != nil {
-		return errors.New("failed to update auth refresh token expiration: " + err.Error())
+		return errors.New("failed to revoke sessions: " + err.Error())
 	}
 	return nil
 }
@@ -95,16 +203,18 @@ func (r AuthRepository) DeleteAuthRefreshToken(ctx context.Context, userID int64
 	return nil
 }
 
-func (r AuthRepository) GenerateTokenPair(userID int64, refreshTokenID uuid.UUID, role float64) (models.AuthTokenPair, error) {
+func (r AuthRepository) GenerateTokenPair(userID int64, refreshTokenID, sessionID uuid.UUID, role models.UserRole) (models.AuthTokenPair, error) {
 	var m models.AuthTokenPair
 	now := time.Now()
 
 	accessClaims := models.AccessTokenClaims{
-		ID:     uuid.New(),
-		UserID: userID,
-		Role:   role,
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		UserID:    userID,
+		Role:      role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(r.accessTokenExpireTime)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
@@ -128,11 +238,13 @@ func (r AuthRepository) GenerateTokenPair(userID int64, refreshTokenID uuid.UUID
 	}
 
 	m.AccessToken = accessToken
+	m.AccessTokenID = accessClaims.ID
+	m.AccessTokenExpiresAt = accessClaims.ExpiresAt.Time
 	m.RefreshToken = refreshToken
 	return m, nil
 }
 
-func (r AuthRepository) ParseRefreshToken(refreshToken string) (refreshTokenID uuid.UUID, userID int64, role float64, err error) {
+func (r AuthRepository) ParseRefreshToken(refreshToken string) (refreshTokenID uuid.UUID, userID int64, role models.UserRole, err error) {
 	refreshClaims := models.RefreshTokenClaims{}
 	claims, err := jwt.ParseWithClaims(refreshToken, &refreshClaims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(r.jwtSecret), nil
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/personal-project/pitch-league/models"
+)
+
+func TestCheckRefreshable(t *testing.T) {
+	usedAt := time.Now().Add(-time.Minute)
+	family := uuid.New()
+
+	tests := []struct {
+		name  string
+		token models.AuthRefreshToken
+		want  error
+	}{
+		{
+			name:  "kullanılmamış token",
+			token: models.AuthRefreshToken{TokenID: uuid.New(), FamilyID: family},
+		},
+		{
+			name:  "rotasyonda kullanılmış token tekrar geldi",
+			token: models.AuthRefreshToken{TokenID: uuid.New(), FamilyID: family, ConsumedAt: &usedAt},
+			want:  ErrRefreshTokenReused,
+		},
+		{
+			name:  "ailesi iptal edilmiş token",
+			token: models.AuthRefreshToken{TokenID: uuid.New(), FamilyID: family, RevokedAt: &usedAt},
+			want:  ErrRefreshTokenRevoked,
+		},
+		{
+			// Tekrar kullanımda aile zaten iptal edildiği için aile bir daha iptal edilmez
+			name:  "kullanılmış ve ailesi iptal edilmiş token",
+			token: models.AuthRefreshToken{TokenID: uuid.New(), FamilyID: family, ConsumedAt: &usedAt, RevokedAt: &usedAt},
+			want:  ErrRefreshTokenRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckRefreshable(tt.token); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRefreshTokenRoundTrip(t *testing.T) {
+	r := AuthRepository{jwtSecret: "test-secret", accessTokenExpireTime: time.Minute, refreshTokenExpireTime: time.Hour}
+	tokenID, family := uuid.New(), uuid.New()
+
+	pair, err := r.GenerateTokenPair(7, tokenID, family, models.UserRoleNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotID, gotUser, _, err := r.ParseRefreshToken(pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotID != tokenID || gotUser != 7 {
+		t.Errorf("got token %s user %d, want token %s user 7", gotID, gotUser, tokenID)
+	}
+
+	other := AuthRepository{jwtSecret: "another-secret", refreshTokenExpireTime: time.Hour}
+	if _, _, _, err := other.ParseRefreshToken(pair.RefreshToken); err == nil {
+		t.Error("token signed with another secret was accepted")
+	}
+}
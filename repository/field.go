@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/personal-project/pitch-league/models"
 	"github.com/uptrace/bun"
@@ -15,6 +16,12 @@ type IFieldRepository interface {
 	DeleteByFieldID(ctx context.Context, id int64) error
 	UpdateField(ctx context.Context, m models.Field) error
 	CreateField(ctx context.Context, field models.Field) error
+	GetFieldOpeningHours(ctx context.Context, fieldID uint) ([]models.FieldOpeningHours, error)
+	UpdateFieldSchedule(ctx context.Context, field models.Field, hours []models.FieldOpeningHours) error
+	GetFieldBlackouts(ctx context.Context, fieldID uint, from, to time.Time) ([]models.FieldBlackout, error)
+	CreateFieldBlackout(ctx context.Context, blackout *models.FieldBlackout) error
+	DeleteFieldBlackout(ctx context.Context, fieldID uint, id int64) error
+	GetFieldGames(ctx context.Context, fieldID uint, from, to time.Time) ([]models.Game, error)
 }
 
 type FieldRepository struct {
@@ -87,3 +94,121 @@ func (r FieldRepository) CreateField(ctx context.Context, field models.Field) er
 		Exec(ctx)
 	return err
 }
+
+// GetFieldOpeningHours sahanın haftalık açılış saatlerini gün ve saat sırasıyla getirir
+func (r FieldRepository) GetFieldOpeningHours(ctx context.Context, fieldID uint) ([]models.FieldOpeningHours, error) {
+	return fieldOpeningHours(ctx, r.db, fieldID)
+}
+
+// UpdateFieldSchedule sahanın slot süresini, saat dilimini ve açılış saatlerini tek transaction içinde değiştirir.
+// Eski açılış saatleri tamamen yenileriyle değiştirilir; mevcut oyunlara dokunulmaz.
+func (r FieldRepository) UpdateFieldSchedule(ctx context.Context, field models.Field, hours []models.FieldOpeningHours) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model(&field).
+			Column("slot_minutes", "timezone").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewDelete().
+			Model((*models.FieldOpeningHours)(nil)).
+			Where("field_id = ?", field.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(hours) == 0 {
+			return nil
+		}
+		_, err = tx.NewInsert().Model(&hours).Exec(ctx)
+		return err
+	})
+}
+
+// GetFieldBlackouts sahanın [from, to) ile çakışan kapalı dönemlerini getirir
+func (r FieldRepository) GetFieldBlackouts(ctx context.Context, fieldID uint, from, to time.Time) ([]models.FieldBlackout, error) {
+	var blackouts []models.FieldBlackout
+	err := r.db.NewSelect().
+		Model(&blackouts).
+		Where("fb.field_id = ?", fieldID).
+		Where("fb.starts_at < ?", to).
+		Where("fb.ends_at > ?", from).
+		OrderExpr("fb.starts_at ASC").
+		Scan(ctx)
+	return blackouts, err
+}
+
+// CreateFieldBlackout sahayı bir dönem için kapatır. Bu dönemde aktif oyun varsa önce o oyunların iptal edilmesi gerekir.
+func (r FieldRepository) CreateFieldBlackout(ctx context.Context, blackout *models.FieldBlackout) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var id int64
+		err := tx.NewSelect().
+			Model((*models.Field)(nil)).
+			Column("id").
+			Where("id = ?", blackout.FieldID).
+			For("UPDATE").
+			Scan(ctx, &id)
+		if err != nil {
+			return err
+		}
+
+		booked, err := blockingGames(tx.NewSelect().Model((*models.Game)(nil)), blackout.StartsAt, blackout.EndsAt).
+			Where("g.field_id = ?", blackout.FieldID).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if booked {
+			return ErrBlackoutHasGames
+		}
+
+		_, err = tx.NewInsert().Model(blackout).Exec(ctx)
+		return err
+	})
+}
+
+func (r FieldRepository) DeleteFieldBlackout(ctx context.Context, fieldID uint, id int64) error {
+	result, err := r.db.NewDelete().
+		Model((*models.FieldBlackout)(nil)).
+		Where("id = ?", id).
+		Where("field_id = ?", fieldID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrFieldBlackoutNotFound
+	}
+
+	return nil
+}
+
+// GetFieldGames sahanın [from, to) ile çakışan ve sahayı meşgul eden oyunlarını getirir
+func (r FieldRepository) GetFieldGames(ctx context.Context, fieldID uint, from, to time.Time) ([]models.Game, error) {
+	var games []models.Game
+	err := blockingGames(r.db.NewSelect().Model(&games), from, to).
+		Where("g.field_id = ?", fieldID).
+		OrderExpr("g.start_time ASC").
+		Scan(ctx)
+	return games, err
+}
+
+func fieldOpeningHours(ctx context.Context, db bun.IDB, fieldID uint) ([]models.FieldOpeningHours, error) {
+	var hours []models.FieldOpeningHours
+	err := db.NewSelect().
+		Model(&hours).
+		Where("foh.field_id = ?", fieldID).
+		OrderExpr("foh.weekday ASC, foh.opens_at ASC").
+		Scan(ctx)
+	return hours, err
+}
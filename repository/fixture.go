@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/personal-project/pitch-league/models"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrFixturesExist       = errors.New("ligin fikstürü zaten oluşturulmuş, yeniden üretmeyi kullanın")
+	ErrFixtureRoundPlayed  = errors.New("yeniden üretilecek haftalarda oynanmış maç var")
+	ErrFixtureTeamsChanged = errors.New("fikstür oluşturulduktan sonra ligin takımları değişti, oynanmış haftalar varken fikstür yeniden üretilemez")
+	ErrLeagueTeamsChanged  = errors.New("fikstür üretilirken ligin takımları değişti, tekrar deneyin")
+)
+
+type IFixtureRepository interface {
+	GetLeagueTeamIDs(ctx context.Context, leagueID uint) ([]uint, error)
+	GetLastPlayedRound(ctx context.Context, leagueID uint) (int64, time.Time, error)
+	GetFieldBookings(ctx context.Context, fieldID uint, from, to time.Time, leagueID uint, fromRound int64) ([]models.Game, error)
+	CreateFixtures(ctx context.Context, leagueID uint, teamIDs []uint, matches []models.Match, booking *models.FixtureBooking) error
+	ReplaceUnplayedFixtures(ctx context.Context, leagueID uint, fromRound int64, teamIDs []uint, matches []models.Match, booking *models.FixtureBooking) error
+}
+
+type FixtureRepository struct {
+	db *bun.DB
+}
+
+func NewFixtureRepository(db *bun.DB) IFixtureRepository {
+	return &FixtureRepository{db: db}
+}
+
+// GetLeagueTeamIDs lige kayıtlı takımların id'lerini getirir
+func (r FixtureRepository) GetLeagueTeamIDs(ctx context.Context, leagueID uint) ([]uint, error) {
+	var teamIDs []uint
+	err := r.db.NewSelect().
+		Model((*models.LeagueTeam)(nil)).
+		Column("team_id").
+		Where("league_id = ?", leagueID).
+		OrderExpr("team_id ASC").
+		Scan(ctx, &teamIDs)
+	return teamIDs, err
+}
+
+// GetLastPlayedRound oynanmış (planlanmış veya ertelenmiş olmayan) maçı olan son haftayı ve o maçların en geç zamanını döner.
+// Fikstürde hiç oynanmış hafta yoksa 0 ve sıfır zaman döner.
+func (r FixtureRepository) GetLastPlayedRound(ctx context.Context, leagueID uint) (int64, time.Time, error) {
+	var (
+		round      int64
+		lastPlayed sql.NullTime
+	)
+	err := r.db.NewSelect().
+		Model((*models.Match)(nil)).
+		ColumnExpr("COALESCE(MAX(round), 0)").
+		ColumnExpr("MAX(match_time)").
+		Where("league_id = ?", leagueID).
+		Where("round IS NOT NULL").
+		Where("status NOT IN (?)", bun.In(models.UnplayedMatchStatuses)).
+		Scan(ctx, &round, &lastPlayed)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return round, lastPlayed.Time, nil
+}
+
+// GetFieldBookings sahanın aralıktaki dolu oyunlarını getirir. Ligin fromRound ve sonrasındaki fikstür maçlarına
+// ait oyunlar yeniden üretilecekleri için dolu sayılmaz.
+func (r FixtureRepository) GetFieldBookings(ctx context.Context, fieldID uint, from, to time.Time, leagueID uint, fromRound int64) ([]models.Game, error) {
+	var games []models.Game
+	err := blockingGames(r.db.NewSelect().Model(&games), from, to).
+		Where("g.field_id = ?", fieldID).
+		Where("NOT EXISTS (SELECT 1 FROM matches m WHERE m.game_id = g.id AND m.league_id = ? AND m.round >= ?)", leagueID, fromRound).
+		OrderExpr("g.start_time ASC").
+		Scan(ctx)
+	return games, err
+}
+
+// CreateFixtures ligin fikstürünü tek seferde kaydeder, lig satırı kilitlenerek aynı anda iki fikstür üretilmesi engellenir.
+// teamIDs fikstürün üretildiği takımlardır; o sırada ligin takımları değiştiyse ErrLeagueTeamsChanged döner.
+func (r FixtureRepository) CreateFixtures(ctx context.Context, leagueID uint, teamIDs []uint, matches []models.Match, booking *models.FixtureBooking) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := lockLeague(ctx, tx, leagueID); err != nil {
+			return err
+		}
+		if err := checkFixtureTeams(ctx, tx, leagueID, teamIDs, 1); err != nil {
+			return err
+		}
+
+		exists, err := tx.NewSelect().
+			Model((*models.Match)(nil)).
+			Where("league_id = ?", leagueID).
+			Where("round IS NOT NULL").
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrFixturesExist
+		}
+
+		return insertFixtures(ctx, tx, matches, booking)
+	})
+}
+
+// ReplaceUnplayedFixtures fromRound ve sonrasındaki planlanmış veya ertelenmiş maçları silip yerine yenilerini ekler.
+// Oynanmış haftalara dokunulmaz; bu haftalarda arada bir maç oynandıysa işlem geri alınır.
+// Oynanmış hafta varken ligin takımları fikstürdekilerden farklıysa eşleşmeler tutmayacağı için ErrFixtureTeamsChanged döner.
+func (r FixtureRepository) ReplaceUnplayedFixtures(ctx context.Context, leagueID uint, fromRound int64, teamIDs []uint, matches []models.Match, booking *models.FixtureBooking) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := lockLeague(ctx, tx, leagueID); err != nil {
+			return err
+		}
+		if err := checkFixtureTeams(ctx, tx, leagueID, teamIDs, fromRound); err != nil {
+			return err
+		}
+
+		played, err := tx.NewSelect().
+			Model((*models.Match)(nil)).
+			Where("league_id = ?", leagueID).
+			Where("round >= ?", fromRound).
+			Where("status NOT IN (?)", bun.In(models.UnplayedMatchStatuses)).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if played {
+			return ErrFixtureRoundPlayed
+		}
+
+		var gameIDs []int64
+		err = tx.NewSelect().
+			Model((*models.Match)(nil)).
+			Column("game_id").
+			Where("league_id = ?", leagueID).
+			Where("round >= ?", fromRound).
+			Where("game_id IS NOT NULL").
+			Scan(ctx, &gameIDs)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewDelete().
+			Model((*models.Match)(nil)).
+			Where("league_id = ?", leagueID).
+			Where("round >= ?", fromRound).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Silinen maçlar için ayrılmış sahalar boşa çıkarılır
+		if err := cancelGames(ctx, tx, gameIDs, "fikstür yeniden üretildi"); err != nil {
+			return err
+		}
+
+		return insertFixtures(ctx, tx, matches, booking)
+	})
+}
+
+// insertFixtures maçları ekler. booking verilirse her maç için sahada bir oyun açılır ve maç o oyuna bağlanır;
+// saha o saatte doluysa ErrFieldDoubleBooked döner ve fikstür hiç kaydedilmez.
+func insertFixtures(ctx context.Context, tx bun.Tx, matches []models.Match, booking *models.FixtureBooking) error {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	if booking != nil {
+		var capacity int64
+		err := tx.NewSelect().
+			Model((*models.Field)(nil)).
+			Column("capacity").
+			Where("id = ?", booking.FieldID).
+			Scan(ctx, &capacity)
+		if err != nil {
+			return err
+		}
+
+		for i := range matches {
+			game := models.Game{
+				FieldID:    booking.FieldID,
+				HostID:     booking.HostID,
+				StartTime:  matches[i].MatchTime,
+				EndTime:    matches[i].MatchTime.Add(booking.Duration),
+				MaxPlayers: capacity,
+				Status:     models.GameStatusAccepted,
+			}
+			if err := checkFieldSlot(ctx, tx, game.FieldID, game.StartTime, game.EndTime, 0); err != nil {
+				return err
+			}
+			if _, err := tx.NewInsert().Model(&game).Exec(ctx); err != nil {
+				return bookingError(err)
+			}
+
+			matches[i].GameID = uint(game.ID)
+			if err := checkMatchClash(ctx, tx, matches[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := tx.NewInsert().Model(&matches).Exec(ctx)
+	return err
+}
+
+func lockLeague(ctx context.Context, tx bun.Tx, leagueID uint) error {
+	var id int64
+	err := tx.NewSelect().
+		Model((*models.League)(nil)).
+		Column("id").
+		Where("id = ?", leagueID).
+		For("UPDATE").
+		Scan(ctx, &id)
+	return err
+}
+
+// checkFixtureTeams ligin takımlarını kilitleyip fikstürün üretildiği teamIDs ile karşılaştırır. Takım eklemek lig satırının
+// anahtar kilidini, takım çıkarmak league_teams satırını beklediği için işlem bitene kadar takımlar değişemez.
+// fromRound 1'den büyükse oynanmış haftaların eşleşmeleri korunacağından takımlar mevcut fikstürdekilerle de aynı olmalıdır.
+func checkFixtureTeams(ctx context.Context, tx bun.Tx, leagueID uint, teamIDs []uint, fromRound int64) error {
+	var leagueTeams []uint
+	err := tx.NewSelect().
+		Model((*models.LeagueTeam)(nil)).
+		Column("team_id").
+		Where("league_id = ?", leagueID).
+		OrderExpr("team_id ASC").
+		For("UPDATE").
+		Scan(ctx, &leagueTeams)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(leagueTeams, teamIDs) {
+		return ErrLeagueTeamsChanged
+	}
+	if fromRound <= 1 {
+		return nil
+	}
+
+	home := tx.NewSelect().
+		Model((*models.Match)(nil)).
+		ColumnExpr("home_team_id AS team_id").
+		Where("league_id = ?", leagueID).
+		Where("round IS NOT NULL")
+	away := tx.NewSelect().
+		Model((*models.Match)(nil)).
+		ColumnExpr("away_team_id AS team_id").
+		Where("league_id = ?", leagueID).
+		Where("round IS NOT NULL")
+
+	var fixtureTeams []uint
+	err = tx.NewSelect().
+		TableExpr("(?) AS x", home.Union(away)).
+		Column("x.team_id").
+		OrderExpr("x.team_id ASC").
+		Scan(ctx, &fixtureTeams)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(leagueTeams, fixtureTeams) {
+		return ErrFixtureTeamsChanged
+	}
+	return nil
+}
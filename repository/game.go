@@ -2,12 +2,25 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/personal-project/pitch-league/models"
 	"github.com/uptrace/bun"
 )
 
+var (
+	ErrGameNotFound          = errors.New("oyun bulunamadı")
+	ErrInvalidGameTransition = errors.New("oyunun durumu bu şekilde değiştirilemez")
+	ErrGameClosed            = errors.New("reddedilmiş, iptal edilmiş veya bitmiş oyun düzenlenemez")
+	ErrGameNotStarted        = errors.New("başlamamış oyun bitirilemez")
+	ErrGameResultRequired    = errors.New("oyunu bitirmek için oyundaki her takımın skoru girilmeli")
+	ErrGameMatchNotCompleted = errors.New("lig maçına bağlı oyun, maçın sonucu girilmeden bitirilemez")
+	ErrGameReserved          = errors.New("rezervasyonlu oyunun durumu rezervasyon üzerinden değiştirilmeli")
+)
+
 type IGameRepository interface {
 	IBaseRepository[models.Game]
 	GetAllGame(ctx context.Context) ([]models.Game, error)
@@ -15,6 +28,10 @@ type IGameRepository interface {
 	DeleteByGameID(ctx context.Context, id int64) error
 	UpdateGame(ctx context.Context, m models.Game) error
 	CreateGame(ctx context.Context, game models.Game) error
+	ChangeGameStatus(ctx context.Context, id int64, change models.GameStatusChange, scores []models.GameScore) (*models.Game, error)
+	GetGameStatusHistory(ctx context.Context, id int64) ([]models.GameStatusChange, error)
+	GetUserUpcomingGames(ctx context.Context, userID int64) ([]models.Game, error)
+	ClaimGameStatusNotifications(ctx context.Context, limit int) ([]models.GameStatusNotification, error)
 }
 
 type GameRepository struct {
@@ -45,9 +62,12 @@ func (r GameRepository) GetByGameID(ctx context.Context, id int64) (*models.Game
 		Model(game).
 		Relation("Host").
 		Relation("Field").
+		Relation("Scores").
 		Where("g.id = ?", id).
 		Scan(ctx)
-
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGameNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -77,13 +97,14 @@ func (r GameRepository) DeleteByGameID(ctx context.Context, id int64) error {
 	return nil
 }
 
+// UpdateGame oyunun saha, saat, host ve kapasitesini günceller, durum sadece ChangeGameStatus ile değişir. Yeni saat
+// aralığında sahanın boş olduğu ve kayıtlı oyuncuların başka bir oyunla çakışmadığı aynı transaction içinde doğrulanır.
 func (r GameRepository) UpdateGame(ctx context.Context, m models.Game) error {
 	// Host kontrolü
-	var hostExists bool
-	err := r.db.NewSelect().
+	hostExists, err := r.db.NewSelect().
 		Model((*models.User)(nil)).
 		Where("id = ?", m.HostID).
-		Scan(ctx, &hostExists)
+		Exists(ctx)
 	if err != nil {
 		return err
 	}
@@ -91,29 +112,318 @@ func (r GameRepository) UpdateGame(ctx context.Context, m models.Game) error {
 		return errors.New("geçersiz hostID: böyle bir kullanıcı mevcut değil")
 	}
 
-	// Field kontrolü
-	var fieldExists bool
-	err = r.db.NewSelect().
-		Model((*models.Field)(nil)).
-		Where("id = ?", m.FieldID).
-		Scan(ctx, &fieldExists)
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		current, err := lockGame(ctx, tx, m.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status != models.GameStatusPending && current.Status != models.GameStatusAccepted {
+			return ErrGameClosed
+		}
+		// Rezervasyonun fiyatı oyunun saatine göre hesaplandığı için rezervasyonlu oyun taşınamaz
+		reserved, err := hasActiveReservation(ctx, tx, m.ID)
+		if err != nil {
+			return err
+		}
+		if reserved {
+			return ErrGameReserved
+		}
+
+		if err := checkFieldSlot(ctx, tx, m.FieldID, m.StartTime, m.EndTime, m.ID); err != nil {
+			return err
+		}
+		if err := checkParticipantsClash(ctx, tx, m.ID, m.StartTime, m.EndTime); err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model(&m).
+			ExcludeColumn("status").
+			WherePK().
+			Exec(ctx)
+		return bookingError(err)
+	})
+}
+
+// CreateGame sahanın seçilen saat aralığında boş olduğunu doğrulayıp oyunu PENDING olarak ekler, dolu sahada ErrFieldDoubleBooked döner
+func (r GameRepository) CreateGame(ctx context.Context, game models.Game) error {
+	game.Status = models.GameStatusPending
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := checkFieldSlot(ctx, tx, game.FieldID, game.StartTime, game.EndTime, 0); err != nil {
+			return err
+		}
+
+		_, err := tx.NewInsert().
+			Model(&game).
+			Exec(ctx)
+		return bookingError(err)
+	})
+}
+
+// ChangeGameStatus oyunu change.ToStatus durumuna geçirip değişikliği geçmişe yazar ve oyunun önceki halini döner.
+// Sadece izin verilen geçişler yapılabilir. Oyunu bitirmek için oyun başlamış olmalı ve sonucu girilmiş olmalıdır:
+// lig maçına bağlı oyunlarda maç COMPLETED olmalı, diğerlerinde oyundaki her takımın skoru scores ile verilmelidir.
+func (r GameRepository) ChangeGameStatus(ctx context.Context, id int64, change models.GameStatusChange, scores []models.GameScore) (*models.Game, error) {
+	var before *models.Game
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		game, err := lockGame(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		before = game
+
+		if !game.Status.CanTransitionTo(change.ToStatus) {
+			return fmt.Errorf("%w: %s -> %s", ErrInvalidGameTransition, game.Status, change.ToStatus)
+		}
+
+		// Rezervasyonun ödemesi ve iadesi oyunun durumuna bağlıdır, bu yüzden rezervasyonlu oyunlar sadece bitirilebilir
+		if change.ToStatus != models.GameStatusFinished {
+			reserved, err := hasActiveReservation(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if reserved {
+				return ErrGameReserved
+			}
+		}
+
+		if change.ToStatus == models.GameStatusFinished {
+			if err := finishGame(ctx, tx, *game, scores); err != nil {
+				return err
+			}
+		}
+
+		return setGameStatus(ctx, tx, *game, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return before, nil
+}
+
+func (r GameRepository) GetGameStatusHistory(ctx context.Context, id int64) ([]models.GameStatusChange, error) {
+	var changes []models.GameStatusChange
+	err := r.db.NewSelect().
+		Model(&changes).
+		Where("gsc.game_id = ?", id).
+		OrderExpr("gsc.created_at ASC, gsc.id ASC").
+		Scan(ctx)
+	return changes, err
+}
+
+// GetUserUpcomingGames kullanıcının oyuncu veya host olduğu, henüz bitmemiş bekleyen ve kabul edilmiş oyunları getirir
+func (r GameRepository) GetUserUpcomingGames(ctx context.Context, userID int64) ([]models.Game, error) {
+	var games []models.Game
+	err := r.db.NewSelect().
+		Model(&games).
+		Relation("Host").
+		Relation("Field").
+		Where("g.status IN (?)", bun.In([]models.GameStatus{models.GameStatusPending, models.GameStatusAccepted})).
+		Where("g.end_time > ?", time.Now()).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("g.host_id = ?", userID).
+				WhereOr("EXISTS (SELECT 1 FROM game_participants AS gp WHERE gp.game_id = g.id AND gp.user_id = ?)", userID)
+		}).
+		OrderExpr("g.start_time ASC").
+		Scan(ctx)
+	return games, err
+}
+
+// finishGame oyunun bitirilebileceğini doğrulayıp skorlarını kaydeder
+func finishGame(ctx context.Context, tx bun.Tx, game models.Game, scores []models.GameScore) error {
+	if game.StartTime.After(time.Now()) {
+		return ErrGameNotStarted
+	}
+
+	var matches []models.Match
+	err := tx.NewSelect().
+		Model(&matches).
+		Where("m.game_id = ?", game.ID).
+		Scan(ctx)
 	if err != nil {
 		return err
 	}
-	if !fieldExists {
-		return errors.New("geçersiz fieldID: böyle bir saha mevcut değil")
+	if len(matches) > 0 {
+		for _, match := range matches {
+			if !match.Status.CountsInStandings() {
+				return ErrGameMatchNotCompleted
+			}
+		}
+		return nil
+	}
+
+	var teamIDs []uint
+	err = tx.NewSelect().
+		Model((*models.GameParticipants)(nil)).
+		ColumnExpr("DISTINCT team_id").
+		Where("game_id = ?", game.ID).
+		Scan(ctx, &teamIDs)
+	if err != nil {
+		return err
+	}
+
+	// Oyundaki her takımın tam olarak bir skoru olmalı
+	if len(teamIDs) == 0 || len(scores) != len(teamIDs) {
+		return ErrGameResultRequired
+	}
+	expected := make(map[uint]bool, len(teamIDs))
+	for _, teamID := range teamIDs {
+		expected[teamID] = true
+	}
+	for _, score := range scores {
+		if !expected[score.TeamID] || score.Score < 0 {
+			return ErrGameResultRequired
+		}
+		delete(expected, score.TeamID)
 	}
 
-	_, err = r.db.NewUpdate().
-		Model(&m).
-		WherePK().
+	for i := range scores {
+		scores[i].GameID = game.ID
+	}
+	_, err = tx.NewInsert().
+		Model(&scores).
 		Exec(ctx)
 	return err
 }
 
-func (r GameRepository) CreateGame(ctx context.Context, game models.Game) error {
-	_, err := r.db.NewInsert().
-		Model(&game).
+func hasActiveReservation(ctx context.Context, tx bun.Tx, gameID int64) (bool, error) {
+	return tx.NewSelect().
+		Model((*models.Reservation)(nil)).
+		Where("game_id = ?", gameID).
+		Where("status IN (?)", bun.In([]models.ReservationStatus{models.ReservationStatusHeld, models.ReservationStatusConfirmed})).
+		Exists(ctx)
+}
+
+func lockGame(ctx context.Context, tx bun.Tx, id int64) (*models.Game, error) {
+	game := new(models.Game)
+	err := tx.NewSelect().
+		Model(game).
+		Where("g.id = ?", id).
+		For("UPDATE").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGameNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// transitionGame oyunu kilitleyip, geçiş izin veriliyorsa durumunu değiştirir
+func transitionGame(ctx context.Context, tx bun.Tx, id int64, change models.GameStatusChange) error {
+	game, err := lockGame(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if !game.Status.CanTransitionTo(change.ToStatus) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidGameTransition, game.Status, change.ToStatus)
+	}
+	return setGameStatus(ctx, tx, *game, change)
+}
+
+// setGameStatus oyunun durumunu değiştirip değişikliği geçmişe yazar, geçişin geçerliliğini çağıran kontrol eder
+func setGameStatus(ctx context.Context, tx bun.Tx, game models.Game, change models.GameStatusChange) error {
+	_, err := tx.NewUpdate().
+		Model((*models.Game)(nil)).
+		Set("status = ?", change.ToStatus).
+		Where("id = ?", game.ID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	change.GameID = game.ID
+	change.FromStatus = game.Status
+	_, err = tx.NewInsert().
+		Model(&change).
 		Exec(ctx)
 	return err
 }
+
+// ClaimGameStatusNotifications henüz bildirilmemiş iptal ve ret değişikliklerini gönderilmek üzere işaretleyip döner.
+// Değişiklikler oyunun durumuyla aynı transaction içinde yazıldığı için yalnızca commit edilmiş olanlar görülür;
+// hangi yoldan iptal edildiğine bakılmaksızın her oyun bir kez bildirilir. Birden fazla sunucu aynı satırları almaz.
+func (r GameRepository) ClaimGameStatusNotifications(ctx context.Context, limit int) ([]models.GameStatusNotification, error) {
+	var notifications []models.GameStatusNotification
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var changes []models.GameStatusChange
+		err := tx.NewSelect().
+			Model(&changes).
+			Where("gsc.notified_at IS NULL").
+			Where("gsc.to_status IN (?)", bun.In(models.NotifiedGameStatuses)).
+			OrderExpr("gsc.id ASC").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+		if err != nil || len(changes) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(changes))
+		for i, change := range changes {
+			ids[i] = change.ID
+		}
+		_, err = tx.NewUpdate().
+			Model((*models.GameStatusChange)(nil)).
+			Set("notified_at = ?", time.Now()).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			notification := models.GameStatusNotification{Change: change}
+			err := tx.NewSelect().
+				Model(&notification.Game).
+				Where("g.id = ?", change.GameID).
+				Scan(ctx)
+			if err != nil {
+				return err
+			}
+
+			err = tx.NewSelect().
+				Model(&notification.Users).
+				Join("JOIN game_participants gp ON gp.user_id = \"user\".id").
+				Where("gp.game_id = ?", change.GameID).
+				Scan(ctx)
+			if err != nil {
+				return err
+			}
+			notifications = append(notifications, notification)
+		}
+		return nil
+	})
+	return notifications, err
+}
+
+// cancelGames sistemin iptal ettiği oyunları (süresi dolan rezervasyonlar, silinen fikstür maçları) CANCELLED yapar.
+// Zaten kapanmış oyunlar olduğu gibi bırakılır.
+func cancelGames(ctx context.Context, tx bun.Tx, ids []int64, reason string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var games []models.Game
+	err := tx.NewSelect().
+		Model(&games).
+		Where("g.id IN (?)", bun.In(ids)).
+		Where("g.status IN (?)", bun.In([]models.GameStatus{models.GameStatusPending, models.GameStatusAccepted})).
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, game := range games {
+		err := setGameStatus(ctx, tx, game, models.GameStatusChange{
+			ToStatus: models.GameStatusCancelled,
+			Reason:   reason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -12,7 +12,6 @@ import (
 type IGameParticipantsRepository interface {
 	IBaseRepository[models.GameParticipants]
 	GetAllGameParticipants(ctx context.Context) ([]models.GameParticipants, error)
-	GetByGameParticipantsID(ctx context.Context, userID int64) (*models.GameParticipants, error)
 	GetGameParticipantsUsers(ctx context.Context, gameID uint) ([]models.User, error)
 	DeleteByGameParticipantsID(ctx context.Context, id int64) error
 	UpdateGameParticipants(ctx context.Context, m models.GameParticipants) error
@@ -46,26 +45,6 @@ func (r GameParticipantsRepository) GetAllGameParticipants(ctx context.Context)
 	return gameParts, err
 }
 
-func (r GameParticipantsRepository) GetByGameParticipantsID(ctx context.Context, userID int64) (*models.GameParticipants, error) {
-	gamePart := new(models.GameParticipants)
-	err := r.db.NewSelect().
-		Model(gamePart).
-		Relation("User").
-		Relation("Game").
-		Relation("Game.Field").
-		Relation("Game.Host").
-		Relation("Team").
-		Relation("Team.Captain").
-		Where("gp.user_id = ?", userID).
-		Scan(ctx)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return gamePart, nil
-}
-
 func (r GameParticipantsRepository) GetGameParticipantsUsers(ctx context.Context, gameID uint) ([]models.User, error) {
 	var users []models.User
 	err := r.db.NewSelect().
@@ -138,10 +117,23 @@ func (r GameParticipantsRepository) CreateGameParticipants(ctx context.Context,
 		return fmt.Errorf("takım bulunamadı: %w", err)
 	}
 
-	_, err = r.db.NewInsert().
-		Model(&gamePart).
-		Exec(ctx)
-	return err
+	// Oyuncu ve takım aynı saatte iki farklı oyunda olamaz, cezalı oyuncu takımının lig maçında oynayamaz
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := checkPlayerClash(ctx, tx, gamePart.UserID, game.StartTime, game.EndTime, game.ID); err != nil {
+			return err
+		}
+		if err := checkTeamClash(ctx, tx, gamePart.TeamID, game.StartTime, game.EndTime, game.ID); err != nil {
+			return err
+		}
+		if err := checkSuspension(ctx, tx, gamePart); err != nil {
+			return err
+		}
+
+		_, err := tx.NewInsert().
+			Model(&gamePart).
+			Exec(ctx)
+		return err
+	})
 }
 
 func (r GameParticipantsRepository) FixGameParticipantsOnTeamChange(ctx context.Context, userID, teamID int64) error {
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/personal-project/pitch-league/models"
+	"github.com/uptrace/bun"
+)
+
+type ILeaderboardRepository interface {
+	GetTopScorers(ctx context.Context, f models.LeaderboardFilter) (models.LeaderboardPage[models.PlayerLeaderboardRow], error)
+	GetTopAssists(ctx context.Context, f models.LeaderboardFilter) (models.LeaderboardPage[models.PlayerLeaderboardRow], error)
+	GetCleanSheets(ctx context.Context, f models.LeaderboardFilter) (models.LeaderboardPage[models.CleanSheetRow], error)
+	GetCardTable(ctx context.Context, f models.LeaderboardFilter) (models.LeaderboardPage[models.CardRow], error)
+}
+
+type LeaderboardRepository struct {
+	db *bun.DB
+}
+
+func NewLeaderboardRepository(db *bun.DB) ILeaderboardRepository {
+	return &LeaderboardRepository{db: db}
+}
+
+// GetTopScorers tamamlanmış maçlardaki gollerden gol krallığını getirir, kendi kalesine goller sayılmaz
+func (r LeaderboardRepository) GetTopScorers(ctx context.Context, f models.LeaderboardFilter) (models.LeaderboardPage[models.PlayerLeaderboardRow], error) {
+	stats := leaderboardEvents(r.db, f, models.MatchStatusCompleted).
+		ColumnExpr("me.user_id, me.team_id, COUNT(*) AS count, COUNT(DISTINCT me.match_id) AS matches").
+		Where("me.type = ?", models.MatchEventGoal).
+		GroupExpr("me.user_id, me.team_id")
+	if f.TeamID != 0 {
+		stats.Where("me.team_id = ?", f.TeamID)
+	}
+
+	return playerLeaderboard(ctx, r.db, stats, f)
+}
+
+// GetTopAssists tamamlanmış maçlardaki gollerin asistlerinden asist sıralamasını getirir
+func (r LeaderboardRepository) GetTopAssists(ctx context.Context, f models.LeaderboardFilter) (models.LeaderboardPage[models.PlayerLeaderboardRow], error) {
+	stats := leaderboardEvents(r.db, f, models.MatchStatusCompleted).
+		Join("JOIN game_participants AS gp ON gp.id = me.assist_participant_id").
+		ColumnExpr("gp.user_id, gp.team_id, COUNT(*) AS count, COUNT(DISTINCT me.match_id) AS matches").
+		Where("me.type = ?", models.MatchEventGoal).
+		GroupExpr("gp.user_id, gp.team_id")
+	if f.TeamID != 0 {
+		stats.Where("gp.team_id = ?", f.TeamID)
+	}
+
+	return playerLeaderboard(ctx, r.db, stats, f)
+}
+
+// GetCleanSheets takımların gol yemeden bitirdiği tamamlanmış maçları sayar, hiç gol yemediği maçı olmayan takımlar listelenmez
+func (r LeaderboardRepository) GetCleanSheets(ctx context.Context, f models.LeaderboardFilter) (models.LeaderboardPage[models.CleanSheetRow], error) {
+	home := leaderboardMatches(r.db.NewSelect().TableExpr("matches AS m"), f, models.MatchStatusCompleted).
+		ColumnExpr("m.home_team_id AS team_id, m.away_score AS conceded")
+	away := leaderboardMatches(r.db.NewSelect().TableExpr("matches AS m"), f, models.MatchStatusCompleted).
+		ColumnExpr("m.away_team_id AS team_id, m.home_score AS conceded")
+
+	stats := r.db.NewSelect().
+		TableExpr("(?) AS x", home.UnionAll(away)).
+		ColumnExpr("x.team_id, COUNT(*) FILTER (WHERE x.conceded = 0) AS clean_sheets, COUNT(*) AS matches").
+		GroupExpr("x.team_id").
+		Having("COUNT(*) FILTER (WHERE x.conceded = 0) > 0")
+	if f.TeamID != 0 {
+		stats.Where("x.team_id = ?", f.TeamID)
+	}
+
+	page := models.LeaderboardPage[models.CleanSheetRow]{Rows: []models.CleanSheetRow{}}
+	total, err := countLeaderboard(ctx, r.db, stats)
+	if err != nil || total == 0 {
+		return page, err
+	}
+	page.Total = total
+
+	err = r.db.NewSelect().
+		TableExpr("(?) AS s", stats).
+		Join("JOIN teams AS t ON t.id = s.team_id").
+		ColumnExpr("RANK() OVER (ORDER BY s.clean_sheets DESC) AS rank").
+		ColumnExpr("s.team_id, t.name AS team_name, s.clean_sheets, s.matches").
+		OrderExpr("rank ASC, s.matches ASC, t.name ASC").
+		Limit(f.Limit).
+		Offset(f.Offset).
+		Scan(ctx, &page.Rows)
+	return page, err
+}
+
+// GetCardTable ligdeki tüm maçlarda görülen kartları oyuncu başına disiplin puanına göre sıralar
+func (r LeaderboardRepository) GetCardTable(ctx context.Context, f models.LeaderboardFilter) (models.LeaderboardPage[models.CardRow], error) {
+	stats := leaderboardEvents(r.db, f, models.MatchEventStatuses...).
+		ColumnExpr("me.user_id, me.team_id").
+		ColumnExpr("COUNT(*) FILTER (WHERE me.type = ?) AS yellow_cards", models.MatchEventYellowCard).
+		ColumnExpr("COUNT(*) FILTER (WHERE me.type = ?) AS red_cards", models.MatchEventRedCard).
+		ColumnExpr("SUM(CASE me.type WHEN ? THEN ?::bigint WHEN ? THEN ?::bigint ELSE 0 END) AS points",
+			models.MatchEventYellowCard, models.MatchEventYellowCard.FairPlayPoints(),
+			models.MatchEventRedCard, models.MatchEventRedCard.FairPlayPoints()).
+		Where("me.type IN (?)", bun.In([]models.MatchEventType{models.MatchEventYellowCard, models.MatchEventRedCard})).
+		GroupExpr("me.user_id, me.team_id")
+	if f.TeamID != 0 {
+		stats.Where("me.team_id = ?", f.TeamID)
+	}
+
+	page := models.LeaderboardPage[models.CardRow]{Rows: []models.CardRow{}}
+	total, err := countLeaderboard(ctx, r.db, stats)
+	if err != nil || total == 0 {
+		return page, err
+	}
+	page.Total = total
+
+	err = r.db.NewSelect().
+		TableExpr("(?) AS s", stats).
+		Join("JOIN users AS u ON u.id = s.user_id").
+		Join("JOIN teams AS t ON t.id = s.team_id").
+		ColumnExpr("RANK() OVER (ORDER BY s.points DESC, s.red_cards DESC) AS rank").
+		ColumnExpr("s.user_id, u.name, u.surname, u.username, s.team_id, t.name AS team_name").
+		ColumnExpr("s.yellow_cards, s.red_cards, s.points").
+		OrderExpr("rank ASC, u.name ASC, s.user_id ASC").
+		Limit(f.Limit).
+		Offset(f.Offset).
+		Scan(ctx, &page.Rows)
+	return page, err
+}
+
+// playerLeaderboard user_id, team_id, count ve matches dönen istatistik sorgusunu sıralayıp istenen sayfasını getirir
+func playerLeaderboard(ctx context.Context, db *bun.DB, stats *bun.SelectQuery, f models.LeaderboardFilter) (models.LeaderboardPage[models.PlayerLeaderboardRow], error) {
+	page := models.LeaderboardPage[models.PlayerLeaderboardRow]{Rows: []models.PlayerLeaderboardRow{}}
+	total, err := countLeaderboard(ctx, db, stats)
+	if err != nil || total == 0 {
+		return page, err
+	}
+	page.Total = total
+
+	err = db.NewSelect().
+		TableExpr("(?) AS s", stats).
+		Join("JOIN users AS u ON u.id = s.user_id").
+		Join("JOIN teams AS t ON t.id = s.team_id").
+		ColumnExpr("RANK() OVER (ORDER BY s.count DESC) AS rank").
+		ColumnExpr("s.user_id, u.name, u.surname, u.username, s.team_id, t.name AS team_name, s.count, s.matches").
+		OrderExpr("rank ASC, s.matches ASC, u.name ASC, s.user_id ASC").
+		Limit(f.Limit).
+		Offset(f.Offset).
+		Scan(ctx, &page.Rows)
+	return page, err
+}
+
+func countLeaderboard(ctx context.Context, db *bun.DB, stats *bun.SelectQuery) (int64, error) {
+	var total int64
+	err := db.NewSelect().
+		TableExpr("(?) AS s", stats).
+		ColumnExpr("COUNT(*)").
+		Scan(ctx, &total)
+	return total, err
+}
+
+// leaderboardEvents ligin filtreye uyan maçlarındaki olayları seçen sorguyu başlatır
+func leaderboardEvents(db *bun.DB, f models.LeaderboardFilter, statuses ...models.MatchStatus) *bun.SelectQuery {
+	q := db.NewSelect().
+		TableExpr("match_events AS me").
+		Join("JOIN matches AS m ON m.id = me.match_id")
+	return leaderboardMatches(q, f, statuses...)
+}
+
+// leaderboardMatches "m" takma adlı maçları lige, duruma ve tarih aralığına göre daraltır
+func leaderboardMatches(q *bun.SelectQuery, f models.LeaderboardFilter, statuses ...models.MatchStatus) *bun.SelectQuery {
+	q.Where("m.league_id = ?", f.LeagueID).
+		Where("m.status IN (?)", bun.In(statuses))
+	if !f.From.IsZero() {
+		q.Where("m.match_time >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q.Where("m.match_time < ?", f.To)
+	}
+	return q
+}
@@ -15,6 +15,8 @@ type ILeagueRepository interface {
 	DeleteByLeagueID(ctx context.Context, id int64) error
 	UpdateLeague(ctx context.Context, m models.League) error
 	CreateLeague(ctx context.Context, league models.League) error
+	UpdateLeagueRules(ctx context.Context, league models.League) error
+	GetUserLeagues(ctx context.Context, userID int64) ([]models.League, error)
 }
 
 type LeagueRepository struct {
@@ -87,3 +89,39 @@ func (r LeagueRepository) CreateLeague(ctx context.Context, league models.League
 		Exec(ctx)
 	return err
 }
+
+// UpdateLeagueRules ligin puanlama, eşitlik ve kart cezası kurallarını günceller, puan durumu ve kart cezaları
+// yeni kurallarla yeniden kurulur
+func (r LeagueRepository) UpdateLeagueRules(ctx context.Context, league models.League) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model(&league).
+			Column("points_per_win", "points_per_draw", "points_per_loss",
+				"forfeit_goals_for", "forfeit_goals_against", "forfeit_points_deduction", "tiebreakers",
+				"yellow_cards_per_ban", "red_card_ban_matches").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := syncLeagueSuspensions(ctx, tx, uint(league.ID)); err != nil {
+			return err
+		}
+		return rebuildLeagueStandings(ctx, tx, uint(league.ID))
+	})
+}
+
+// GetUserLeagues kullanıcının aktif üyesi olduğu takımların kayıtlı olduğu ligleri getirir
+func (r LeagueRepository) GetUserLeagues(ctx context.Context, userID int64) ([]models.League, error) {
+	var leagues []models.League
+	err := r.db.NewSelect().
+		Model(&leagues).
+		Where("l.id IN (?)", r.db.NewSelect().
+			TableExpr("league_teams AS lt").
+			ColumnExpr("lt.league_id").
+			Where("lt.team_id IN (?)", userTeamIDs(r.db, userID))).
+		OrderExpr("l.start_date DESC, l.name ASC").
+		Scan(ctx)
+	return leagues, err
+}
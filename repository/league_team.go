@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 
@@ -80,26 +81,24 @@ func (r LeagueTeamRepository) GetByLeagueID(ctx context.Context, id int64) ([]mo
 	return leagueTeams, nil
 }
 
+// DeleteByLeagueTeamID takımı ligden çıkarır ve kalan takımların sıralamasını yeniden kurar
 func (r LeagueTeamRepository) DeleteByLeagueTeamID(ctx context.Context, id int64) error {
-	result, err := r.db.NewDelete().
-		Model((*models.LeagueTeam)(nil)).
-		Where("id = ?", id).
-		Exec(ctx)
-
-	if err != nil {
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-
-	if rowsAffected == 0 {
-		return errors.New("league team not found")
-	}
-
-	return nil
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var leagueID uint
+		err := tx.NewDelete().
+			Model((*models.LeagueTeam)(nil)).
+			Where("id = ?", id).
+			Returning("league_id").
+			Scan(ctx, &leagueID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("league team not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		return rebuildLeagueStandings(ctx, tx, leagueID)
+	})
 }
 
 func (r LeagueTeamRepository) UpdateLeagueTeam(ctx context.Context, m models.LeagueTeam) error {
@@ -131,8 +130,12 @@ func (r LeagueTeamRepository) CreateLeagueTeam(ctx context.Context, leagueTeam m
 		return fmt.Errorf("takım bulunamadı: %w", err)
 	}
 
-	_, err = r.db.NewInsert().
-		Model(&leagueTeam).
-		Exec(ctx)
-	return err
+	// Puan ve sıra maç sonuçlarından hesaplanır, yeni takım tabloya bu şekilde girer
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(&leagueTeam).Exec(ctx); err != nil {
+			return err
+		}
+
+		return rebuildLeagueStandings(ctx, tx, leagueTeam.LeagueID)
+	})
 }
@@ -2,12 +2,22 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/personal-project/pitch-league/models"
 	"github.com/uptrace/bun"
 )
 
+var (
+	ErrMatchNotFound          = errors.New("maç bulunamadı")
+	ErrInvalidMatchTransition = errors.New("maçın durumu bu şekilde değiştirilemez")
+	ErrMatchNotPlayed         = errors.New("başlamamış maçın sonucu bildirilemez")
+	ErrMatchResultFinal       = errors.New("maçın sonucu kesinleşmiş, düzeltme için lig yönetimine başvurun")
+	ErrNotMatchTeam           = errors.New("takım bu maçta oynamıyor")
+)
+
 type IMatchRepository interface {
 	IBaseRepository[models.Match]
 	GetAllMatch(ctx context.Context) ([]models.Match, error)
@@ -15,8 +25,10 @@ type IMatchRepository interface {
 	DeleteByMatchID(ctx context.Context, id int64) error
 	UpdateMatch(ctx context.Context, m models.Match) error
 	CreateMatch(ctx context.Context, match models.Match) error
-	UpdateLeagueStandings(ctx context.Context, match models.Match) error
-	RecalculateRankings(ctx context.Context, leagueID uint) error
+	ChangeMatchStatus(ctx context.Context, m models.Match, from models.MatchStatus, changedBy int64) error
+	RecordMatchResult(ctx context.Context, id int64, homeScore, awayScore int64, changedBy int64) error
+	SubmitMatchResult(ctx context.Context, submission models.MatchResultSubmission) (bool, error)
+	GetUserMatches(ctx context.Context, userID int64, upcoming bool) ([]models.Match, error)
 }
 
 type MatchRepository struct {
@@ -59,6 +71,7 @@ func (r MatchRepository) GetByMatchID(ctx context.Context, id int64) (*models.Ma
 		Relation("Game.Field").
 		Relation("HomeTeam.Captain").
 		Relation("AwayTeam.Captain").
+		Relation("ResultSubmissions").
 		Where("m.id = ?", id).
 		Scan(ctx)
 
@@ -69,137 +82,312 @@ func (r MatchRepository) GetByMatchID(ctx context.Context, id int64) (*models.Ma
 	return match, nil
 }
 
-func (r MatchRepository) DeleteByMatchID(ctx context.Context, id int64) error {
-	result, err := r.db.NewDelete().
-		Model((*models.Match)(nil)).
-		Where("id = ?", id).
-		Exec(ctx)
-
-	if err != nil {
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
+// GetUserMatches kullanıcının aktif üyesi olduğu takımların maçlarını getirir. upcoming ise sadece sonucu henüz belli
+// olmayan maçlar en yakından başlayarak, değilse tüm maçlar en yeniden başlayarak döner.
+func (r MatchRepository) GetUserMatches(ctx context.Context, userID int64, upcoming bool) ([]models.Match, error) {
+	var matches []models.Match
+	q := r.db.NewSelect().
+		Model(&matches).
+		Relation("League").
+		Relation("HomeTeam").
+		Relation("AwayTeam").
+		Relation("Game").
+		Relation("Game.Field").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("m.home_team_id IN (?)", userTeamIDs(r.db, userID)).
+				WhereOr("m.away_team_id IN (?)", userTeamIDs(r.db, userID))
+		})
+	if upcoming {
+		q.Where("m.status IN (?)", bun.In(models.UpcomingMatchStatuses)).
+			OrderExpr("m.match_time ASC")
+	} else {
+		q.OrderExpr("m.match_time DESC")
 	}
+	err := q.Scan(ctx)
+	return matches, err
+}
 
-	if rowsAffected == 0 {
-		return errors.New("match not found")
-	}
+// DeleteByMatchID maçı siler, ligin kart cezalarını ve puan durumunu aynı transaction içinde yeniden kurar
+func (r MatchRepository) DeleteByMatchID(ctx context.Context, id int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var leagueID uint
+		err := tx.NewDelete().
+			Model((*models.Match)(nil)).
+			Where("id = ?", id).
+			Returning("league_id").
+			Scan(ctx, &leagueID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMatchNotFound
+		}
+		if err != nil {
+			return err
+		}
 
-	return nil
+		// Maçın kartları silindiği için sarı kart birikimleri değişebilir
+		if err := syncLeagueSuspensions(ctx, tx, leagueID); err != nil {
+			return err
+		}
+		return rebuildLeagueStandings(ctx, tx, leagueID)
+	})
 }
 
+// UpdateMatch maçı günceller, skor veya durum değiştiyse puan durumu yeniden hesaplanır
 func (r MatchRepository) UpdateMatch(ctx context.Context, m models.Match) error {
-	_, err := r.db.NewUpdate().
-		Model(&m).
-		WherePK().
-		Exec(ctx)
-	return err
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var previousLeagueID uint
+		err := tx.NewSelect().
+			Model((*models.Match)(nil)).
+			Column("league_id").
+			Where("id = ?", m.ID).
+			For("UPDATE").
+			Scan(ctx, &previousLeagueID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkMatchClash(ctx, tx, m); err != nil {
+			return err
+		}
+
+		if _, err := tx.NewUpdate().Model(&m).WherePK().Exec(ctx); err != nil {
+			return err
+		}
+
+		// Maç başka bir lige taşındıysa eski ligin tablosu da düzeltilir
+		if previousLeagueID != m.LeagueID {
+			if err := rebuildLeagueStandings(ctx, tx, previousLeagueID); err != nil {
+				return err
+			}
+		}
+
+		return rebuildLeagueStandings(ctx, tx, m.LeagueID)
+	})
 }
 
+// CreateMatch maçı ekler (takımların o saatte başka oyunu olmamalı) ve ligin puan durumunu aynı transaction içinde yeniden kurar
 func (r MatchRepository) CreateMatch(ctx context.Context, match models.Match) error {
-	_, err := r.db.NewInsert().
-		Model(&match).
-		Exec(ctx)
-	return err
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := checkMatchClash(ctx, tx, match); err != nil {
+			return err
+		}
+
+		if _, err := tx.NewInsert().Model(&match).Exec(ctx); err != nil {
+			return err
+		}
+
+		return rebuildLeagueStandings(ctx, tx, match.LeagueID)
+	})
 }
 
-func (r MatchRepository) UpdateLeagueStandings(ctx context.Context, match models.Match) error {
-	var homeStanding, awayStanding models.LeagueTeam
+// ChangeMatchStatus maçı from durumundan m.Status durumuna geçirir. Maçın durumu bu arada değiştiyse veya geçiş
+// geçersizse ErrInvalidMatchTransition döner.
+func (r MatchRepository) ChangeMatchStatus(ctx context.Context, m models.Match, from models.MatchStatus, changedBy int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		current, err := lockMatch(ctx, tx, m.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status != from || !from.CanTransitionTo(m.Status) {
+			return ErrInvalidMatchTransition
+		}
 
-	// Ev sahibi takımın puan durumu
-	err := r.db.NewSelect().
-		Model(&homeStanding).
-		Where("league_id = ? AND team_id = ?", match.LeagueID, match.HomeTeamID).
-		Scan(ctx)
+		return applyMatchStatus(ctx, tx, *current, m, changedBy)
+	})
+}
 
-	if err != nil {
-		homeStanding = models.LeagueTeam{
-			LeagueID: match.LeagueID,
-			TeamID:   match.HomeTeamID,
-			Points:   0,
-			Rank:     0,
+// RecordMatchResult maçın skorunu kaydedip maçı COMPLETED yapar. Tamamlanmış maçın skoru düzeltilebilir;
+// aynı skor tekrar gönderilirse hiçbir şey değişmez ve puan durumu yeniden hesaplanmaz.
+func (r MatchRepository) RecordMatchResult(ctx context.Context, id int64, homeScore, awayScore int64, changedBy int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		current, err := lockMatch(ctx, tx, id)
+		if err != nil {
+			return err
 		}
-	}
 
-	// Deplasman takımının puan durumu
-	err = r.db.NewSelect().
-		Model(&awayStanding).
-		Where("league_id = ? AND team_id = ?", match.LeagueID, match.AwayTeamID).
-		Scan(ctx)
+		return recordMatchResult(ctx, tx, *current, homeScore, awayScore, changedBy)
+	})
+}
 
-	if err != nil {
-		awayStanding = models.LeagueTeam{
-			LeagueID: match.LeagueID,
-			TeamID:   match.AwayTeamID,
-			Points:   0,
-			Rank:     0,
+// SubmitMatchResult kaptanın bildirdiği skoru kaydeder. Rakip takım da aynı skoru bildirmişse maç tamamlanır ve
+// true döner. Tamamlanmış maça sadece kayıtlı skor bildirilebilir, bu durumda da true döner.
+func (r MatchRepository) SubmitMatchResult(ctx context.Context, submission models.MatchResultSubmission) (bool, error) {
+	var completed bool
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		current, err := lockMatch(ctx, tx, submission.MatchID)
+		if err != nil {
+			return err
+		}
+		if err := checkResultSubmission(*current, submission, time.Now()); err != nil {
+			return err
+		}
+
+		_, err = tx.NewInsert().
+			Model(&submission).
+			On("CONFLICT (match_id, team_id) DO UPDATE").
+			Set("submitted_by = EXCLUDED.submitted_by").
+			Set("home_score = EXCLUDED.home_score").
+			Set("away_score = EXCLUDED.away_score").
+			Set("submitted_at = current_timestamp").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if current.Status == models.MatchStatusCompleted {
+			completed = true
+			return nil
+		}
+
+		opponent := new(models.MatchResultSubmission)
+		err = tx.NewSelect().
+			Model(opponent).
+			Where("mrs.match_id = ?", current.ID).
+			Where("mrs.team_id <> ?", submission.TeamID).
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !opponent.SameScore(submission) {
+			return nil
 		}
+
+		completed = true
+		return recordMatchResult(ctx, tx, *current, submission.HomeScore, submission.AwayScore, submission.SubmittedBy)
+	})
+	return completed, err
+}
+
+func lockMatch(ctx context.Context, tx bun.Tx, id int64) (*models.Match, error) {
+	match := new(models.Match)
+	err := tx.NewSelect().
+		Model(match).
+		Where("m.id = ?", id).
+		For("UPDATE").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// checkResultSubmission kaptanın bildirimini kilitlenmiş maça göre doğrular. Tamamlanmış maça sadece kayıtlı skor
+// bildirilebilir, diğer maçlar oynanmış ve COMPLETED durumuna geçebilir olmalıdır.
+func checkResultSubmission(current models.Match, submission models.MatchResultSubmission, now time.Time) error {
+	if submission.TeamID != current.HomeTeamID && submission.TeamID != current.AwayTeamID {
+		return ErrNotMatchTeam
 	}
 
-	// Ev sahibi takımın puanlarını güncelle
-	if match.HomeScore > match.AwayScore {
-		homeStanding.Points += 3
-	} else if match.HomeScore == match.AwayScore {
-		homeStanding.Points += 1
+	switch {
+	case current.Status == models.MatchStatusCompleted:
+		if current.HomeScore != submission.HomeScore || current.AwayScore != submission.AwayScore {
+			return ErrMatchResultFinal
+		}
+	case !current.Status.CanTransitionTo(models.MatchStatusCompleted):
+		return ErrInvalidMatchTransition
+	case current.MatchTime.After(now):
+		return ErrMatchNotPlayed
 	}
+	return nil
+}
 
-	// Deplasman takımının puanlarını güncelle
-	if match.AwayScore > match.HomeScore {
-		awayStanding.Points += 3
-	} else if match.AwayScore == match.HomeScore {
-		awayStanding.Points += 1
+// resultChanged kilitlenmiş maça verilen skorun yazılması gerekip gerekmediğini söyler. Aynı skor tekrar geldiyse
+// false döner; tamamlanmış maçın skoru düzeltilebilir, diğer maçlar COMPLETED durumuna geçebilir olmalıdır.
+func resultChanged(current models.Match, homeScore, awayScore int64) (bool, error) {
+	if current.Status == models.MatchStatusCompleted {
+		return current.HomeScore != homeScore || current.AwayScore != awayScore, nil
+	}
+	if !current.Status.CanTransitionTo(models.MatchStatusCompleted) {
+		return false, ErrInvalidMatchTransition
 	}
+	return true, nil
+}
 
-	// Puan durumlarını kaydet
-	_, err = r.db.NewInsert().
-		Model(&homeStanding).
-		On("CONFLICT (league_id, team_id) DO UPDATE").
-		Set("points = EXCLUDED.points").
-		Exec(ctx)
-	if err != nil {
+// recordMatchResult kilitlenmiş maçı verilen skorla tamamlar, skor zaten kayıtlıysa hiçbir şey yapmaz
+func recordMatchResult(ctx context.Context, tx bun.Tx, current models.Match, homeScore, awayScore int64, changedBy int64) error {
+	changed, err := resultChanged(current, homeScore, awayScore)
+	if err != nil || !changed {
 		return err
 	}
 
-	_, err = r.db.NewInsert().
-		Model(&awayStanding).
-		On("CONFLICT (league_id, team_id) DO UPDATE").
-		Set("points = EXCLUDED.points").
+	m := current
+	m.Status = models.MatchStatusCompleted
+	m.HomeScore, m.AwayScore = homeScore, awayScore
+	m.ForfeitedBy = 0
+	return applyMatchStatus(ctx, tx, current, m, changedBy)
+}
+
+// applyMatchStatus maçın yeni halini kaydeder. Maça bağlı oyun yeni duruma göre kapatılır, puan durumu
+// etkilendiyse bir kez yeniden kurulur.
+func applyMatchStatus(ctx context.Context, tx bun.Tx, current, m models.Match, changedBy int64) error {
+	switch {
+	case m.Status == models.MatchStatusCompleted:
+		if err := checkMatchScore(ctx, tx, m); err != nil {
+			return err
+		}
+	case m.Status == models.MatchStatusScheduled && current.Status == models.MatchStatusAbandoned:
+		// Yarıda kalan maç baştan oynanır, eski oyunun olayları geçersizdir
+		if err := deleteMatchEvents(ctx, tx, m.ID); err != nil {
+			return err
+		}
+		if err := syncLeagueSuspensions(ctx, tx, current.LeagueID); err != nil {
+			return err
+		}
+	}
+
+	m.GameID = current.GameID
+	if m.GameID != 0 {
+		reason := "maç durumu " + string(m.Status) + " oldu"
+		switch m.Status {
+		case models.MatchStatusPostponed, models.MatchStatusScheduled:
+			// Ertelenen veya yeni bir zamana alınan maçın eski saha ayrımı boşa çıkarılır
+			if err := cancelGames(ctx, tx, []int64{int64(m.GameID)}, reason); err != nil {
+				return err
+			}
+			m.GameID = 0
+		case models.MatchStatusCompleted, models.MatchStatusAbandoned,
+			models.MatchStatusForfeit, models.MatchStatusWalkover:
+			if err := closeMatchGame(ctx, tx, int64(m.GameID), changedBy, reason); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := tx.NewUpdate().
+		Model(&m).
+		Column("status", "home_score", "away_score", "forfeited_by", "match_time", "game_id").
+		WherePK().
 		Exec(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Sıralamaları yeniden hesapla
-	return r.RecalculateRankings(ctx, match.LeagueID)
+	if !current.Status.CountsInStandings() && !m.Status.CountsInStandings() {
+		return nil
+	}
+	return rebuildLeagueStandings(ctx, tx, current.LeagueID)
 }
 
-func (r MatchRepository) RecalculateRankings(ctx context.Context, leagueID uint) error {
-	var standings []models.LeagueTeam
-
-	// Lig için tüm puan durumlarını getir
-	err := r.db.NewSelect().
-		Model(&standings).
-		Where("league_id = ?", leagueID).
-		Order("points DESC").
-		Scan(ctx)
+// closeMatchGame sonuçlanan maçın oyununu kapatır: başlamış ve kabul edilmiş oyun bitirilir, diğer açık oyunlar iptal edilir
+func closeMatchGame(ctx context.Context, tx bun.Tx, gameID int64, changedBy int64, reason string) error {
+	game, err := lockGame(ctx, tx, gameID)
 	if err != nil {
 		return err
 	}
 
-	// Sıralı puan durumlarına göre sıralamaları güncelle
-	for rank, standing := range standings {
-		standing.Rank = int64(rank + 1)
-		_, err := r.db.NewUpdate().
-			Model(&standing).
-			WherePK().
-			Exec(ctx)
-		if err != nil {
-			return err
-		}
+	change := models.GameStatusChange{ChangedBy: &changedBy, Reason: reason}
+	switch {
+	case game.Status == models.GameStatusAccepted && !game.StartTime.After(time.Now()):
+		change.ToStatus = models.GameStatusFinished
+	case game.Status == models.GameStatusPending || game.Status == models.GameStatusAccepted:
+		change.ToStatus = models.GameStatusCancelled
+	default:
+		return nil
 	}
-
-	return nil
+	return setGameStatus(ctx, tx, *game, change)
 }
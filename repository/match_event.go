@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/personal-project/pitch-league/models"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrMatchEventNotFound   = errors.New("maç olayı bulunamadı")
+	ErrMatchEventsClosed    = errors.New("olaylar sadece canlı, tamamlanmış, yarıda kalmış veya hükmen sonuçlanmış maçlara girilebilir")
+	ErrMatchHasNoGame       = errors.New("maçın oyunu olmadığı için oyuncuları bilinmiyor")
+	ErrEventParticipant     = errors.New("oyuncu maçın oyununda bu maçın takımlarından biri için oynamıyor")
+	ErrEventParticipantTeam = errors.New("asist yapan veya oyuna giren oyuncu aynı takımdan olmalı")
+	ErrMatchScoreMismatch   = errors.New("skor maçın gol olaylarıyla uyuşmuyor")
+)
+
+type IMatchEventRepository interface {
+	GetMatchEvents(ctx context.Context, matchID int64) ([]models.MatchEvent, error)
+	CreateMatchEvent(ctx context.Context, event *models.MatchEvent) error
+	UpdateMatchEvent(ctx context.Context, event *models.MatchEvent) error
+	DeleteMatchEvent(ctx context.Context, matchID, id int64) error
+}
+
+type MatchEventRepository struct {
+	db *bun.DB
+}
+
+func NewMatchEventRepository(db *bun.DB) IMatchEventRepository {
+	return &MatchEventRepository{db: db}
+}
+
+func (r MatchEventRepository) GetMatchEvents(ctx context.Context, matchID int64) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	err := r.db.NewSelect().
+		Model(&events).
+		Relation("Participant").
+		Relation("Participant.User").
+		Relation("AssistParticipant").
+		Relation("AssistParticipant.User").
+		Relation("SubstituteParticipant").
+		Relation("SubstituteParticipant.User").
+		Where("me.match_id = ?", matchID).
+		OrderExpr("me.minute ASC, me.id ASC").
+		Scan(ctx)
+	return events, err
+}
+
+// CreateMatchEvent olayı maçın oyunundaki katılımcıya bağlayıp kaydeder. Canlı ve tamamlanmış maçlarda gol
+// girilince skor gol olaylarından yeniden hesaplanır.
+func (r MatchEventRepository) CreateMatchEvent(ctx context.Context, event *models.MatchEvent) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		match, err := lockEventMatch(ctx, tx, event.MatchID)
+		if err != nil {
+			return err
+		}
+		if err := resolveEventParticipants(ctx, tx, *match, event); err != nil {
+			return err
+		}
+
+		if _, err := tx.NewInsert().Model(event).Exec(ctx); err != nil {
+			return err
+		}
+
+		return syncMatchEvents(ctx, tx, *match, event.Type.IsGoal())
+	})
+}
+
+// UpdateMatchEvent yanlış girilmiş olayı düzeltir, olayı ilk giren kullanıcı ve zaman değişmez
+func (r MatchEventRepository) UpdateMatchEvent(ctx context.Context, event *models.MatchEvent) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		match, err := lockEventMatch(ctx, tx, event.MatchID)
+		if err != nil {
+			return err
+		}
+		if err := resolveEventParticipants(ctx, tx, *match, event); err != nil {
+			return err
+		}
+
+		var previous models.MatchEventType
+		err = tx.NewSelect().
+			Model((*models.MatchEvent)(nil)).
+			Column("type").
+			Where("id = ?", event.ID).
+			Where("match_id = ?", event.MatchID).
+			Scan(ctx, &previous)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMatchEventNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model(event).
+			Column("type", "minute", "participant_id", "team_id", "user_id", "assist_participant_id", "substitute_participant_id").
+			Where("id = ?", event.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return syncMatchEvents(ctx, tx, *match, previous.IsGoal() || event.Type.IsGoal())
+	})
+}
+
+func (r MatchEventRepository) DeleteMatchEvent(ctx context.Context, matchID, id int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		match, err := lockEventMatch(ctx, tx, matchID)
+		if err != nil {
+			return err
+		}
+
+		var previous models.MatchEventType
+		err = tx.NewDelete().
+			Model((*models.MatchEvent)(nil)).
+			Where("id = ?", id).
+			Where("match_id = ?", matchID).
+			Returning("type").
+			Scan(ctx, &previous)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMatchEventNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		return syncMatchEvents(ctx, tx, *match, previous.IsGoal())
+	})
+}
+
+// lockEventMatch olay girilecek maçı kilitler, maçın durumu olay girmeye uygun değilse hata döner
+func lockEventMatch(ctx context.Context, tx bun.Tx, id int64) (*models.Match, error) {
+	match, err := lockMatch(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range models.MatchEventStatuses {
+		if match.Status == status {
+			if match.GameID == 0 {
+				return nil, ErrMatchHasNoGame
+			}
+			return match, nil
+		}
+	}
+	return nil, ErrMatchEventsClosed
+}
+
+// resolveEventParticipants olaydaki katılımcıların maçın oyununda olduğunu doğrular, oyuncu ve takım bilgisini katılımcıdan alır
+func resolveEventParticipants(ctx context.Context, tx bun.Tx, match models.Match, event *models.MatchEvent) error {
+	ids := []int64{event.ParticipantID}
+	for _, id := range []int64{event.AssistParticipantID, event.SubstituteParticipantID} {
+		if id != 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	var participants []models.GameParticipants
+	err := tx.NewSelect().
+		Model(&participants).
+		Where("gp.id IN (?)", bun.In(ids)).
+		Where("gp.game_id = ?", match.GameID).
+		Where("gp.team_id IN (?)", bun.In([]uint{match.HomeTeamID, match.AwayTeamID})).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+	if len(participants) != len(ids) {
+		return ErrEventParticipant
+	}
+
+	byID := make(map[int64]models.GameParticipants, len(participants))
+	for _, participant := range participants {
+		byID[participant.ID] = participant
+	}
+
+	player := byID[event.ParticipantID]
+	for _, id := range ids[1:] {
+		if byID[id].TeamID != player.TeamID {
+			return ErrEventParticipantTeam
+		}
+	}
+
+	event.TeamID = player.TeamID
+	event.UserID = player.UserID
+	return nil
+}
+
+// syncMatchEvents bir gol olayı değişince canlı ve tamamlanmış maçın skorunu gollerden yeniden hesaplar. Kartlar
+// cezaları ve FAIR_PLAY kriterini etkilediği için cezalar ve puan durumu her değişiklikte yeniden kurulur.
+func syncMatchEvents(ctx context.Context, tx bun.Tx, match models.Match, goalsChanged bool) error {
+	if goalsChanged && (match.Status == models.MatchStatusLive || match.Status == models.MatchStatusCompleted) {
+		home, away, _, err := matchEventScore(ctx, tx, match)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.Match)(nil)).
+			Set("home_score = ?", home).
+			Set("away_score = ?", away).
+			Where("id = ?", match.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := syncLeagueSuspensions(ctx, tx, match.LeagueID); err != nil {
+		return err
+	}
+	return rebuildLeagueStandings(ctx, tx, match.LeagueID)
+}
+
+// matchEventScore maçın skorunu gol olaylarından hesaplar, hasGoals maça hiç gol girilip girilmediğini söyler
+func matchEventScore(ctx context.Context, tx bun.Tx, match models.Match) (home, away int64, hasGoals bool, err error) {
+	var events []models.MatchEvent
+	err = tx.NewSelect().
+		Model(&events).
+		Column("type", "team_id").
+		Where("match_id = ?", match.ID).
+		Where("type IN (?)", bun.In([]models.MatchEventType{models.MatchEventGoal, models.MatchEventOwnGoal})).
+		Scan(ctx)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	for _, event := range events {
+		h, a := event.ScoresFor(match)
+		home += h
+		away += a
+	}
+	return home, away, len(events) > 0, nil
+}
+
+// checkMatchScore gol girilmiş maçlarda sonucun gollerle uyuştuğunu doğrular
+func checkMatchScore(ctx context.Context, tx bun.Tx, match models.Match) error {
+	home, away, hasGoals, err := matchEventScore(ctx, tx, match)
+	if err != nil {
+		return err
+	}
+	if hasGoals && (home != match.HomeScore || away != match.AwayScore) {
+		return ErrMatchScoreMismatch
+	}
+	return nil
+}
+
+// leagueFairPlay ligin maçlarındaki kartlardan takım başına disiplin puanını hesaplar
+func leagueFairPlay(ctx context.Context, tx bun.Tx, leagueID uint) (map[uint]int64, error) {
+	var events []models.MatchEvent
+	err := tx.NewSelect().
+		Model(&events).
+		ColumnExpr("me.type, me.team_id").
+		Join("JOIN matches AS m ON m.id = me.match_id").
+		Where("m.league_id = ?", leagueID).
+		Where("me.type IN (?)", bun.In([]models.MatchEventType{models.MatchEventYellowCard, models.MatchEventRedCard})).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fairPlay := make(map[uint]int64)
+	for _, event := range events {
+		fairPlay[event.TeamID] += event.Type.FairPlayPoints()
+	}
+	return fairPlay, nil
+}
+
+// deleteMatchEvents yeniden oynanacak maçın olaylarını siler
+func deleteMatchEvents(ctx context.Context, tx bun.Tx, matchID int64) error {
+	_, err := tx.NewDelete().
+		Model((*models.MatchEvent)(nil)).
+		Where("match_id = ?", matchID).
+		Exec(ctx)
+	return err
+}
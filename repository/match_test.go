@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/personal-project/pitch-league/models"
+)
+
+func playedMatch(status models.MatchStatus, homeScore, awayScore int64) models.Match {
+	return models.Match{
+		HomeTeamID: 1,
+		AwayTeamID: 2,
+		Status:     status,
+		HomeScore:  homeScore,
+		AwayScore:  awayScore,
+		MatchTime:  now.Add(-2 * time.Hour),
+	}
+}
+
+func TestResultChanged(t *testing.T) {
+	tests := []struct {
+		name                 string
+		current              models.Match
+		homeScore, awayScore int64
+		want                 bool
+		wantErr              error
+	}{
+		{name: "planlanmış maç tamamlanır", current: playedMatch(models.MatchStatusScheduled, 0, 0), homeScore: 2, awayScore: 1, want: true},
+		{name: "canlı maç tamamlanır", current: playedMatch(models.MatchStatusLive, 1, 1), homeScore: 2, awayScore: 1, want: true},
+		{name: "aynı skor tekrar kaydedilmez", current: playedMatch(models.MatchStatusCompleted, 2, 1), homeScore: 2, awayScore: 1},
+		// Skor 0-0 olsa da tamamlanmamış maç ilk kez kaydedilir
+		{name: "golsüz beraberlik ilk kez kaydedilir", current: playedMatch(models.MatchStatusLive, 0, 0), homeScore: 0, awayScore: 0, want: true},
+		{name: "tamamlanmış maçın skoru düzeltilir", current: playedMatch(models.MatchStatusCompleted, 2, 1), homeScore: 1, awayScore: 1, want: true},
+		{name: "hükmen biten maça skor yazılmaz", current: playedMatch(models.MatchStatusForfeit, 3, 0), homeScore: 3, awayScore: 0, wantErr: ErrInvalidMatchTransition},
+		{name: "ertelenen maça skor yazılmaz", current: playedMatch(models.MatchStatusPostponed, 0, 0), homeScore: 1, awayScore: 0, wantErr: ErrInvalidMatchTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resultChanged(tt.current, tt.homeScore, tt.awayScore)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, %v; want %v, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckResultSubmission(t *testing.T) {
+	submit := func(teamID uint, homeScore, awayScore int64) models.MatchResultSubmission {
+		return models.MatchResultSubmission{TeamID: teamID, HomeScore: homeScore, AwayScore: awayScore}
+	}
+	upcoming := playedMatch(models.MatchStatusScheduled, 0, 0)
+	upcoming.MatchTime = now.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		current    models.Match
+		submission models.MatchResultSubmission
+		want       error
+	}{
+		{name: "ev sahibi kaptanı bildirir", current: playedMatch(models.MatchStatusLive, 0, 0), submission: submit(1, 2, 1)},
+		{name: "deplasman kaptanı bildirir", current: playedMatch(models.MatchStatusScheduled, 0, 0), submission: submit(2, 2, 1)},
+		{name: "maçta olmayan takım", current: playedMatch(models.MatchStatusLive, 0, 0), submission: submit(3, 2, 1), want: ErrNotMatchTeam},
+		{name: "oynanmamış maç", current: upcoming, submission: submit(1, 2, 1), want: ErrMatchNotPlayed},
+		{name: "tamamlanmış maça aynı skor tekrar bildirilir", current: playedMatch(models.MatchStatusCompleted, 2, 1), submission: submit(2, 2, 1)},
+		{name: "tamamlanmış maçın skoru kaptanla değişmez", current: playedMatch(models.MatchStatusCompleted, 2, 1), submission: submit(2, 1, 1), want: ErrMatchResultFinal},
+		{name: "hükmen biten maça bildirim yapılmaz", current: playedMatch(models.MatchStatusForfeit, 3, 0), submission: submit(1, 3, 0), want: ErrInvalidMatchTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkResultSubmission(tt.current, tt.submission, now); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/personal-project/pitch-league/models"
+	"github.com/uptrace/bun"
+)
+
+var ErrPlayerNotFound = errors.New("oyuncu bulunamadı")
+
+type IPlayerRepository interface {
+	GetPlayer(ctx context.Context, userID int64) (*models.User, *models.PlayerProfile, error)
+	UpdatePlayerProfile(ctx context.Context, profile *models.PlayerProfile) error
+	GetPlayerCareerStats(ctx context.Context, userID int64) (models.PlayerCareerStats, error)
+}
+
+type PlayerRepository struct {
+	db *bun.DB
+}
+
+func NewPlayerRepository(db *bun.DB) IPlayerRepository {
+	return &PlayerRepository{db: db}
+}
+
+// GetPlayer kullanıcıyı ve oyuncu profilini getirir, profilini doldurmamış kullanıcılar için boş profil döner
+func (r PlayerRepository) GetPlayer(ctx context.Context, userID int64) (*models.User, *models.PlayerProfile, error) {
+	user := new(models.User)
+	err := r.db.NewSelect().
+		Model(user).
+		Where("?TableAlias.id = ?", userID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrPlayerNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile := &models.PlayerProfile{UserID: userID}
+	err = r.db.NewSelect().
+		Model(profile).
+		WherePK().
+		Scan(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, err
+	}
+	return user, profile, nil
+}
+
+// UpdatePlayerProfile profili oluşturur veya günceller
+func (r PlayerRepository) UpdatePlayerProfile(ctx context.Context, profile *models.PlayerProfile) error {
+	profile.UpdatedAt = time.Now()
+	_, err := r.db.NewInsert().
+		Model(profile).
+		On("CONFLICT (user_id) DO UPDATE").
+		Set("position = EXCLUDED.position").
+		Set("strong_foot = EXCLUDED.strong_foot").
+		Set("date_of_birth = EXCLUDED.date_of_birth").
+		Set("avatar_url = EXCLUDED.avatar_url").
+		Set("bio = EXCLUDED.bio").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	return err
+}
+
+// GetPlayerCareerStats oyuncunun biten oyunlardaki, maç olaylarındaki ve lig maçlarına katılımındaki istatistiklerini toplar
+func (r PlayerRepository) GetPlayerCareerStats(ctx context.Context, userID int64) (models.PlayerCareerStats, error) {
+	stats := models.PlayerCareerStats{Teams: []models.PlayerTeamStats{}}
+
+	// Lig maçının oyununda sonuç maçtan, diğer oyunlarda takımların skorlarından okunur.
+	// Hükmen biten maçları hükmen kaybetmeyen takım kazanır, skorlu oyunları en çok gol atan takım kazanır.
+	err := r.db.NewSelect().
+		TableExpr("game_participants AS gp").
+		Join("JOIN games AS g ON g.id = gp.game_id").
+		Join("LEFT JOIN matches AS m ON m.game_id = gp.game_id AND m.status IN (?)", bun.In(models.StandingsMatchStatuses)).
+		ColumnExpr("COUNT(*) AS games_played").
+		ColumnExpr(`COUNT(*) FILTER (WHERE CASE
+			WHEN m.id IS NULL THEN
+				(SELECT gs.score FROM game_scores AS gs WHERE gs.game_id = gp.game_id AND gs.team_id = gp.team_id) >
+				(SELECT MAX(o.score) FROM game_scores AS o WHERE o.game_id = gp.game_id AND o.team_id <> gp.team_id)
+			WHEN m.forfeited_by IS NOT NULL THEN m.forfeited_by <> gp.team_id
+			WHEN gp.team_id = m.home_team_id THEN m.home_score > m.away_score
+			ELSE m.away_score > m.home_score
+		END) AS games_won`).
+		Where("gp.user_id = ?", userID).
+		Where("g.status = ?", models.GameStatusFinished).
+		Scan(ctx, &stats.GamesPlayed, &stats.GamesWon)
+	if err != nil {
+		return stats, err
+	}
+
+	err = r.db.NewSelect().
+		TableExpr("game_participants AS gp").
+		Join("JOIN games AS g ON g.id = gp.game_id").
+		Join("JOIN teams AS t ON t.id = gp.team_id").
+		ColumnExpr("gp.team_id, t.name AS team_name, COUNT(*) AS games").
+		Where("gp.user_id = ?", userID).
+		Where("g.status = ?", models.GameStatusFinished).
+		GroupExpr("gp.team_id, t.name").
+		OrderExpr("games DESC, t.name ASC").
+		Scan(ctx, &stats.Teams)
+	if err != nil {
+		return stats, err
+	}
+
+	// Goller ve asistler gol krallığı gibi tamamlanmış maçlardan, kartlar kart tablosu gibi oynanan tüm maçlardan sayılır
+	err = r.db.NewSelect().
+		TableExpr("match_events AS me").
+		Join("JOIN matches AS m ON m.id = me.match_id").
+		ColumnExpr("COUNT(*) FILTER (WHERE me.type = ? AND m.status = ?) AS goals", models.MatchEventGoal, models.MatchStatusCompleted).
+		ColumnExpr("COUNT(*) FILTER (WHERE me.type = ? AND m.status = ?) AS own_goals", models.MatchEventOwnGoal, models.MatchStatusCompleted).
+		ColumnExpr("COUNT(*) FILTER (WHERE me.type = ?) AS yellow_cards", models.MatchEventYellowCard).
+		ColumnExpr("COUNT(*) FILTER (WHERE me.type = ?) AS red_cards", models.MatchEventRedCard).
+		Where("me.user_id = ?", userID).
+		Where("m.status IN (?)", bun.In(models.MatchEventStatuses)).
+		Scan(ctx, &stats.Goals, &stats.OwnGoals, &stats.YellowCards, &stats.RedCards)
+	if err != nil {
+		return stats, err
+	}
+
+	err = r.db.NewSelect().
+		TableExpr("match_events AS me").
+		Join("JOIN matches AS m ON m.id = me.match_id").
+		Join("JOIN game_participants AS gp ON gp.id = me.assist_participant_id").
+		ColumnExpr("COUNT(*)").
+		Where("gp.user_id = ?", userID).
+		Where("me.type = ?", models.MatchEventGoal).
+		Where("m.status = ?", models.MatchStatusCompleted).
+		Scan(ctx, &stats.Assists)
+	if err != nil {
+		return stats, err
+	}
+
+	// Katılım, oyuncu takımın kadrosundayken oynanan ve oyunu olan tamamlanmış lig maçlarından hesaplanır.
+	// Hükmen biten maçlar oynanmadığı için sayılmaz.
+	err = r.db.NewSelect().
+		TableExpr("matches AS m").
+		Join(`JOIN team_members AS tm ON tm.team_id IN (m.home_team_id, m.away_team_id) AND tm.user_id = ?
+			AND tm.joined_at <= m.match_time AND (tm.left_at IS NULL OR tm.left_at > m.match_time)`, userID).
+		ColumnExpr("COUNT(*) AS eligible").
+		ColumnExpr(`COUNT(*) FILTER (WHERE EXISTS (
+			SELECT 1 FROM game_participants AS gp
+			WHERE gp.game_id = m.game_id AND gp.team_id = tm.team_id AND gp.user_id = tm.user_id
+		)) AS attended`).
+		Where("m.status = ?", models.MatchStatusCompleted).
+		Where("m.game_id IS NOT NULL").
+		Scan(ctx, &stats.EligibleMatches, &stats.AttendedMatches)
+	if err != nil {
+		return stats, err
+	}
+	if stats.EligibleMatches > 0 {
+		rate := math.Round(float64(stats.AttendedMatches)*1000/float64(stats.EligibleMatches)) / 10
+		stats.AttendanceRate = &rate
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,564 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/personal-project/pitch-league/booking"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrReservationNotFound      = errors.New("rezervasyon bulunamadı")
+	ErrReservationExpired       = errors.New("rezervasyonun süresi dolmuş, saha serbest bırakıldı")
+	ErrReservationNotHeld       = errors.New("rezervasyon onay beklemiyor")
+	ErrReservationNotConfirmed  = errors.New("rezervasyon henüz onaylanmadı")
+	ErrReservationClosed        = errors.New("iptal edilmiş veya süresi dolmuş rezervasyon değiştirilemez")
+	ErrReservationStarted       = errors.New("başlamış rezervasyon iptal edilemez")
+	ErrReservationSharesPaid    = errors.New("payını ödemiş oyuncular varken ücret yeniden bölünemez")
+	ErrReservationShareNotFound = errors.New("oyuncunun bu rezervasyonda bekleyen payı yok")
+)
+
+// ChargeFunc onaylanan rezervasyonun ücretini çeker ve ödeme id'sini döner. Ödeme sağlayıcısı beklenirken satır
+// kilitli kalmasın diye transaction dışında çağrılır, hata dönerse rezervasyon değişmeden kalır.
+type ChargeFunc func(r models.Reservation) (paymentID string, err error)
+
+// VoidFunc ücreti çekildikten sonra onaylanamayan (bu arada süresi dolan veya iptal edilen) rezervasyonun ödemesini geri verir
+type VoidFunc func(r models.Reservation, paymentID string) error
+
+// RefundAmountFunc iptal edilen onaylı rezervasyonda iade edilecek tutarı belirler
+type RefundAmountFunc func(r models.Reservation) int64
+
+// RefundFunc iptal edilmiş rezervasyonun iadesini yapar ve iade id'sini döner. Ödeme sağlayıcısı beklenirken satır
+// kilitli kalmasın diye iptal kaydedildikten sonra transaction dışında çağrılır.
+type RefundFunc func(r models.Reservation, amount int64) (refundID string, err error)
+
+type IReservationRepository interface {
+	CreateReservation(ctx context.Context, reservation *models.Reservation, game models.Game) error
+	GetReservation(ctx context.Context, id int64) (*models.Reservation, error)
+	GetReservationsByUser(ctx context.Context, userID int64) ([]models.Reservation, error)
+	ConfirmReservation(ctx context.Context, id int64, charge ChargeFunc, void VoidFunc) error
+	CancelReservation(ctx context.Context, id int64, refundAmount RefundAmountFunc, refund RefundFunc) error
+	SplitReservation(ctx context.Context, id int64, userIDs []int64) error
+	MarkReservationSharePaid(ctx context.Context, id, userID int64) error
+	ExpireReservationHolds(ctx context.Context) (int, error)
+}
+
+type ReservationRepository struct {
+	db *bun.DB
+}
+
+func NewReservationRepository(db *bun.DB) IReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+// CreateReservation sahayı tutan oyunu ve rezervasyonu aynı transaction içinde ekler. Ücretin tamamı
+// başlangıçta rezervasyonu yapanın payıdır, SplitReservation ile oyunculara bölünebilir.
+func (r ReservationRepository) CreateReservation(ctx context.Context, reservation *models.Reservation, game models.Game) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := checkFieldSlot(ctx, tx, game.FieldID, game.StartTime, game.EndTime, 0); err != nil {
+			return err
+		}
+
+		if _, err := tx.NewInsert().Model(&game).Exec(ctx); err != nil {
+			return bookingError(err)
+		}
+
+		reservation.GameID = game.ID
+		reservation.Status = models.ReservationStatusHeld
+		if _, err := tx.NewInsert().Model(reservation).Exec(ctx); err != nil {
+			return err
+		}
+
+		_, err := tx.NewInsert().
+			Model(&models.ReservationShare{
+				ReservationID: reservation.ID,
+				UserID:        reservation.UserID,
+				Amount:        reservation.TotalPrice,
+				Status:        models.ReservationShareStatusPending,
+			}).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (r ReservationRepository) GetReservation(ctx context.Context, id int64) (*models.Reservation, error) {
+	reservation := new(models.Reservation)
+	err := r.db.NewSelect().
+		Model(reservation).
+		Relation("Field").
+		Relation("Shares", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.OrderExpr("rs.id ASC")
+		}).
+		Where("r.id = ?", id).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReservationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// GetReservationsByUser kullanıcının yaptığı ve payı olduğu rezervasyonları getirir
+func (r ReservationRepository) GetReservationsByUser(ctx context.Context, userID int64) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.NewSelect().
+		Model(&reservations).
+		Relation("Field").
+		Relation("Shares", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.OrderExpr("rs.id ASC")
+		}).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("r.user_id = ?", userID).
+				WhereOr("EXISTS (SELECT 1 FROM reservation_shares s WHERE s.reservation_id = r.id AND s.user_id = ?)", userID)
+		}).
+		OrderExpr("r.start_time DESC").
+		Scan(ctx)
+	return reservations, err
+}
+
+// ConfirmReservation tutulan rezervasyonun ücretini çekip onaylar, oyun ACCEPTED olur. Ücretin tamamını
+// rezervasyonu yapan öder, bu yüzden onun payı ödenmiş sayılır. Ücret rezervasyon kilitlenmeden önce çekilir;
+// çekimden sonra rezervasyonun süresi dolmuş veya iptal edilmişse ödeme void ile geri verilir. Veritabanı hatasında
+// ödeme geri verilmez, tekrar denendiğinde aynı referansla çekim yeniden yapılmaz.
+func (r ReservationRepository) ConfirmReservation(ctx context.Context, id int64, charge ChargeFunc, void VoidFunc) error {
+	reservation, err := r.GetReservation(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkConfirmable(*reservation, time.Now()); err != nil {
+		return err
+	}
+
+	paymentID, err := charge(*reservation)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		locked, err := lockReservation(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		// Aynı rezervasyon eş zamanlı iki istekle onaylandıysa ikisi de aynı ödemeyi almıştır
+		if locked.Status == models.ReservationStatusConfirmed && locked.PaymentID == paymentID {
+			return nil
+		}
+		now := time.Now()
+		if err := checkConfirmable(*locked, now); err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.Reservation)(nil)).
+			Set("status = ?", models.ReservationStatusConfirmed).
+			Set("payment_id = ?", paymentID).
+			Set("confirmed_at = ?", now).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.ReservationShare)(nil)).
+			Set("status = ?", models.ReservationShareStatusPaid).
+			Set("paid_at = ?", now).
+			Where("reservation_id = ?", id).
+			Where("user_id = ?", locked.UserID).
+			Where("status = ?", models.ReservationShareStatusPending).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return transitionGame(ctx, tx, locked.GameID, models.GameStatusChange{
+			ToStatus:  models.GameStatusAccepted,
+			ChangedBy: &locked.UserID,
+			Reason:    "rezervasyon onaylandı",
+		})
+	})
+
+	if errors.Is(err, ErrReservationExpired) || errors.Is(err, ErrReservationNotHeld) {
+		if voidErr := void(*reservation, paymentID); voidErr != nil {
+			return errors.Join(err, voidErr)
+		}
+	}
+	return err
+}
+
+// checkConfirmable rezervasyonun hâlâ tutulduğunu ve tutma süresinin dolmadığını doğrular
+func checkConfirmable(reservation models.Reservation, now time.Time) error {
+	switch reservation.Status {
+	case models.ReservationStatusHeld:
+	case models.ReservationStatusExpired:
+		return ErrReservationExpired
+	default:
+		return ErrReservationNotHeld
+	}
+	if !reservation.HoldExpiresAt.After(now) {
+		return ErrReservationExpired
+	}
+	return nil
+}
+
+// CancelReservation rezervasyonu iptal edip sahayı serbest bırakır. Onaylanmış rezervasyonlarda refundAmount iade
+// tutarını belirler; iade, ödenmiş paylara tutarları oranında dağıtılır, bekleyen paylar iptal edilir. İptal önce
+// kaydedilir, iade transaction dışında yapılır ve iade id'si sonra yazılır. İade başarısız olursa rezervasyon iptal
+// kalır, aynı rezervasyon tekrar iptal edildiğinde yalnızca bekleyen iade aynı referansla yeniden denenir.
+func (r ReservationRepository) CancelReservation(ctx context.Context, id int64, refundAmount RefundAmountFunc, refund RefundFunc) error {
+	var cancelled models.Reservation
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		reservation, err := lockReservation(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		cancelled = *reservation
+
+		now := time.Now()
+		retryRefund, err := checkCancellable(*reservation, now)
+		if err != nil || retryRefund {
+			return err
+		}
+
+		var amount int64
+		if reservation.Status == models.ReservationStatusConfirmed {
+			amount = refundAmount(*reservation)
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.Reservation)(nil)).
+			Set("status = ?", models.ReservationStatusCancelled).
+			Set("cancelled_at = ?", now).
+			Set("refund_amount = ?", amount).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		cancelled.Status = models.ReservationStatusCancelled
+		cancelled.CancelledAt = &now
+		cancelled.RefundAmount = amount
+
+		if err := refundReservationShares(ctx, tx, *reservation, amount); err != nil {
+			return err
+		}
+
+		return transitionGame(ctx, tx, reservation.GameID, models.GameStatusChange{
+			ToStatus:  models.GameStatusCancelled,
+			ChangedBy: &reservation.UserID,
+			Reason:    "rezervasyon iptal edildi",
+		})
+	})
+	if err != nil || !cancelled.RefundPending() {
+		return err
+	}
+
+	refundID, err := refund(cancelled, cancelled.RefundAmount)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.NewUpdate().
+		Model((*models.Reservation)(nil)).
+		Set("refund_id = ?", refundID).
+		Where("id = ?", id).
+		Where("refund_id IS NULL").
+		Exec(ctx)
+	return err
+}
+
+// checkCancellable rezervasyonun iptal edilebileceğini doğrular. İadesi yarım kalmış bir iptalde retryRefund
+// true döner, rezervasyon değiştirilmeden yalnızca iade yeniden denenir.
+func checkCancellable(reservation models.Reservation, now time.Time) (retryRefund bool, err error) {
+	if reservation.RefundPending() {
+		return true, nil
+	}
+	if reservation.Status != models.ReservationStatusHeld && reservation.Status != models.ReservationStatusConfirmed {
+		return false, ErrReservationClosed
+	}
+	if !reservation.StartTime.After(now) {
+		return false, ErrReservationStarted
+	}
+	return false, nil
+}
+
+// SplitReservation ücreti rezervasyonu yapan ve verilen oyuncular arasında eşit böler. userIDs boşsa
+// oyuna kayıtlı oyuncular kullanılır. Başka bir oyuncu payını ödediyse bölüşüm değiştirilemez.
+func (r ReservationRepository) SplitReservation(ctx context.Context, id int64, userIDs []int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		reservation, err := lockReservation(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if reservation.Status != models.ReservationStatusHeld && reservation.Status != models.ReservationStatusConfirmed {
+			return ErrReservationClosed
+		}
+
+		paid, err := tx.NewSelect().
+			Model((*models.ReservationShare)(nil)).
+			Where("reservation_id = ?", id).
+			Where("user_id <> ?", reservation.UserID).
+			Where("status = ?", models.ReservationShareStatusPaid).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if paid {
+			return ErrReservationSharesPaid
+		}
+
+		if len(userIDs) == 0 {
+			err := tx.NewSelect().
+				Model((*models.GameParticipants)(nil)).
+				ColumnExpr("DISTINCT user_id").
+				Where("game_id = ?", reservation.GameID).
+				Scan(ctx, &userIDs)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Rezervasyonu yapan her zaman ilk paydır, aynı oyuncu iki kez sayılmaz
+		payers := []int64{reservation.UserID}
+		seen := map[int64]bool{reservation.UserID: true}
+		for _, userID := range userIDs {
+			if !seen[userID] {
+				seen[userID] = true
+				payers = append(payers, userID)
+			}
+		}
+
+		count, err := tx.NewSelect().
+			Model((*models.User)(nil)).
+			Where("id IN (?)", bun.In(payers)).
+			Count(ctx)
+		if err != nil {
+			return err
+		}
+		if count != len(payers) {
+			return errors.New("geçersiz userID: böyle bir kullanıcı mevcut değil")
+		}
+
+		_, err = tx.NewDelete().
+			Model((*models.ReservationShare)(nil)).
+			Where("reservation_id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		amounts := booking.Split(reservation.TotalPrice, len(payers))
+		shares := make([]models.ReservationShare, len(payers))
+		for i, userID := range payers {
+			shares[i] = models.ReservationShare{
+				ReservationID: id,
+				UserID:        userID,
+				Amount:        amounts[i],
+				Status:        models.ReservationShareStatusPending,
+			}
+		}
+		// Onaylanmış rezervasyonda rezervasyonu yapan ücreti zaten ödemiştir
+		if reservation.Status == models.ReservationStatusConfirmed {
+			now := time.Now()
+			shares[0].Status = models.ReservationShareStatusPaid
+			shares[0].PaidAt = &now
+		}
+
+		_, err = tx.NewInsert().
+			Model(&shares).
+			Exec(ctx)
+		return err
+	})
+}
+
+// MarkReservationSharePaid oyuncunun payını rezervasyonu yapana ödediğini kaydeder. Ücret onayda çekildiği
+// için paylar ancak onaylanmış rezervasyonda ödenebilir.
+func (r ReservationRepository) MarkReservationSharePaid(ctx context.Context, id, userID int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		reservation, err := lockReservation(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		switch reservation.Status {
+		case models.ReservationStatusConfirmed:
+		case models.ReservationStatusHeld:
+			return ErrReservationNotConfirmed
+		default:
+			return ErrReservationClosed
+		}
+
+		result, err := tx.NewUpdate().
+			Model((*models.ReservationShare)(nil)).
+			Set("status = ?", models.ReservationShareStatusPaid).
+			Set("paid_at = ?", time.Now()).
+			Where("reservation_id = ?", id).
+			Where("user_id = ?", userID).
+			Where("status = ?", models.ReservationShareStatusPending).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrReservationShareNotFound
+		}
+
+		return nil
+	})
+}
+
+// ExpireReservationHolds süresi içinde onaylanmayan tutmaları EXPIRED yapıp oyunlarını iptal eder
+func (r ReservationRepository) ExpireReservationHolds(ctx context.Context) (int, error) {
+	var expired int
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		expired, err = expireReservationHolds(ctx, tx, 0)
+		return err
+	})
+	return expired, err
+}
+
+// StartReservationHoldSweeper süresi dolan tutmaları belirli aralıklarla serbest bırakır, ctx kapanınca durur.
+// Sahaya yeni rezervasyon yapılırken o sahanın tutmaları zaten kontrol edilir, bu yüzden aralık kısa olmak zorunda değildir.
+func StartReservationHoldSweeper(ctx context.Context, r IReservationRepository, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := r.ExpireReservationHolds(ctx); err != nil {
+					log.Printf("reservation hold sweep failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// expireReservationHolds süresi dolan tutmaları serbest bırakır, fieldID 0 ise tüm sahalara bakar
+func expireReservationHolds(ctx context.Context, tx bun.Tx, fieldID uint) (int, error) {
+	var reservations []models.Reservation
+	q := tx.NewUpdate().
+		Model((*models.Reservation)(nil)).
+		Set("status = ?", models.ReservationStatusExpired).
+		Where("status = ?", models.ReservationStatusHeld).
+		Where("hold_expires_at <= ?", time.Now()).
+		Returning("id, game_id")
+	if fieldID != 0 {
+		q = q.Where("field_id = ?", fieldID)
+	}
+	if _, err := q.Exec(ctx, &reservations); err != nil {
+		return 0, err
+	}
+	if len(reservations) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, len(reservations))
+	gameIDs := make([]int64, len(reservations))
+	for i, reservation := range reservations {
+		ids[i] = reservation.ID
+		gameIDs[i] = reservation.GameID
+	}
+
+	_, err := tx.NewUpdate().
+		Model((*models.ReservationShare)(nil)).
+		Set("status = ?", models.ReservationShareStatusCancelled).
+		Where("reservation_id IN (?)", bun.In(ids)).
+		Where("status = ?", models.ReservationShareStatusPending).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := cancelGames(ctx, tx, gameIDs, "rezervasyon süresi doldu"); err != nil {
+		return 0, err
+	}
+
+	return len(reservations), nil
+}
+
+// refundReservationShares iade tutarını ödenmiş paylara tutarları oranında dağıtır, kalan kuruşlar
+// rezervasyonu yapanın payına eklenir. Bekleyen paylar iptal edilir.
+func refundReservationShares(ctx context.Context, tx bun.Tx, reservation models.Reservation, amount int64) error {
+	var shares []models.ReservationShare
+	err := tx.NewSelect().
+		Model(&shares).
+		Where("rs.reservation_id = ?", reservation.ID).
+		OrderExpr("rs.id ASC").
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	var paid, distributed int64
+	for _, share := range shares {
+		if share.Status == models.ReservationShareStatusPaid {
+			paid += share.Amount
+		}
+	}
+
+	booker := -1
+	for i := range shares {
+		if shares[i].Status != models.ReservationShareStatusPaid {
+			shares[i].Status = models.ReservationShareStatusCancelled
+			continue
+		}
+		if paid > 0 {
+			shares[i].RefundAmount = amount * shares[i].Amount / paid
+			distributed += shares[i].RefundAmount
+		}
+		if shares[i].UserID == reservation.UserID {
+			booker = i
+		}
+	}
+	if booker >= 0 {
+		shares[booker].RefundAmount += amount - distributed
+	}
+
+	for _, share := range shares {
+		_, err := tx.NewUpdate().
+			Model((*models.ReservationShare)(nil)).
+			Set("status = ?", share.Status).
+			Set("refund_amount = ?", share.RefundAmount).
+			Where("id = ?", share.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func lockReservation(ctx context.Context, tx bun.Tx, id int64) (*models.Reservation, error) {
+	reservation := new(models.Reservation)
+	err := tx.NewSelect().
+		Model(reservation).
+		Where("r.id = ?", id).
+		For("UPDATE").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReservationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
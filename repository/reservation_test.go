@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/personal-project/pitch-league/models"
+)
+
+var now = time.Date(2026, time.May, 2, 12, 0, 0, 0, time.UTC)
+
+func TestCheckConfirmable(t *testing.T) {
+	tests := []struct {
+		name        string
+		reservation models.Reservation
+		want        error
+	}{
+		{
+			name:        "tutma süresi dolmamış",
+			reservation: models.Reservation{Status: models.ReservationStatusHeld, HoldExpiresAt: now.Add(time.Minute)},
+		},
+		{
+			name:        "tutma süresi dolmuş ama süpürücü henüz çalışmamış",
+			reservation: models.Reservation{Status: models.ReservationStatusHeld, HoldExpiresAt: now},
+			want:        ErrReservationExpired,
+		},
+		{
+			name:        "süpürücü tarafından serbest bırakılmış",
+			reservation: models.Reservation{Status: models.ReservationStatusExpired, HoldExpiresAt: now.Add(-time.Minute)},
+			want:        ErrReservationExpired,
+		},
+		{
+			name:        "ödeme beklenirken iptal edilmiş",
+			reservation: models.Reservation{Status: models.ReservationStatusCancelled, HoldExpiresAt: now.Add(time.Minute)},
+			want:        ErrReservationNotHeld,
+		},
+		{
+			name:        "zaten onaylanmış",
+			reservation: models.Reservation{Status: models.ReservationStatusConfirmed, HoldExpiresAt: now.Add(time.Minute)},
+			want:        ErrReservationNotHeld,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkConfirmable(tt.reservation, now); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCancellable(t *testing.T) {
+	later := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		reservation models.Reservation
+		wantRetry   bool
+		want        error
+	}{
+		{
+			name:        "tutulan rezervasyon",
+			reservation: models.Reservation{Status: models.ReservationStatusHeld, StartTime: later},
+		},
+		{
+			name:        "onaylanmış rezervasyon",
+			reservation: models.Reservation{Status: models.ReservationStatusConfirmed, StartTime: later, PaymentID: "ch_1"},
+		},
+		{
+			name:        "başlamış rezervasyon",
+			reservation: models.Reservation{Status: models.ReservationStatusConfirmed, StartTime: now},
+			want:        ErrReservationStarted,
+		},
+		{
+			name:        "süresi dolmuş tutma",
+			reservation: models.Reservation{Status: models.ReservationStatusExpired, StartTime: later},
+			want:        ErrReservationClosed,
+		},
+		{
+			name:        "iadesi yapılmış iptal tekrar iptal edilemez",
+			reservation: models.Reservation{Status: models.ReservationStatusCancelled, StartTime: later, RefundAmount: 500, RefundID: "re_1"},
+			want:        ErrReservationClosed,
+		},
+		{
+			name:        "iadesiz iptal tekrar iptal edilemez",
+			reservation: models.Reservation{Status: models.ReservationStatusCancelled, StartTime: later},
+			want:        ErrReservationClosed,
+		},
+		{
+			name:        "iadesi yarım kalan iptalde yalnızca iade yeniden denenir",
+			reservation: models.Reservation{Status: models.ReservationStatusCancelled, StartTime: later, RefundAmount: 500},
+			wantRetry:   true,
+		},
+		{
+			name:        "iade başlangıçtan sonra da yeniden denenir",
+			reservation: models.Reservation{Status: models.ReservationStatusCancelled, StartTime: now.Add(-time.Hour), RefundAmount: 500},
+			wantRetry:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, err := checkCancellable(tt.reservation, now)
+			if retry != tt.wantRetry || !errors.Is(err, tt.want) {
+				t.Errorf("got retry %v, err %v; want retry %v, err %v", retry, err, tt.wantRetry, tt.want)
+			}
+		})
+	}
+}
+
+// TestReservationFlow bir rezervasyonu tutma, onay, iptal ve yarım kalan iadenin yeniden denenmesi adımlarından
+// ConfirmReservation ve CancelReservation'ın yaptığı sırayla geçirir
+func TestReservationFlow(t *testing.T) {
+	reservation := models.Reservation{
+		Status:        models.ReservationStatusHeld,
+		HoldExpiresAt: now.Add(10 * time.Minute),
+		StartTime:     now.Add(48 * time.Hour),
+	}
+
+	if err := checkConfirmable(reservation, now); err != nil {
+		t.Fatalf("confirm held reservation: %v", err)
+	}
+	reservation.Status = models.ReservationStatusConfirmed
+	reservation.PaymentID = "ch_1"
+
+	// İkinci onay isteği ödemeyi geri vermek için ErrReservationNotHeld alır
+	if err := checkConfirmable(reservation, now); !errors.Is(err, ErrReservationNotHeld) {
+		t.Fatalf("confirm twice: got %v, want %v", err, ErrReservationNotHeld)
+	}
+
+	retry, err := checkCancellable(reservation, now)
+	if err != nil || retry {
+		t.Fatalf("cancel confirmed reservation: got retry %v, err %v", retry, err)
+	}
+	reservation.Status = models.ReservationStatusCancelled
+	reservation.RefundAmount = 1000
+	if !reservation.RefundPending() {
+		t.Fatal("refund is not pending after cancel")
+	}
+
+	// İade başarısız oldu, tekrar iptal isteği kick-off geçmiş olsa da yalnızca iadeyi dener
+	later := reservation.StartTime.Add(time.Hour)
+	if retry, err := checkCancellable(reservation, later); err != nil || !retry {
+		t.Fatalf("retry refund: got retry %v, err %v", retry, err)
+	}
+	reservation.RefundID = "re_1"
+	if reservation.RefundPending() {
+		t.Fatal("refund is still pending after refund id is saved")
+	}
+	if _, err := checkCancellable(reservation, now); !errors.Is(err, ErrReservationClosed) {
+		t.Fatalf("cancel after refund: got %v, want %v", err, ErrReservationClosed)
+	}
+}
+
+func TestReservationHoldExpiresBeforeConfirm(t *testing.T) {
+	reservation := models.Reservation{
+		Status:        models.ReservationStatusHeld,
+		HoldExpiresAt: now.Add(10 * time.Minute),
+		StartTime:     now.Add(48 * time.Hour),
+	}
+
+	// Ödeme sürerken tutma süresi doldu, kilitli satırda onay reddedilir ve çekilen ücret geri verilir
+	if err := checkConfirmable(reservation, reservation.HoldExpiresAt); !errors.Is(err, ErrReservationExpired) {
+		t.Fatalf("got %v, want %v", err, ErrReservationExpired)
+	}
+
+	// Süpürücü serbest bıraktıktan sonra rezervasyon ne onaylanır ne iptal edilir
+	reservation.Status = models.ReservationStatusExpired
+	if err := checkConfirmable(reservation, now); !errors.Is(err, ErrReservationExpired) {
+		t.Errorf("confirm expired: got %v, want %v", err, ErrReservationExpired)
+	}
+	if _, err := checkCancellable(reservation, now); !errors.Is(err, ErrReservationClosed) {
+		t.Errorf("cancel expired: got %v, want %v", err, ErrReservationClosed)
+	}
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/personal-project/pitch-league/models"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+var (
+	ErrFieldDoubleBooked     = errors.New("saha bu saat aralığında başka bir oyun için ayrılmış")
+	ErrFieldUnavailable      = errors.New("saha şu anda rezervasyona kapalı")
+	ErrTeamScheduleClash     = errors.New("takımın bu saat aralığında başka bir oyunu var")
+	ErrPlayerScheduleClash   = errors.New("oyuncunun bu saat aralığında başka bir oyunu var")
+	ErrFieldClosed           = errors.New("saha bu saatlerde açık değil")
+	ErrFieldBlackout         = errors.New("saha bu tarihlerde kapalı")
+	ErrBlackoutHasGames      = errors.New("bu dönemde sahada oyunlar var, önce oyunların iptal edilmesi gerekir")
+	ErrFieldBlackoutNotFound = errors.New("kapalı dönem bulunamadı")
+)
+
+// freeingGameStatuses sahayı ve oyuncuları tekrar boşa çıkaran oyun durumlarıdır, diğer durumlar zamanı meşgul eder
+var freeingGameStatuses = []models.GameStatus{models.GameStatusRejected, models.GameStatusCancelled}
+
+// exclusionViolation games tablosundaki çakışma kısıtının (games_field_no_overlap) hata kodudur
+const exclusionViolation = "23P01"
+
+// blockingGames sorguyu verilen aralıkla çakışan ve zamanı meşgul eden oyunlarla sınırlar
+func blockingGames(q *bun.SelectQuery, start, end time.Time) *bun.SelectQuery {
+	return q.
+		Where("g.status NOT IN (?)", bun.In(freeingGameStatuses)).
+		Where("g.start_time < ?", end).
+		Where("g.end_time > ?", start)
+}
+
+// checkFieldSlot sahanın satırını kilitleyip aralığın sahanın açık olduğu saatlere denk geldiğini ve boş olduğunu doğrular. Aynı sahaya yapılan rezervasyonlar
+// böylece sıraya girer; veritabanındaki çakışma kısıtı da son güvencedir.
+func checkFieldSlot(ctx context.Context, tx bun.Tx, fieldID uint, start, end time.Time, excludeGameID int64) error {
+	field := new(models.Field)
+	err := tx.NewSelect().
+		Model(field).
+		Where("f.id = ?", fieldID).
+		For("UPDATE").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("geçersiz fieldID: böyle bir saha mevcut değil")
+	}
+	if err != nil {
+		return err
+	}
+	if !field.Available {
+		return ErrFieldUnavailable
+	}
+
+	// Süresi dolan rezervasyon tutmaları sahayı meşgul etmesin
+	if _, err := expireReservationHolds(ctx, tx, fieldID); err != nil {
+		return err
+	}
+
+	hours, err := fieldOpeningHours(ctx, tx, fieldID)
+	if err != nil {
+		return err
+	}
+	if !field.Schedule(hours).Contains(start, end) {
+		return ErrFieldClosed
+	}
+
+	closed, err := tx.NewSelect().
+		Model((*models.FieldBlackout)(nil)).
+		Where("field_id = ?", fieldID).
+		Where("starts_at < ?", end).
+		Where("ends_at > ?", start).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if closed {
+		return ErrFieldBlackout
+	}
+
+	booked, err := blockingGames(tx.NewSelect().Model((*models.Game)(nil)), start, end).
+		Where("g.field_id = ?", fieldID).
+		Where("g.id <> ?", excludeGameID).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if booked {
+		return ErrFieldDoubleBooked
+	}
+
+	return nil
+}
+
+// checkTeamClash takımın aralıkla çakışan başka bir oyunu olup olmadığına bakar. Takım bir oyuna ya oyuncuları
+// üzerinden ya da oyuna bağlı bir lig maçı üzerinden katılır.
+func checkTeamClash(ctx context.Context, db bun.IDB, teamID uint, start, end time.Time, excludeGameID int64) error {
+	clash, err := blockingGames(db.NewSelect().Model((*models.Game)(nil)), start, end).
+		Where("g.id <> ?", excludeGameID).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("EXISTS (SELECT 1 FROM game_participants gp WHERE gp.game_id = g.id AND gp.team_id = ?)", teamID).
+				WhereOr("EXISTS (SELECT 1 FROM matches m WHERE m.game_id = g.id AND (m.home_team_id = ? OR m.away_team_id = ?))", teamID, teamID)
+		}).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if clash {
+		return ErrTeamScheduleClash
+	}
+
+	return nil
+}
+
+// checkPlayerClash oyuncunun aralıkla çakışan başka bir oyuna kayıtlı olup olmadığına bakar
+func checkPlayerClash(ctx context.Context, db bun.IDB, userID uint, start, end time.Time, excludeGameID int64) error {
+	clash, err := blockingGames(db.NewSelect().Model((*models.Game)(nil)), start, end).
+		Join("JOIN game_participants gp ON gp.game_id = g.id").
+		Where("gp.user_id = ?", userID).
+		Where("g.id <> ?", excludeGameID).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if clash {
+		return ErrPlayerScheduleClash
+	}
+
+	return nil
+}
+
+// checkParticipantsClash oyunun saati değiştiğinde kayıtlı oyunculardan birinin başka bir oyunla çakışıp çakışmadığına bakar
+func checkParticipantsClash(ctx context.Context, db bun.IDB, gameID int64, start, end time.Time) error {
+	clash, err := blockingGames(db.NewSelect().Model((*models.Game)(nil)), start, end).
+		Join("JOIN game_participants other ON other.game_id = g.id").
+		Join("JOIN game_participants gp ON gp.user_id = other.user_id").
+		Where("gp.game_id = ?", gameID).
+		Where("g.id <> ?", gameID).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if clash {
+		return ErrPlayerScheduleClash
+	}
+
+	return nil
+}
+
+// checkMatchClash maça bir oyun bağlandıysa ev sahibi ve deplasman takımının o saatte başka bir oyunu olmadığını doğrular
+func checkMatchClash(ctx context.Context, tx bun.Tx, match models.Match) error {
+	if match.GameID == 0 {
+		return nil
+	}
+
+	game := new(models.Game)
+	err := tx.NewSelect().
+		Model(game).
+		Where("g.id = ?", match.GameID).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, teamID := range []uint{match.HomeTeamID, match.AwayTeamID} {
+		if err := checkTeamClash(ctx, tx, teamID, game.StartTime, game.EndTime, game.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bookingError veritabanının çakışma kısıtını ErrFieldDoubleBooked hatasına çevirir
+func bookingError(err error) error {
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) && pgErr.Field('C') == exclusionViolation {
+		return ErrFieldDoubleBooked
+	}
+	return err
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/standings"
+	"github.com/uptrace/bun"
+)
+
+// standingsLockNamespace aynı ligin puan durumunun aynı anda iki kez kurulmasını engelleyen advisory lock anahtarıdır
+const standingsLockNamespace = 4100
+
+type IStandingsRepository interface {
+	RebuildLeagueStandings(ctx context.Context, leagueID uint) error
+	GetLeagueStandings(ctx context.Context, leagueID uint) ([]models.LeagueTeam, error)
+}
+
+type StandingsRepository struct {
+	db *bun.DB
+}
+
+func NewStandingsRepository(db *bun.DB) IStandingsRepository {
+	return &StandingsRepository{db: db}
+}
+
+// RebuildLeagueStandings ligin puan durumunu tamamlanmış maçlardan tek transaction içinde yeniden kurar
+func (r StandingsRepository) RebuildLeagueStandings(ctx context.Context, leagueID uint) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return rebuildLeagueStandings(ctx, tx, leagueID)
+	})
+}
+
+// GetLeagueStandings ligin puan tablosunu sıraya göre getirir
+func (r StandingsRepository) GetLeagueStandings(ctx context.Context, leagueID uint) ([]models.LeagueTeam, error) {
+	var table []models.LeagueTeam
+	err := r.db.NewSelect().
+		Model(&table).
+		Relation("Team").
+		Where("lt.league_id = ?", leagueID).
+		OrderExpr("lt.rank ASC, lt.team_id ASC").
+		Scan(ctx)
+	return table, err
+}
+
+// rebuildLeagueStandings maç değişikliği ile aynı transaction içinde çağrılır ki puan durumu
+// maçlarla her zaman tutarlı kalsın. Lig başına advisory lock eşzamanlı güncellemeleri sıraya koyar.
+func rebuildLeagueStandings(ctx context.Context, tx bun.Tx, leagueID uint) error {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(?, ?)", standingsLockNamespace, leagueID); err != nil {
+		return err
+	}
+
+	league := new(models.League)
+	err := tx.NewSelect().
+		Model(league).
+		Where("l.id = ?", leagueID).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	var teamIDs []uint
+	err = tx.NewSelect().
+		Model((*models.LeagueTeam)(nil)).
+		Column("team_id").
+		Where("league_id = ?", leagueID).
+		Scan(ctx, &teamIDs)
+	if err != nil {
+		return err
+	}
+
+	var matches []models.Match
+	err = tx.NewSelect().
+		Model(&matches).
+		Column("home_team_id", "away_team_id", "home_score", "away_score", "match_time", "status", "forfeited_by").
+		Where("league_id = ?", leagueID).
+		Where("status IN (?)", bun.In(models.StandingsMatchStatuses)).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	results := make([]standings.Result, 0, len(matches))
+	for _, match := range matches {
+		results = append(results, standings.Result{
+			HomeTeamID:  match.HomeTeamID,
+			AwayTeamID:  match.AwayTeamID,
+			HomeScore:   match.HomeScore,
+			AwayScore:   match.AwayScore,
+			PlayedAt:    match.MatchTime,
+			ForfeitedBy: match.ForfeitedBy,
+			Walkover:    match.Status == models.MatchStatusWalkover,
+		})
+	}
+
+	fairPlay, err := leagueFairPlay(ctx, tx, leagueID)
+	if err != nil {
+		return err
+	}
+
+	table := standings.Compute(teamIDs, results, league.StandingsRules(), fairPlay)
+	if len(table) == 0 {
+		return nil
+	}
+
+	rows := make([]models.LeagueTeam, 0, len(table))
+	for _, row := range table {
+		rows = append(rows, models.LeagueTeam{
+			LeagueID:     leagueID,
+			TeamID:       row.TeamID,
+			Points:       row.Points,
+			Rank:         row.Rank,
+			Played:       row.Played,
+			Won:          row.Won,
+			Drawn:        row.Drawn,
+			Lost:         row.Lost,
+			GoalsFor:     row.GoalsFor,
+			GoalsAgainst: row.GoalsAgainst,
+			GoalDiff:     row.GoalDifference(),
+			Form:         row.Form,
+			DecidedBy:    string(row.DecidedBy),
+		})
+	}
+
+	_, err = tx.NewInsert().
+		Model(&rows).
+		On("CONFLICT (league_id, team_id) DO UPDATE").
+		Set("points = EXCLUDED.points").
+		Set("rank = EXCLUDED.rank").
+		Set("played = EXCLUDED.played").
+		Set("won = EXCLUDED.won").
+		Set("drawn = EXCLUDED.drawn").
+		Set("lost = EXCLUDED.lost").
+		Set("goals_for = EXCLUDED.goals_for").
+		Set("goals_against = EXCLUDED.goals_against").
+		Set("goal_difference = EXCLUDED.goal_difference").
+		Set("form = EXCLUDED.form").
+		Set("decided_by = EXCLUDED.decided_by").
+		Exec(ctx)
+	return err
+}
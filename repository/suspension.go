@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/personal-project/pitch-league/discipline"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrSuspensionNotFound = errors.New("ceza bulunamadı")
+	ErrSuspensionLifted   = errors.New("ceza zaten kaldırılmış")
+	ErrPlayerSuspended    = errors.New("oyuncu cezalı olduğu için bu maçta oynayamaz")
+	ErrTeamNotInLeague    = errors.New("takım bu ligde değil")
+)
+
+type ISuspensionRepository interface {
+	GetLeagueSuspensions(ctx context.Context, leagueID uint) ([]models.Suspension, map[int64]discipline.Serving, error)
+	CreateSuspension(ctx context.Context, suspension *models.Suspension) error
+	LiftSuspension(ctx context.Context, leagueID uint, id int64, liftedBy int64) error
+}
+
+type SuspensionRepository struct {
+	db *bun.DB
+}
+
+func NewSuspensionRepository(db *bun.DB) ISuspensionRepository {
+	return &SuspensionRepository{db: db}
+}
+
+// GetLeagueSuspensions ligin tüm cezalarını ve her cezanın hangi maçlarda çekildiğini getirir
+func (r SuspensionRepository) GetLeagueSuspensions(ctx context.Context, leagueID uint) ([]models.Suspension, map[int64]discipline.Serving, error) {
+	var suspensions []models.Suspension
+	err := r.db.NewSelect().
+		Model(&suspensions).
+		Relation("User").
+		Relation("Team").
+		Where("s.league_id = ?", leagueID).
+		OrderExpr("s.starts_after DESC, s.id DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	servings, err := serveSuspensions(ctx, r.db, leagueID, suspensions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return suspensions, servings, nil
+}
+
+// CreateSuspension lig yönetiminin verdiği cezayı kaydeder, takımın ligde olması gerekir
+func (r SuspensionRepository) CreateSuspension(ctx context.Context, suspension *models.Suspension) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		exists, err := tx.NewSelect().
+			Model((*models.LeagueTeam)(nil)).
+			Where("league_id = ?", suspension.LeagueID).
+			Where("team_id = ?", suspension.TeamID).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrTeamNotInLeague
+		}
+
+		_, err = tx.NewInsert().
+			Model(suspension).
+			Exec(ctx)
+		return err
+	})
+}
+
+// LiftSuspension cezayı kaldırır, kaldırılan ceza bir daha maç kapsamaz. Kartlardan gelen cezalar kartlar
+// yeniden hesaplansa da kaldırılmış kalır.
+func (r SuspensionRepository) LiftSuspension(ctx context.Context, leagueID uint, id int64, liftedBy int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		suspension := new(models.Suspension)
+		err := tx.NewSelect().
+			Model(suspension).
+			Where("s.id = ?", id).
+			Where("s.league_id = ?", leagueID).
+			For("UPDATE").
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSuspensionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if suspension.LiftedAt != nil {
+			return ErrSuspensionLifted
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.Suspension)(nil)).
+			Set("lifted_at = ?", time.Now()).
+			Set("lifted_by = ?", liftedBy).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+}
+
+// checkSuspension oyuncunun, takımının oyuna bağlı lig maçında cezalı olup olmadığını kontrol eder
+func checkSuspension(ctx context.Context, tx bun.Tx, gamePart models.GameParticipants) error {
+	var matches []models.Match
+	err := tx.NewSelect().
+		Model(&matches).
+		Where("m.game_id = ?", gamePart.GameID).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("m.home_team_id = ?", gamePart.TeamID).
+				WhereOr("m.away_team_id = ?", gamePart.TeamID)
+		}).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, match := range matches {
+		var suspensions []models.Suspension
+		err := tx.NewSelect().
+			Model(&suspensions).
+			Where("s.league_id = ?", match.LeagueID).
+			Where("s.user_id = ?", gamePart.UserID).
+			Where("s.team_id = ?", gamePart.TeamID).
+			Where("s.lifted_at IS NULL").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+		if len(suspensions) == 0 {
+			continue
+		}
+
+		servings, err := serveSuspensions(ctx, tx, match.LeagueID, suspensions)
+		if err != nil {
+			return err
+		}
+		for _, suspension := range suspensions {
+			serving := servings[suspension.ID]
+			if serving.Covers(match.ID) {
+				return fmt.Errorf("%w (%s, %d maç ceza, %d maç kaldı)", ErrPlayerSuspended, suspension.Reason, suspension.Matches, serving.Remaining)
+			}
+		}
+	}
+	return nil
+}
+
+// serveSuspensions kaldırılmamış cezaları takımların ligdeki maçlarına dağıtır. Kaldırılmış cezalar maç kapsamaz.
+func serveSuspensions(ctx context.Context, db bun.IDB, leagueID uint, suspensions []models.Suspension) (map[int64]discipline.Serving, error) {
+	servings := make(map[int64]discipline.Serving, len(suspensions))
+
+	type player struct {
+		userID, teamID uint
+	}
+	bans := make(map[player][]discipline.Ban)
+	teamIDs := make([]uint, 0)
+	for _, suspension := range suspensions {
+		if suspension.LiftedAt != nil {
+			servings[suspension.ID] = discipline.Serving{Ban: suspension.Ban(), MatchIDs: []int64{}}
+			continue
+		}
+		key := player{suspension.UserID, suspension.TeamID}
+		if _, ok := bans[key]; !ok {
+			teamIDs = append(teamIDs, suspension.TeamID)
+		}
+		bans[key] = append(bans[key], suspension.Ban())
+	}
+	if len(bans) == 0 {
+		return servings, nil
+	}
+
+	var matches []models.Match
+	err := db.NewSelect().
+		Model(&matches).
+		Column("id", "home_team_id", "away_team_id", "match_time", "status").
+		Where("league_id = ?", leagueID).
+		Where("status IN (?)", bun.In(models.SuspensionServingStatuses)).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("home_team_id IN (?)", bun.In(teamIDs)).
+				WhereOr("away_team_id IN (?)", bun.In(teamIDs))
+		}).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make(map[uint][]discipline.TeamMatch)
+	for _, match := range matches {
+		teamMatch := discipline.TeamMatch{
+			MatchID:  match.ID,
+			PlayedAt: match.MatchTime,
+			Played:   match.Status != models.MatchStatusScheduled,
+		}
+		schedules[match.HomeTeamID] = append(schedules[match.HomeTeamID], teamMatch)
+		schedules[match.AwayTeamID] = append(schedules[match.AwayTeamID], teamMatch)
+	}
+
+	for key, playerBans := range bans {
+		for _, serving := range discipline.Serve(playerBans, schedules[key.teamID]) {
+			servings[serving.Ban.ID] = serving
+		}
+	}
+	return servings, nil
+}
+
+// syncLeagueSuspensions ligin kartlarından gelen cezaları kurallara göre yeniden hesaplar. Değişmeyen cezalar
+// (kaldırılmış olsalar da) korunur, artık karşılığı olmayanlar silinir. Elle verilen cezalara dokunulmaz.
+func syncLeagueSuspensions(ctx context.Context, tx bun.Tx, leagueID uint) error {
+	league := new(models.League)
+	err := tx.NewSelect().
+		Model(league).
+		Where("l.id = ?", leagueID).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	var events []struct {
+		UserID    uint                  `bun:"user_id"`
+		TeamID    uint                  `bun:"team_id"`
+		MatchID   int64                 `bun:"match_id"`
+		MatchTime time.Time             `bun:"match_time"`
+		Type      models.MatchEventType `bun:"type"`
+	}
+	err = tx.NewSelect().
+		TableExpr("match_events AS me").
+		Join("JOIN matches AS m ON m.id = me.match_id").
+		ColumnExpr("me.user_id, me.team_id, me.match_id, m.match_time, me.type").
+		Where("m.league_id = ?", leagueID).
+		Where("me.type IN (?)", bun.In([]models.MatchEventType{models.MatchEventYellowCard, models.MatchEventRedCard})).
+		Scan(ctx, &events)
+	if err != nil {
+		return err
+	}
+
+	cards := make([]discipline.Card, 0, len(events))
+	for _, event := range events {
+		cards = append(cards, discipline.Card{
+			UserID:   event.UserID,
+			TeamID:   event.TeamID,
+			MatchID:  event.MatchID,
+			PlayedAt: event.MatchTime,
+			Red:      event.Type == models.MatchEventRedCard,
+		})
+	}
+
+	type cardKey struct {
+		userID  uint
+		matchID int64
+		reason  discipline.Reason
+	}
+	desired := make(map[cardKey]discipline.Ban)
+	for _, ban := range discipline.Compute(cards, league.DisciplineRules()) {
+		key := cardKey{ban.UserID, ban.MatchID, ban.Reason}
+		// Aynı maçta birden fazla sarı kart ceza sınırını iki kez aşarsa cezalar birleştirilir
+		if existing, ok := desired[key]; ok {
+			ban.Matches += existing.Matches
+		}
+		desired[key] = ban
+	}
+
+	var existing []models.Suspension
+	err = tx.NewSelect().
+		Model(&existing).
+		Where("s.league_id = ?", leagueID).
+		Where("s.match_id IS NOT NULL").
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, suspension := range existing {
+		key := cardKey{suspension.UserID, suspension.MatchID, suspension.Reason}
+		ban, ok := desired[key]
+		if !ok {
+			_, err := tx.NewDelete().
+				Model((*models.Suspension)(nil)).
+				Where("id = ?", suspension.ID).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		delete(desired, key)
+
+		if suspension.Matches == ban.Matches && suspension.TeamID == ban.TeamID && suspension.StartsAfter.Equal(ban.StartsAfter) {
+			continue
+		}
+		_, err := tx.NewUpdate().
+			Model((*models.Suspension)(nil)).
+			Set("matches = ?", ban.Matches).
+			Set("team_id = ?", ban.TeamID).
+			Set("starts_after = ?", ban.StartsAfter).
+			Where("id = ?", suspension.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(desired) == 0 {
+		return nil
+	}
+	added := make([]models.Suspension, 0, len(desired))
+	for _, ban := range desired {
+		added = append(added, models.Suspension{
+			LeagueID:    leagueID,
+			UserID:      ban.UserID,
+			TeamID:      ban.TeamID,
+			MatchID:     ban.MatchID,
+			Reason:      ban.Reason,
+			Matches:     ban.Matches,
+			StartsAfter: ban.StartsAfter,
+		})
+	}
+	_, err = tx.NewInsert().
+		Model(&added).
+		Exec(ctx)
+	return err
+}
@@ -2,20 +2,37 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/personal-project/pitch-league/models"
 	"github.com/uptrace/bun"
 )
 
+var (
+	ErrTeamFull            = errors.New("takımın kapasitesi dolu")
+	ErrAlreadyTeamMember   = errors.New("kullanıcı zaten bu takımın üyesi")
+	ErrTeamMemberNotFound  = errors.New("kullanıcı bu takımın aktif üyesi değil")
+	ErrJerseyNumberTaken   = errors.New("bu forma numarası takımda başka bir oyuncuya ait")
+	ErrCaptainCannotLeave  = errors.New("kaptan takımdan ayrılmadan önce kaptanlığı devretmeli")
+	ErrCaptainRoleReserved = errors.New("kaptanlık sadece devir ile değiştirilebilir")
+)
+
 type ITeamRepository interface {
 	IBaseRepository[models.Team]
-	AddUserToTeam(ctx context.Context, userID, teamID int64) error
 	GetAllTeam(ctx context.Context) ([]models.Team, error)
 	GetByTeamID(ctx context.Context, id int64) (*models.Team, error)
 	DeleteByTeamID(ctx context.Context, id int64) error
 	UpdateTeam(ctx context.Context, m models.Team) error
 	CreateTeam(ctx context.Context, team models.Team) error
+	AddTeamMember(ctx context.Context, member models.TeamMember) error
+	GetTeamMembers(ctx context.Context, teamID int64) ([]models.TeamMember, error)
+	GetTeamMember(ctx context.Context, teamID, userID int64) (*models.TeamMember, error)
+	GetUserTeams(ctx context.Context, userID int64) ([]models.TeamMember, error)
+	UpdateTeamMember(ctx context.Context, member models.TeamMember) error
+	RemoveTeamMember(ctx context.Context, teamID, userID int64, status models.TeamMemberStatus) error
+	TransferCaptaincy(ctx context.Context, teamID, newCaptainID int64) error
 }
 
 type TeamRepository struct {
@@ -34,6 +51,8 @@ func (r TeamRepository) GetAllTeam(ctx context.Context) ([]models.Team, error) {
 	var teams []models.Team
 	err := r.db.NewSelect().
 		Model(&teams).
+		ColumnExpr("t.*").
+		ColumnExpr(memberCountExpr).
 		Relation("Captain").
 		Scan(ctx)
 	return teams, err
@@ -43,6 +62,8 @@ func (r TeamRepository) GetByTeamID(ctx context.Context, id int64) (*models.Team
 	team := new(models.Team)
 	err := r.db.NewSelect().
 		Model(team).
+		ColumnExpr("t.*").
+		ColumnExpr(memberCountExpr).
 		Relation("Captain").
 		Where("t.id = ?", id).
 		Scan(ctx)
@@ -70,58 +91,336 @@ func (r TeamRepository) UpdateTeam(ctx context.Context, m models.Team) error {
 	return err
 }
 
-func (r TeamRepository) AddUserToTeam(ctx context.Context, userID, teamID int64) error {
-	// Takımı kontrol et
-	team := new(models.Team)
+func (r TeamRepository) CreateTeam(ctx context.Context, team models.Team) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Önce takımı oluştur
+		if _, err := tx.NewInsert().Model(&team).Exec(ctx); err != nil {
+			return err
+		}
+
+		// Kaptan takımın ilk üyesidir
+		_, err := tx.NewInsert().
+			Model(&models.TeamMember{
+				TeamID: team.ID,
+				UserID: team.CaptainID,
+				Role:   models.TeamMemberRoleCaptain,
+				Status: models.TeamMemberStatusActive,
+			}).
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Oluşturulan takımı Captain ilişkisiyle birlikte yükle
+	err = r.db.NewSelect().
+		Model(&team).
+		Relation("Captain").
+		Where("t.id = ?", team.ID).
+		Scan(ctx)
+
+	return err
+}
+
+func (r TeamRepository) AddTeamMember(ctx context.Context, member models.TeamMember) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return addTeamMember(ctx, tx, member)
+	})
+}
+
+func (r TeamRepository) GetTeamMembers(ctx context.Context, teamID int64) ([]models.TeamMember, error) {
+	var members []models.TeamMember
 	err := r.db.NewSelect().
-		Model(team).
-		Where("t.id = ?", teamID).
+		Model(&members).
+		Relation("User").
+		Where("tm.team_id = ?", teamID).
+		Where("tm.status = ?", models.TeamMemberStatusActive).
+		OrderExpr("tm.joined_at ASC").
 		Scan(ctx)
+	return members, err
+}
 
+func (r TeamRepository) GetTeamMember(ctx context.Context, teamID, userID int64) (*models.TeamMember, error) {
+	member := new(models.TeamMember)
+	err := r.db.NewSelect().
+		Model(member).
+		Relation("User").
+		Where("tm.team_id = ?", teamID).
+		Where("tm.user_id = ?", userID).
+		Where("tm.status = ?", models.TeamMemberStatusActive).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTeamMemberNotFound
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return member, nil
+}
 
-	if team.Capacity <= 0 {
-		return errors.New("team capacity is full")
+// GetUserTeams kullanıcının aktif üyeliklerini takımlarıyla birlikte getirir
+func (r TeamRepository) GetUserTeams(ctx context.Context, userID int64) ([]models.TeamMember, error) {
+	var members []models.TeamMember
+	err := r.db.NewSelect().
+		Model(&members).
+		Where("tm.user_id = ?", userID).
+		Where("tm.status = ?", models.TeamMemberStatusActive).
+		OrderExpr("tm.joined_at ASC").
+		Scan(ctx)
+	if err != nil || len(members) == 0 {
+		return members, err
 	}
 
-	// Kullanıcıyı güncelle
-	_, err = r.db.NewUpdate().
-		Model((*models.User)(nil)).
-		Set("team_id = ?", teamID).
-		Where("id = ?", userID).
-		Exec(ctx)
+	teamIDs := make([]int64, 0, len(members))
+	for _, member := range members {
+		teamIDs = append(teamIDs, member.TeamID)
+	}
 
+	var teams []models.Team
+	err = r.db.NewSelect().
+		Model(&teams).
+		ColumnExpr("t.*").
+		ColumnExpr(memberCountExpr).
+		Relation("Captain").
+		Where("t.id IN (?)", bun.In(teamIDs)).
+		Scan(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Takım kapasitesini güncelle
-	_, err = r.db.NewUpdate().
-		Model(team).
-		Set("capacity = capacity - 1").
-		Where("t.id = ?", teamID).
-		Exec(ctx)
+	byID := make(map[int64]*models.Team, len(teams))
+	for i := range teams {
+		byID[teams[i].ID] = &teams[i]
+	}
+	for i := range members {
+		members[i].Team = byID[members[i].TeamID]
+	}
+	return members, nil
+}
 
-	return err
+func (r TeamRepository) UpdateTeamMember(ctx context.Context, member models.TeamMember) error {
+	if member.Role == models.TeamMemberRoleCaptain {
+		return ErrCaptainRoleReserved
+	}
+
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		current := new(models.TeamMember)
+		err := tx.NewSelect().
+			Model(current).
+			Where("tm.id = ?", member.ID).
+			Where("tm.status = ?", models.TeamMemberStatusActive).
+			For("UPDATE").
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTeamMemberNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if current.Role == models.TeamMemberRoleCaptain {
+			return ErrCaptainRoleReserved
+		}
+
+		if err := checkJerseyNumber(ctx, tx, current.TeamID, current.UserID, member.JerseyNumber); err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.TeamMember)(nil)).
+			Set("role = ?", member.Role).
+			Set("jersey_number = ?", member.JerseyNumber).
+			Where("id = ?", current.ID).
+			Exec(ctx)
+		return err
+	})
 }
 
-func (r TeamRepository) CreateTeam(ctx context.Context, team models.Team) error {
-	// Önce takımı oluştur
-	_, err := r.db.NewInsert().
-		Model(&team).
-		Exec(ctx)
+func (r TeamRepository) RemoveTeamMember(ctx context.Context, teamID, userID int64, status models.TeamMemberStatus) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		member := new(models.TeamMember)
+		err := tx.NewSelect().
+			Model(member).
+			Where("tm.team_id = ?", teamID).
+			Where("tm.user_id = ?", userID).
+			Where("tm.status = ?", models.TeamMemberStatusActive).
+			For("UPDATE").
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTeamMemberNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if member.Role == models.TeamMemberRoleCaptain {
+			return ErrCaptainCannotLeave
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.TeamMember)(nil)).
+			Set("status = ?", status).
+			Set("left_at = ?", time.Now()).
+			Where("id = ?", member.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Ayrılan oyuncunun bu takım adına henüz onaylanmamış oyunlardaki katılımlarını sil
+		_, err = tx.NewDelete().
+			Model((*models.GameParticipants)(nil)).
+			Where("user_id = ?", userID).
+			Where("team_id = ?", teamID).
+			Where("game_id IN (?)", tx.NewSelect().
+				Model((*models.Game)(nil)).
+				Column("id").
+				Where("status = ?", models.GameStatusPending)).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (r TeamRepository) TransferCaptaincy(ctx context.Context, teamID, newCaptainID int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		team := new(models.Team)
+		err := tx.NewSelect().
+			Model(team).
+			Where("t.id = ?", teamID).
+			For("UPDATE").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		if team.CaptainID == newCaptainID {
+			return nil
+		}
+
+		exists, err := tx.NewSelect().
+			Model((*models.TeamMember)(nil)).
+			Where("team_id = ?", teamID).
+			Where("user_id = ?", newCaptainID).
+			Where("status = ?", models.TeamMemberStatusActive).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrTeamMemberNotFound
+		}
+
+		// Eski kaptan oyuncu olarak takımda kalır
+		_, err = tx.NewUpdate().
+			Model((*models.TeamMember)(nil)).
+			Set("role = ?", models.TeamMemberRolePlayer).
+			Where("team_id = ?", teamID).
+			Where("role = ?", models.TeamMemberRoleCaptain).
+			Where("status = ?", models.TeamMemberStatusActive).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.TeamMember)(nil)).
+			Set("role = ?", models.TeamMemberRoleCaptain).
+			Where("team_id = ?", teamID).
+			Where("user_id = ?", newCaptainID).
+			Where("status = ?", models.TeamMemberStatusActive).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.Team)(nil)).
+			Set("captain_id = ?", newCaptainID).
+			Where("id = ?", teamID).
+			Exec(ctx)
+		return err
+	})
+}
+
+// memberCountExpr takımın aktif üye sayısını hesaplar, kapasite bu sayıdan türetilir
+const memberCountExpr = "(SELECT COUNT(*) FROM team_members AS tm WHERE tm.team_id = t.id AND tm.status = 'ACTIVE') AS member_count"
+
+// userTeamIDs kullanıcının aktif üyesi olduğu takımların id'lerini seçen alt sorgudur
+func userTeamIDs(db bun.IDB, userID int64) *bun.SelectQuery {
+	return db.NewSelect().
+		TableExpr("team_members AS utm").
+		ColumnExpr("utm.team_id").
+		Where("utm.user_id = ?", userID).
+		Where("utm.status = ?", models.TeamMemberStatusActive)
+}
+
+// addTeamMember takım satırını kilitleyip kapasiteyi kontrol eder, ardından üyeyi ekler.
+// Aynı transaction içinde çağrılmalıdır ki eşzamanlı katılımlar kapasiteyi aşmasın.
+func addTeamMember(ctx context.Context, tx bun.Tx, member models.TeamMember) error {
+	team := new(models.Team)
+	err := tx.NewSelect().
+		Model(team).
+		Where("t.id = ?", member.TeamID).
+		For("UPDATE").
+		Scan(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Oluşturulan takımı Captain ilişkisiyle birlikte yükle
-	err = r.db.NewSelect().
-		Model(&team).
-		Relation("Captain").
-		Where("t.id = ?", team.ID).
-		Scan(ctx)
+	count, err := tx.NewSelect().
+		Model((*models.TeamMember)(nil)).
+		Where("team_id = ?", member.TeamID).
+		Where("status = ?", models.TeamMemberStatusActive).
+		Count(ctx)
+	if err != nil {
+		return err
+	}
+	if int64(count) >= team.Capacity {
+		return ErrTeamFull
+	}
+
+	exists, err := tx.NewSelect().
+		Model((*models.TeamMember)(nil)).
+		Where("team_id = ?", member.TeamID).
+		Where("user_id = ?", member.UserID).
+		Where("status = ?", models.TeamMemberStatusActive).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrAlreadyTeamMember
+	}
 
+	if err := checkJerseyNumber(ctx, tx, member.TeamID, member.UserID, member.JerseyNumber); err != nil {
+		return err
+	}
+
+	member.Role = models.TeamMemberRolePlayer
+	member.Status = models.TeamMemberStatusActive
+	_, err = tx.NewInsert().
+		Model(&member).
+		Exec(ctx)
 	return err
 }
+
+func checkJerseyNumber(ctx context.Context, tx bun.Tx, teamID, userID int64, jerseyNumber *int64) error {
+	if jerseyNumber == nil {
+		return nil
+	}
+
+	taken, err := tx.NewSelect().
+		Model((*models.TeamMember)(nil)).
+		Where("team_id = ?", teamID).
+		Where("user_id <> ?", userID).
+		Where("jersey_number = ?", *jerseyNumber).
+		Where("status = ?", models.TeamMemberStatusActive).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrJerseyNumberTaken
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/personal-project/pitch-league/models"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrTeamInvitationNotFound   = errors.New("davet veya katılım isteği bulunamadı")
+	ErrTeamInvitationExists     = errors.New("bu oyuncu için bekleyen bir davet veya katılım isteği zaten var")
+	ErrTeamInvitationNotPending = errors.New("davet veya katılım isteği artık yanıtlanamaz")
+	ErrTeamInvitationExpired    = errors.New("davet veya katılım isteğinin süresi dolmuş")
+)
+
+type ITeamInvitationRepository interface {
+	CreateTeamInvitation(ctx context.Context, invitation *models.TeamInvitation) error
+	GetTeamInvitation(ctx context.Context, id int64) (*models.TeamInvitation, error)
+	GetPendingTeamInvitationsByTeam(ctx context.Context, teamID int64) ([]models.TeamInvitation, error)
+	GetPendingTeamInvitationsByUser(ctx context.Context, userID int64) ([]models.TeamInvitation, error)
+	AcceptTeamInvitation(ctx context.Context, id, respondedBy int64) error
+	RespondTeamInvitation(ctx context.Context, id, respondedBy int64, status models.TeamInvitationStatus) error
+}
+
+type TeamInvitationRepository struct {
+	db *bun.DB
+}
+
+func NewTeamInvitationRepository(db *bun.DB) ITeamInvitationRepository {
+	return &TeamInvitationRepository{db: db}
+}
+
+func (r TeamInvitationRepository) CreateTeamInvitation(ctx context.Context, invitation *models.TeamInvitation) error {
+	if err := r.expireTeamInvitations(ctx); err != nil {
+		return err
+	}
+
+	isMember, err := r.db.NewSelect().
+		Model((*models.TeamMember)(nil)).
+		Where("team_id = ?", invitation.TeamID).
+		Where("user_id = ?", invitation.UserID).
+		Where("status = ?", models.TeamMemberStatusActive).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if isMember {
+		return ErrAlreadyTeamMember
+	}
+
+	pending, err := r.db.NewSelect().
+		Model((*models.TeamInvitation)(nil)).
+		Where("team_id = ?", invitation.TeamID).
+		Where("user_id = ?", invitation.UserID).
+		Where("status = ?", models.TeamInvitationStatusPending).
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return ErrTeamInvitationExists
+	}
+
+	invitation.Status = models.TeamInvitationStatusPending
+	_, err = r.db.NewInsert().
+		Model(invitation).
+		Exec(ctx)
+	return err
+}
+
+func (r TeamInvitationRepository) GetTeamInvitation(ctx context.Context, id int64) (*models.TeamInvitation, error) {
+	invitation := new(models.TeamInvitation)
+	err := r.db.NewSelect().
+		Model(invitation).
+		Relation("Team").
+		Relation("User").
+		Where("ti.id = ?", id).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTeamInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (r TeamInvitationRepository) GetPendingTeamInvitationsByTeam(ctx context.Context, teamID int64) ([]models.TeamInvitation, error) {
+	var invitations []models.TeamInvitation
+	err := r.db.NewSelect().
+		Model(&invitations).
+		Relation("User").
+		Where("ti.team_id = ?", teamID).
+		Where("ti.status = ?", models.TeamInvitationStatusPending).
+		Where("ti.expires_at > ?", time.Now()).
+		OrderExpr("ti.created_at DESC").
+		Scan(ctx)
+	return invitations, err
+}
+
+func (r TeamInvitationRepository) GetPendingTeamInvitationsByUser(ctx context.Context, userID int64) ([]models.TeamInvitation, error) {
+	var invitations []models.TeamInvitation
+	err := r.db.NewSelect().
+		Model(&invitations).
+		Relation("Team").
+		Where("ti.user_id = ?", userID).
+		Where("ti.status = ?", models.TeamInvitationStatusPending).
+		Where("ti.expires_at > ?", time.Now()).
+		OrderExpr("ti.created_at DESC").
+		Scan(ctx)
+	return invitations, err
+}
+
+// AcceptTeamInvitation daveti kabul edip oyuncuyu aynı transaction içinde takıma ekler,
+// kapasite kontrolü TeamRepository'deki üyelik kuralları ile yapılır
+func (r TeamInvitationRepository) AcceptTeamInvitation(ctx context.Context, id, respondedBy int64) error {
+	if err := r.expireTeamInvitations(ctx); err != nil {
+		return err
+	}
+
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		invitation := new(models.TeamInvitation)
+		err := tx.NewSelect().
+			Model(invitation).
+			Where("ti.id = ?", id).
+			For("UPDATE").
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTeamInvitationNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		switch invitation.Status {
+		case models.TeamInvitationStatusPending:
+		case models.TeamInvitationStatusExpired:
+			return ErrTeamInvitationExpired
+		default:
+			return ErrTeamInvitationNotPending
+		}
+
+		err = addTeamMember(ctx, tx, models.TeamMember{
+			TeamID: invitation.TeamID,
+			UserID: invitation.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.TeamInvitation)(nil)).
+			Set("status = ?", models.TeamInvitationStatusAccepted).
+			Set("responded_by = ?", respondedBy).
+			Set("responded_at = ?", time.Now()).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+}
+
+// RespondTeamInvitation bekleyen daveti reddeder veya iptal eder
+func (r TeamInvitationRepository) RespondTeamInvitation(ctx context.Context, id, respondedBy int64, status models.TeamInvitationStatus) error {
+	if err := r.expireTeamInvitations(ctx); err != nil {
+		return err
+	}
+
+	result, err := r.db.NewUpdate().
+		Model((*models.TeamInvitation)(nil)).
+		Set("status = ?", status).
+		Set("responded_by = ?", respondedBy).
+		Set("responded_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("status = ?", models.TeamInvitationStatusPending).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrTeamInvitationNotPending
+	}
+
+	return nil
+}
+
+// expireTeamInvitations süresi dolan bekleyen davetleri EXPIRED olarak işaretler
+func (r TeamInvitationRepository) expireTeamInvitations(ctx context.Context) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.TeamInvitation)(nil)).
+		Set("status = ?", models.TeamInvitationStatusExpired).
+		Where("status = ?", models.TeamInvitationStatusPending).
+		Where("expires_at <= ?", time.Now()).
+		Exec(ctx)
+	return err
+}
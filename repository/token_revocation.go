@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/uptrace/bun"
+)
+
+// ITokenRevocationStore süresi dolmadan iptal edilen access tokenları tutar. Tek bir token jti ile,
+// bir oturumun veya kullanıcının o ana kadar üretilmiş tüm tokenları kesme zamanıyla iptal edilir.
+type ITokenRevocationStore interface {
+	Revoke(ctx context.Context, tokenID uuid.UUID, userID int64, expiresAt time.Time) error
+	// RevokeIssuedBefore kullanıcının before anından önce üretilen tokenlarını iptal eder, sessionID uuid.Nil ise tüm oturumlarını.
+	// JWT iat saniye hassasiyetinde olduğu için before saniyeye yuvarlanır; aynı saniyede üretilen son token ayrıca jti ile iptal edilmelidir.
+	RevokeIssuedBefore(ctx context.Context, userID int64, sessionID uuid.UUID, before time.Time) error
+	IsRevoked(ctx context.Context, claims *models.AccessTokenClaims) (bool, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type cutoffKey struct {
+	userID    int64
+	sessionID uuid.UUID
+}
+
+type MemoryTokenRevocationStore struct {
+	mu      sync.RWMutex
+	entries map[uuid.UUID]time.Time
+	cutoffs map[cutoffKey]models.AccessTokenCutoff
+	// accessTokenTTL sonunda kesme zamanından önce üretilmiş tokenların hepsi zaten süresi dolmuş olur
+	accessTokenTTL time.Duration
+}
+
+func NewMemoryTokenRevocationStore(accessTokenTTL time.Duration) ITokenRevocationStore {
+	return &MemoryTokenRevocationStore{
+		entries:        make(map[uuid.UUID]time.Time),
+		cutoffs:        make(map[cutoffKey]models.AccessTokenCutoff),
+		accessTokenTTL: accessTokenTTL,
+	}
+}
+
+func (s *MemoryTokenRevocationStore) Revoke(ctx context.Context, tokenID uuid.UUID, userID int64, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[tokenID] = expiresAt
+	return nil
+}
+
+func (s *MemoryTokenRevocationStore) RevokeIssuedBefore(ctx context.Context, userID int64, sessionID uuid.UUID, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := newAccessTokenCutoff(userID, sessionID, before, s.accessTokenTTL)
+	key := cutoffKey{userID: userID, sessionID: sessionID}
+	if existing, ok := s.cutoffs[key]; ok && existing.RevokedBefore.After(cutoff.RevokedBefore) {
+		return nil
+	}
+	s.cutoffs[key] = cutoff
+	return nil
+}
+
+func (s *MemoryTokenRevocationStore) IsRevoked(ctx context.Context, claims *models.AccessTokenClaims) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	if expiresAt, ok := s.entries[claims.ID]; ok && expiresAt.After(now) {
+		return true, nil
+	}
+
+	issuedAt := issuedAt(claims)
+	for _, sessionID := range []uuid.UUID{uuid.Nil, claims.SessionID} {
+		cutoff, ok := s.cutoffs[cutoffKey{userID: claims.UserID, sessionID: sessionID}]
+		if ok && cutoff.ExpiresAt.After(now) && issuedAt.Before(cutoff.RevokedBefore) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *MemoryTokenRevocationStore) DeleteExpired(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	now := time.Now()
+	for tokenID, expiresAt := range s.entries {
+		if !expiresAt.After(now) {
+			delete(s.entries, tokenID)
+			deleted++
+		}
+	}
+	for key, cutoff := range s.cutoffs {
+		if !cutoff.ExpiresAt.After(now) {
+			delete(s.cutoffs, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+type PostgresTokenRevocationStore struct {
+	db             *bun.DB
+	accessTokenTTL time.Duration
+}
+
+func NewPostgresTokenRevocationStore(db *bun.DB, accessTokenTTL time.Duration) ITokenRevocationStore {
+	return &PostgresTokenRevocationStore{db: db, accessTokenTTL: accessTokenTTL}
+}
+
+func (s PostgresTokenRevocationStore) Revoke(ctx context.Context, tokenID uuid.UUID, userID int64, expiresAt time.Time) error {
+	_, err := s.db.NewInsert().
+		Model(&models.RevokedAccessToken{
+			TokenID:   tokenID,
+			UserID:    userID,
+			ExpiresAt: expiresAt,
+		}).
+		On("CONFLICT (token_id) DO NOTHING").
+		Exec(ctx)
+	return err
+}
+
+func (s PostgresTokenRevocationStore) RevokeIssuedBefore(ctx context.Context, userID int64, sessionID uuid.UUID, before time.Time) error {
+	cutoff := newAccessTokenCutoff(userID, sessionID, before, s.accessTokenTTL)
+	_, err := s.db.NewInsert().
+		Model(&cutoff).
+		On("CONFLICT (user_id, session_id) DO UPDATE").
+		Set("revoked_before = GREATEST(access_token_cutoffs.revoked_before, EXCLUDED.revoked_before)").
+		Set("expires_at = GREATEST(access_token_cutoffs.expires_at, EXCLUDED.expires_at)").
+		Exec(ctx)
+	return err
+}
+
+func (s PostgresTokenRevocationStore) IsRevoked(ctx context.Context, claims *models.AccessTokenClaims) (bool, error) {
+	now := time.Now()
+	revoked, err := s.db.NewSelect().
+		Model((*models.RevokedAccessToken)(nil)).
+		Where("token_id = ?", claims.ID).
+		Where("expires_at > ?", now).
+		Exists(ctx)
+	if err != nil || revoked {
+		return revoked, err
+	}
+
+	return s.db.NewSelect().
+		Model((*models.AccessTokenCutoff)(nil)).
+		Where("user_id = ?", claims.UserID).
+		Where("session_id IN (?)", bun.In([]uuid.UUID{uuid.Nil, claims.SessionID})).
+		Where("revoked_before > ?", issuedAt(claims)).
+		Where("expires_at > ?", now).
+		Exists(ctx)
+}
+
+func (s PostgresTokenRevocationStore) DeleteExpired(ctx context.Context) (int64, error) {
+	now := time.Now()
+	tokens, err := s.db.NewDelete().
+		Model((*models.RevokedAccessToken)(nil)).
+		Where("expires_at <= ?", now).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := tokens.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoffs, err := s.db.NewDelete().
+		Model((*models.AccessTokenCutoff)(nil)).
+		Where("expires_at <= ?", now).
+		Exec(ctx)
+	if err != nil {
+		return deleted, err
+	}
+	n, err := cutoffs.RowsAffected()
+	return deleted + n, err
+}
+
+func newAccessTokenCutoff(userID int64, sessionID uuid.UUID, before time.Time, accessTokenTTL time.Duration) models.AccessTokenCutoff {
+	before = before.Truncate(time.Second)
+	return models.AccessTokenCutoff{
+		UserID:        userID,
+		SessionID:     sessionID,
+		RevokedBefore: before,
+		ExpiresAt:     before.Add(accessTokenTTL),
+	}
+}
+
+// issuedAt tokenın üretilme zamanıdır, iat taşımayan eski tokenlar her kesme zamanından önce sayılır
+func issuedAt(claims *models.AccessTokenClaims) time.Time {
+	if claims.IssuedAt == nil {
+		return time.Time{}
+	}
+	return claims.IssuedAt.Time
+}
+
+// StartTokenRevocationSweeper süresi dolan iptal kayıtlarını belirli aralıklarla temizler, ctx kapanınca durur
+func StartTokenRevocationSweeper(ctx context.Context, store ITokenRevocationStore, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := store.DeleteExpired(ctx); err != nil {
+					log.Printf("revoked token sweep failed: %v", err)
+				}
+			}
+		}
+	}()
+}
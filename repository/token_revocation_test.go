@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/personal-project/pitch-league/models"
+)
+
+func accessClaims(userID int64, sessionID uuid.UUID, issuedAt time.Time) *models.AccessTokenClaims {
+	return &models.AccessTokenClaims{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		UserID:    userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt: jwt.NewNumericDate(issuedAt),
+		},
+	}
+}
+
+func TestMemoryTokenRevocationStoreCutoffs(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	session, otherSession := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name   string
+		revoke func(store ITokenRevocationStore) error
+		claims *models.AccessTokenClaims
+		want   bool
+	}{
+		{
+			name:   "oturumda rotasyondan önce üretilmiş token",
+			revoke: func(store ITokenRevocationStore) error { return store.RevokeIssuedBefore(ctx, 1, session, now) },
+			claims: accessClaims(1, session, now.Add(-2*time.Hour)),
+			want:   true,
+		},
+		{
+			name:   "kesme zamanından sonra üretilmiş token",
+			revoke: func(store ITokenRevocationStore) error { return store.RevokeIssuedBefore(ctx, 1, session, now) },
+			claims: accessClaims(1, session, now.Add(time.Second)),
+			want:   false,
+		},
+		{
+			name:   "oturum kesmesi diğer oturumları etkilemez",
+			revoke: func(store ITokenRevocationStore) error { return store.RevokeIssuedBefore(ctx, 1, session, now) },
+			claims: accessClaims(1, otherSession, now.Add(-time.Hour)),
+			want:   false,
+		},
+		{
+			name:   "kullanıcı kesmesi tüm oturumları kapsar",
+			revoke: func(store ITokenRevocationStore) error { return store.RevokeIssuedBefore(ctx, 1, uuid.Nil, now) },
+			claims: accessClaims(1, otherSession, now.Add(-time.Hour)),
+			want:   true,
+		},
+		{
+			name:   "kullanıcı kesmesi başka kullanıcıyı etkilemez",
+			revoke: func(store ITokenRevocationStore) error { return store.RevokeIssuedBefore(ctx, 1, uuid.Nil, now) },
+			claims: accessClaims(2, otherSession, now.Add(-time.Hour)),
+			want:   false,
+		},
+		{
+			name: "daha eski kesme zamanı yenisini geri almaz",
+			revoke: func(store ITokenRevocationStore) error {
+				if err := store.RevokeIssuedBefore(ctx, 1, session, now); err != nil {
+					return err
+				}
+				return store.RevokeIssuedBefore(ctx, 1, session, now.Add(-3*time.Hour))
+			},
+			claims: accessClaims(1, session, now.Add(-time.Hour)),
+			want:   true,
+		},
+		{
+			name: "süresi dolmuş kesme zamanı uygulanmaz",
+			revoke: func(store ITokenRevocationStore) error {
+				return store.RevokeIssuedBefore(ctx, 1, session, now.Add(-20*time.Hour))
+			},
+			claims: accessClaims(1, session, now.Add(-21*time.Hour)),
+			want:   false,
+		},
+		{
+			name:   "iat taşımayan token kesme zamanından önce sayılır",
+			revoke: func(store ITokenRevocationStore) error { return store.RevokeIssuedBefore(ctx, 1, session, now) },
+			claims: &models.AccessTokenClaims{ID: uuid.New(), SessionID: session, UserID: 1},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryTokenRevocationStore(15 * time.Hour)
+			if err := tt.revoke(store); err != nil {
+				t.Fatal(err)
+			}
+			got, err := store.IsRevoked(ctx, tt.claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got revoked %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryTokenRevocationStoreTokenID(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTokenRevocationStore(15 * time.Hour)
+	claims := accessClaims(1, uuid.New(), time.Now())
+
+	if err := store.Revoke(ctx, claims.ID, claims.UserID, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, _ := store.IsRevoked(ctx, claims); !revoked {
+		t.Error("revoked token is still valid")
+	}
+	if revoked, _ := store.IsRevoked(ctx, accessClaims(1, claims.SessionID, time.Now())); revoked {
+		t.Error("another token of the same session is revoked")
+	}
+}
@@ -10,6 +10,9 @@ import (
 type IUserRepository interface {
 	IBaseRepository[models.User]
 	GetByEmail(ctx context.Context, email string) (models.User, error)
+	GetByPhone(ctx context.Context, phone string) (models.User, error)
+	GetByUserName(ctx context.Context, userName string) (models.User, error)
+	ExistsByIdentity(ctx context.Context, email, phone, userName string) (bool, error)
 }
 
 type UserRepository struct {
@@ -30,3 +33,37 @@ func (r UserRepository) GetByEmail(ctx context.Context, email string) (models.Us
 		Scan(ctx)
 	return user, err
 }
+
+func (r UserRepository) GetByPhone(ctx context.Context, phone string) (models.User, error) {
+	var user models.User
+	err := r.db.NewSelect().
+		Model(&user).
+		Where("phone = ?", phone).
+		Scan(ctx)
+	return user, err
+}
+
+func (r UserRepository) GetByUserName(ctx context.Context, userName string) (models.User, error) {
+	var user models.User
+	err := r.db.NewSelect().
+		Model(&user).
+		Where("username = ?", userName).
+		Scan(ctx)
+	return user, err
+}
+
+// ExistsByIdentity e-posta, telefon veya kullanıcı adından herhangi biri kullanılıyor mu kontrol eder
+func (r UserRepository) ExistsByIdentity(ctx context.Context, email, phone, userName string) (bool, error) {
+	return r.db.NewSelect().
+		Model((*models.User)(nil)).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			if email != "" {
+				q = q.WhereOr("email = ?", email)
+			}
+			if phone != "" {
+				q = q.WhereOr("phone = ?", phone)
+			}
+			return q.WhereOr("username = ?", userName)
+		}).
+		Exists(ctx)
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/personal-project/pitch-league/models"
+	"github.com/uptrace/bun"
+)
+
+var ErrVerificationCodeNotFound = errors.New("geçerli bir doğrulama kodu bulunamadı")
+
+type IVerificationRepository interface {
+	CreateVerificationCode(ctx context.Context, code models.VerificationCode) error
+	GetActiveVerificationCode(ctx context.Context, userID int64) (models.VerificationCode, error)
+	GetLatestVerificationCode(ctx context.Context, userID int64) (models.VerificationCode, error)
+	IncrementVerificationAttempts(ctx context.Context, codeID int64) error
+	InvalidateVerificationCodes(ctx context.Context, userID int64) error
+	ConsumeVerificationCode(ctx context.Context, codeID, userID int64) error
+}
+
+type VerificationRepository struct {
+	db *bun.DB
+}
+
+func NewVerificationRepository(db *bun.DB) IVerificationRepository {
+	return &VerificationRepository{db: db}
+}
+
+func (r VerificationRepository) CreateVerificationCode(ctx context.Context, code models.VerificationCode) error {
+	_, err := r.db.NewInsert().
+		Model(&code).
+		Exec(ctx)
+	return err
+}
+
+func (r VerificationRepository) GetActiveVerificationCode(ctx context.Context, userID int64) (models.VerificationCode, error) {
+	var code models.VerificationCode
+	err := r.db.NewSelect().
+		Model(&code).
+		Where("user_id = ?", userID).
+		Where("consumed_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Limit(1).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return code, ErrVerificationCodeNotFound
+	}
+	return code, err
+}
+
+func (r VerificationRepository) GetLatestVerificationCode(ctx context.Context, userID int64) (models.VerificationCode, error) {
+	var code models.VerificationCode
+	err := r.db.NewSelect().
+		Model(&code).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(1).
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return code, ErrVerificationCodeNotFound
+	}
+	return code, err
+}
+
+func (r VerificationRepository) IncrementVerificationAttempts(ctx context.Context, codeID int64) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.VerificationCode)(nil)).
+		Set("attempts = attempts + 1").
+		Where("id = ?", codeID).
+		Exec(ctx)
+	return err
+}
+
+// InvalidateVerificationCodes kullanıcının henüz kullanılmamış tüm kodlarını geçersiz kılar
+func (r VerificationRepository) InvalidateVerificationCodes(ctx context.Context, userID int64) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.VerificationCode)(nil)).
+		Set("expires_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("consumed_at IS NULL").
+		Exec(ctx)
+	return err
+}
+
+// ConsumeVerificationCode kodu kullanıldı olarak işaretler ve kullanıcıyı aynı transaction içinde doğrular
+func (r VerificationRepository) ConsumeVerificationCode(ctx context.Context, codeID, userID int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		now := time.Now()
+		result, err := tx.NewUpdate().
+			Model((*models.VerificationCode)(nil)).
+			Set("consumed_at = ?", now).
+			Where("id = ?", codeID).
+			Where("consumed_at IS NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return ErrVerificationCodeNotFound
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.User)(nil)).
+			Set("verified_at = ?", now).
+			Where("id = ?", userID).
+			Where("verified_at IS NULL").
+			Exec(ctx)
+		return err
+	})
+}
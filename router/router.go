@@ -1,22 +1,49 @@
 package router
 
 import (
+	"context"
 	"time"
 
 	swagger "github.com/arsmn/fiber-swagger/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/personal-project/pitch-league/booking"
 	"github.com/personal-project/pitch-league/handlers"
 	"github.com/personal-project/pitch-league/middleware"
+	"github.com/personal-project/pitch-league/models"
+	"github.com/personal-project/pitch-league/notification"
+	"github.com/personal-project/pitch-league/payment"
 	"github.com/personal-project/pitch-league/repository"
 	"github.com/uptrace/bun"
 )
 
+const (
+	RevocationStoreMemory   = "memory"
+	RevocationStorePostgres = "postgres"
+)
+
+const (
+	NotificationDriverLog  = "log"
+	NotificationDriverFile = "file"
+)
+
 type Config struct {
 	JWTSecret              string
-	AccessTokenExpireTime  int
-	RefreshTokenExpireTime int
+	AccessTokenExpireTime  time.Duration
+	RefreshTokenExpireTime time.Duration
+	// RevocationStore iptal edilen access tokenların nerede tutulacağını belirler: "memory" veya "postgres"
+	RevocationStore         string
+	RevocationSweepInterval time.Duration
+	NotificationDriver      string
+	NotificationFilePath    string
+	// NotificationSweepInterval iptal edilen oyunların bildirimlerinin ne sıklıkla gönderileceğidir
+	NotificationSweepInterval time.Duration
+	// Rezervasyon tutma süresi, iade politikası ve yoğun saat çarpanları
+	ReservationHoldTTL       time.Duration
+	ReservationSweepInterval time.Duration
+	ReservationRefundPolicy  booking.RefundPolicy
+	ReservationPeakHours     []booking.PeakWindow
 }
 
 func Setup(app fiber.Router, db *bun.DB, cfg Config) {
@@ -39,7 +66,7 @@ func Setup(app fiber.Router, db *bun.DB, cfg Config) {
 
 	// Repository'leri oluştur
 	userRepo := repository.NewUserRepository(db)
-	authRepo := repository.NewAuthRepository(db, cfg.JWTSecret, time.Duration(cfg.AccessTokenExpireTime)*time.Hour, time.Duration(cfg.RefreshTokenExpireTime)*time.Hour)
+	authRepo := repository.NewAuthRepository(db, cfg.JWTSecret, cfg.AccessTokenExpireTime, cfg.RefreshTokenExpireTime)
 	teamRepo := repository.NewTeamRepository(db)
 	fieldRepo := repository.NewFieldRepository(db)
 	gameRepo := repository.NewGameRepository(db)
@@ -47,36 +74,101 @@ func Setup(app fiber.Router, db *bun.DB, cfg Config) {
 	leagueRepo := repository.NewLeagueRepository(db)
 	leagueTeamRepo := repository.NewLeagueTeamRepository(db)
 	matchRepo := repository.NewMatchRepository(db)
+	matchEventRepo := repository.NewMatchEventRepository(db)
+	leaderboardRepo := repository.NewLeaderboardRepository(db)
+	suspensionRepo := repository.NewSuspensionRepository(db)
+	playerRepo := repository.NewPlayerRepository(db)
+	verificationRepo := repository.NewVerificationRepository(db)
+	teamInvitationRepo := repository.NewTeamInvitationRepository(db)
+	standingsRepo := repository.NewStandingsRepository(db)
+	fixtureRepo := repository.NewFixtureRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+
+	var revocationStore repository.ITokenRevocationStore
+	switch cfg.RevocationStore {
+	case RevocationStorePostgres:
+		revocationStore = repository.NewPostgresTokenRevocationStore(db, cfg.AccessTokenExpireTime)
+	default:
+		revocationStore = repository.NewMemoryTokenRevocationStore(cfg.AccessTokenExpireTime)
+	}
+	repository.StartTokenRevocationSweeper(context.Background(), revocationStore, cfg.RevocationSweepInterval)
+
+	repository.StartReservationHoldSweeper(context.Background(), reservationRepo, cfg.ReservationSweepInterval)
+
+	// Gerçek bir ödeme sağlayıcısı eklenene kadar ödemeler bellekte tutulur
+	paymentProvider := payment.NewFakeProvider()
+
+	var notifier notification.Notifier
+	switch cfg.NotificationDriver {
+	case NotificationDriverFile:
+		notifier = notification.NewFileNotifier(cfg.NotificationFilePath)
+	default:
+		notifier = notification.NewLogNotifier()
+	}
 
 	// Handler'ları oluştur
-	authHandler := handlers.NewAuthHandler(authRepo, userRepo, time.Duration(cfg.RefreshTokenExpireTime)*time.Hour, cfg.JWTSecret)
-	userHandler := handlers.NewUserHandler(userRepo)
+	authHandler := handlers.NewAuthHandler(authRepo, userRepo, verificationRepo, revocationStore, notifier, cfg.RefreshTokenExpireTime, cfg.JWTSecret)
+	userHandler := handlers.NewUserHandler(userRepo, authRepo, revocationStore)
 	teamHandler := handlers.NewTeamHandler(teamRepo)
+	teamInvitationHandler := handlers.NewTeamInvitationHandler(teamInvitationRepo, teamRepo, userRepo, notifier)
 	fieldHandler := handlers.NewFieldHandler(fieldRepo)
-	gameHandler := handlers.NewGameHandler(gameRepo)
+	gameHandler := handlers.NewGameHandler(gameRepo, notifier)
 	gamePartHandler := handlers.NewGameParticipantsHandler(gamePartRepo)
 	leagueHandler := handlers.NewLeagueHandler(leagueRepo)
 	leagueTeamHandler := handlers.NewLeagueTeamHandler(leagueTeamRepo)
 	matchHandler := handlers.NewMatchHandler(matchRepo)
+	matchEventHandler := handlers.NewMatchEventHandler(matchEventRepo)
+	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardRepo)
+	suspensionHandler := handlers.NewSuspensionHandler(suspensionRepo)
+	playerHandler := handlers.NewPlayerHandler(playerRepo)
+	meHandler := handlers.NewMeHandler(userRepo, authRepo, revocationStore, teamRepo, gameRepo, matchRepo, leagueRepo)
+	standingsHandler := handlers.NewStandingsHandler(standingsRepo)
+	fixtureHandler := handlers.NewFixtureHandler(fixtureRepo, leagueRepo, fieldRepo)
+	reservationHandler := handlers.NewReservationHandler(reservationRepo, fieldRepo, paymentProvider, cfg.ReservationPeakHours, cfg.ReservationRefundPolicy, cfg.ReservationHoldTTL)
+
+	// Hangi yoldan iptal edilirse edilsin oyunların bildirimleri commit'ten sonra buradan gönderilir
+	gameHandler.StartNotificationSweeper(context.Background(), cfg.NotificationSweepInterval)
 
 	// Public routes
 	auth := api.Group("/auth")
+	auth.Post("/register", authHandler.Register)
+	auth.Post("/verify", authHandler.Verify)
+	auth.Post("/verify/resend", authHandler.ResendVerification)
 	auth.Post("/login", authHandler.Login)
 	auth.Post("/refresh", authHandler.RefreshToken)
 	auth.Post("/logout", authHandler.Logout)
 
 	// Protected routes
-	api.Use(middleware.JWTMiddleware(cfg.JWTSecret))
+	api.Use(middleware.JWTMiddleware(cfg.JWTSecret, revocationStore))
+
+	// Session routes
+	sessions := api.Group("/auth/sessions")
+	sessions.Get("/", authHandler.GetSessions)            // açık oturumlarımı listeler
+	sessions.Delete("/", authHandler.RevokeOtherSessions) // mevcut oturum dışındaki tüm oturumları kapatır
+	sessions.Delete("/:id", authHandler.RevokeSession)    // belirli bir oturumu kapatır
+
+	// Me routes, kullanıcı her zaman access tokendan okunur
+	me := api.Group("/me")
+	me.Get("/", meHandler.GetMe)                           // bilgilerimi getirir
+	me.Put("/", meHandler.UpdateMe)                        // ad, soyad ve kullanıcı adımı günceller
+	me.Put("/password", meHandler.ChangeMyPassword)        // parolamı değiştirir, diğer oturumlarımı kapatır
+	me.Get("/player", playerHandler.GetMyPlayer)           // oyuncu profilim ve kariyer istatistiklerim
+	me.Put("/player", playerHandler.UpdateMyPlayerProfile) // oyuncu profilimi günceller
+	me.Get("/teams", meHandler.GetMyTeams)                 // aktif üyesi olduğum takımlar
+	me.Get("/games", meHandler.GetMyUpcomingGames)         // oyuncu veya host olduğum, bitmemiş oyunlar
+	me.Get("/matches", meHandler.GetMyMatches)             // takımlarımın lig maçları
+	me.Get("/leagues", meHandler.GetMyLeagues)             // takımlarımın oynadığı ligler
 
-	// Game Participants routes
-	gameParts := api.Group("/gameParts")
-	gameParts.Get("/", gamePartHandler.GetAllGameParticipants)     // takımların oyun ile ilişkilerini getirir
-	gameParts.Get("/:id", gamePartHandler.GetByGameParticipantsID) // kullanıcı idsine göre oyun ilişkilerini getirir
+	// Player routes
+	players := api.Group("/players")
+	players.Get("/:id", playerHandler.GetPlayer) // oyuncu profili ve kariyer istatistikleri
 
 	// League routes
 	leagues := api.Group("/leagues")
-	leagues.Get("/", leagueHandler.GetAllLeagues)    // tüm ligleri getirir
-	leagues.Get("/:id", leagueHandler.GetByLeagueID) // id ye göre belli bir ligi getirir
+	leagues.Get("/", leagueHandler.GetAllLeagues)                              // tüm ligleri getirir
+	leagues.Get("/:id", leagueHandler.GetByLeagueID)                           // id ye göre belli bir ligi getirir
+	leagues.Get("/:id/standings", standingsHandler.GetLeagueStandings)         // ligin tam puan tablosunu getirir
+	leagues.Get("/:id/leaderboards", leaderboardHandler.GetLeagueLeaderboards) // gol, asist, gol yemeden bitirilen maç ve kart sıralamaları
 
 	// League Team routes
 	leagueTeams := api.Group("/leaguesTeam")
@@ -86,31 +178,73 @@ func Setup(app fiber.Router, db *bun.DB, cfg Config) {
 
 	// Match routes
 	matches := api.Group("/matches")
-	matches.Get("/", matchHandler.GetAllMatches)   // tüm maçları getirir
-	matches.Get("/:id", matchHandler.GetByMatchID) // gameID ye göre o maçı getirir
+	matches.Get("/", matchHandler.GetAllMatches)                 // tüm maçları getirir
+	matches.Get("/:id", matchHandler.GetByMatchID)               // gameID ye göre o maçı getirir
+	matches.Post("/:id/result", matchHandler.SubmitMatchResult)  // maçta oynayan takımın kaptanı skoru bildirir
+	matches.Get("/:id/events", matchEventHandler.GetMatchEvents) // maçın gol, kart ve oyuncu değişikliklerini getirir
 
 	// Team routes
 	teams := api.Group("/teams")
 	teams.Get("/", teamHandler.GetAllTeams)
 	teams.Get("/:id", teamHandler.GetByTeamID)
-	teams.Post("/:id/join/:userID", teamHandler.JoinTeam)
+	teams.Get("/:id/members", teamHandler.GetTeamMembers)            // takımın aktif kadrosunu getirir
+	teams.Post("/:id/join", teamInvitationHandler.RequestToJoinTeam) // isteği yapan kullanıcı için katılım isteği oluşturur
+	teams.Post("/:id/leave", teamHandler.LeaveTeam)                  // isteği yapan kullanıcıyı takımdan çıkarır
+
+	// Kadro yönetimi, sadece takım kaptanı
+	teamCaptain := middleware.RequireTeamCaptain(teamRepo)
+	teams.Put("/:id", teamCaptain, teamHandler.UpdateTeamByID)
+	teams.Put("/:id/members/:userID", teamCaptain, teamHandler.UpdateTeamMember)         // oyuncunun rolünü ve forma numarasını günceller
+	teams.Delete("/:id/members/:userID", teamCaptain, teamHandler.KickTeamMember)        // oyuncuyu takımdan çıkarır
+	teams.Post("/:id/captain", teamCaptain, teamHandler.TransferCaptaincy)               // kaptanlığı başka bir üyeye devreder
+	teams.Get("/:id/invitations", teamCaptain, teamInvitationHandler.GetTeamInvitations) // bekleyen davetler ve katılım istekleri
+	teams.Post("/:id/invitations", teamCaptain, teamInvitationHandler.InviteToTeam)      // kullanıcı adı veya e-posta ile oyuncu davet eder
+
+	// Invitation routes, yetki kontrolü davetin türüne göre handler'da yapılır
+	invitations := api.Group("/invitations")
+	invitations.Get("/", teamInvitationHandler.GetMyInvitations)              // bana gelen davetler ve gönderdiğim istekler
+	invitations.Post("/:id/accept", teamInvitationHandler.AcceptInvitation)   // daveti veya katılım isteğini kabul eder
+	invitations.Post("/:id/decline", teamInvitationHandler.DeclineInvitation) // daveti veya katılım isteğini reddeder
+	invitations.Delete("/:id", teamInvitationHandler.CancelInvitation)        // gönderen taraf daveti geri çeker
 
 	// Field routes
 	fields := api.Group("/fields")
 	fields.Get("/", fieldHandler.GetAllFields)
 	fields.Get("/:id", fieldHandler.GetByFieldID)
+	fields.Get("/:id/availability", fieldHandler.GetFieldAvailability) // from-to aralığındaki boş slotları getirir
+	fields.Get("/:id/schedule", fieldHandler.GetFieldSchedule)
+	fields.Get("/:id/blackouts", fieldHandler.GetFieldBlackouts)
+	fields.Put("/:id", middleware.RequireFieldOwner(fieldRepo), fieldHandler.UpdateFieldByID)                              // sadece saha sahibi
+	fields.Put("/:id/schedule", middleware.RequireFieldOwner(fieldRepo), fieldHandler.UpdateFieldSchedule)                 // açılış saatleri ve slot süresi
+	fields.Post("/:id/blackouts", middleware.RequireFieldOwner(fieldRepo), fieldHandler.CreateFieldBlackout)               // bakım/kapalı dönem ekler
+	fields.Delete("/:id/blackouts/:blackoutID", middleware.RequireFieldOwner(fieldRepo), fieldHandler.DeleteFieldBlackout) // kapalı dönemi siler
 
 	// Game routes
 	games := api.Group("/games")
 	games.Get("/", gameHandler.GetAllGames)
+	games.Post("/", middleware.RequirePermission(models.PermissionGamesHost), gameHandler.HostGame) // isteği yapan kullanıcı host olur
 	games.Get("/:id", gameHandler.GetByGameID)
+	games.Put("/:id", middleware.RequireGameHost(gameRepo), gameHandler.UpdateGameByID) // sadece oyunun hostu
+	games.Post("/:id/cancel", gameHandler.CancelGame)                                   // host veya saha sahibi
+	games.Post("/:id/status", gameHandler.ChangeGameStatus)                             // kabul/ret saha sahibi, bitirme host, yetki handler'da kontrol edilir
+	games.Get("/:id/history", gameHandler.GetGameStatusHistory)                         // durum değişikliklerini kimin ne zaman yaptığı
+
+	// Reservation routes, rezervasyonu yapan veya payı olan kullanıcılar görür, değişiklikleri sadece rezervasyonu yapan yapar
+	reservations := api.Group("/reservations")
+	reservations.Post("/quote", reservationHandler.QuoteReservation)                // saha ve saat aralığı için fiyat hesaplar
+	reservations.Post("/", reservationHandler.CreateReservation)                    // sahayı ödeme için bir süre tutar
+	reservations.Get("/", reservationHandler.GetMyReservations)                     // yaptığım ve payım olan rezervasyonlar
+	reservations.Get("/:id", reservationHandler.GetReservation)                     // rezervasyon detayı ve paylar
+	reservations.Post("/:id/confirm", reservationHandler.ConfirmReservation)        // ücreti çekip rezervasyonu onaylar
+	reservations.Post("/:id/cancel", reservationHandler.CancelReservation)          // iade politikasına göre iptal eder
+	reservations.Put("/:id/shares", reservationHandler.SplitReservation)            // ücreti oyuncular arasında eşit böler
+	reservations.Post("/:id/shares/:userID/paid", reservationHandler.MarkSharePaid) // oyuncunun payını ödediğini kaydeder
 
-	// Admin routes
+	// Admin routes, her grup kendi yetkisini ister
 	adminRoutes := api.Group("/admin")
-	adminRoutes.Use(middleware.AdminControl)
 
 	// Admin User routes
-	adminUsers := adminRoutes.Group("/users")
+	adminUsers := adminRoutes.Group("/users", middleware.RequirePermission(models.PermissionUsersManage))
 	adminUsers.Post("/", userHandler.CreateUser)
 	adminUsers.Get("/", userHandler.GetAllUsers)
 	adminUsers.Get("/:id", userHandler.GetByUserID)
@@ -118,41 +252,56 @@ func Setup(app fiber.Router, db *bun.DB, cfg Config) {
 	adminUsers.Put("/:id", userHandler.UpdateUserByID)
 
 	// Admin Match routes
-	adminMatches := adminRoutes.Group("/matches")
-	adminMatches.Post("/", matchHandler.CreateMatch)          // maç oluşturur
-	adminMatches.Delete("/:id", matchHandler.DeleteByMatchID) // maçı iptal eder
+	adminMatches := adminRoutes.Group("/matches", middleware.RequirePermission(models.PermissionMatchesManage))
+	adminMatches.Post("/", matchHandler.CreateMatch)                                // maç oluşturur
+	adminMatches.Delete("/:id", matchHandler.DeleteByMatchID)                       // maçı iptal eder
+	adminMatches.Post("/:id/status", matchHandler.ChangeMatchStatus)                // maçı bir sonraki durumuna geçirir
+	adminMatches.Put("/:id/result", matchHandler.RecordMatchResult)                 // maçın skorunu girip maçı tamamlar
+	adminMatches.Post("/:id/events", matchEventHandler.CreateMatchEvent)            // gol, kart veya oyuncu değişikliği ekler
+	adminMatches.Put("/:id/events/:eventID", matchEventHandler.UpdateMatchEvent)    // yanlış girilmiş olayı düzeltir
+	adminMatches.Delete("/:id/events/:eventID", matchEventHandler.DeleteMatchEvent) // olayı siler
 
 	// Admin League routes
-	adminLeagues := adminRoutes.Group("/leagues")
-	adminLeagues.Post("/", leagueHandler.CreateLeague)          // yeni bir yerel lig oluşturur
-	adminLeagues.Delete("/:id", leagueHandler.DeleteByLeagueID) // ligi siler
+	adminLeagues := adminRoutes.Group("/leagues", middleware.RequirePermission(models.PermissionLeaguesManage))
+	adminLeagues.Post("/", leagueHandler.CreateLeague)                                         // yeni bir yerel lig oluşturur
+	adminLeagues.Delete("/:id", leagueHandler.DeleteByLeagueID)                                // ligi siler
+	adminLeagues.Post("/:id/standings/rebuild", standingsHandler.RebuildLeagueStandings)       // puan durumunu maç sonuçlarından yeniden kurar
+	adminLeagues.Put("/:id/rules", leagueHandler.UpdateLeagueRules)                            // puanlama, eşitlik ve kart cezası kurallarını günceller
+	adminLeagues.Get("/:id/suspensions", suspensionHandler.GetLeagueSuspensions)               // ligdeki cezaları getirir
+	adminLeagues.Post("/:id/suspensions", suspensionHandler.CreateSuspension)                  // oyuncuya elle ceza verir
+	adminLeagues.Post("/:id/suspensions/:suspensionID/lift", suspensionHandler.LiftSuspension) // cezayı kaldırır
+	adminLeagues.Post("/:id/fixtures/preview", fixtureHandler.PreviewFixtures)                 // fikstürü kaydetmeden üretip gösterir
+	adminLeagues.Post("/:id/fixtures", fixtureHandler.CreateFixtures)                          // fikstürü üretip maçları kaydeder
+	adminLeagues.Post("/:id/fixtures/regenerate", fixtureHandler.RegenerateFixtures)           // sadece oynanmamış haftaları yeniden üretir
 
 	// Admin League Team routes
-	adminLeagueTeams := adminRoutes.Group("/leagueTeams")
+	adminLeagueTeams := adminRoutes.Group("/leagueTeams", middleware.RequirePermission(models.PermissionLeaguesManage))
 	adminLeagueTeams.Post("/", leagueTeamHandler.CreateLeagueTeam)          // yeni oluşturulan takımı belirli bir lige kaydeder
 	adminLeagueTeams.Delete("/:id", leagueTeamHandler.DeleteByLeagueTeamID) // takımı ligden siler
 
 	// Admin Game Participants routes
-	adminGameParts := adminRoutes.Group("/gameParts")
+	adminGameParts := adminRoutes.Group("/gameParts", middleware.RequirePermission(models.PermissionGameParticipantsManage))
+	adminGameParts.Get("/", gamePartHandler.GetAllGameParticipants)            // takımların oyun ile ilişkilerini getirir, oyuncuların iletişim bilgileri içerdiği için sadece adminler görebilir
 	adminGameParts.Get("/users/:id", gamePartHandler.GetGameParticipantsUsers) // game idsine göre maça katılan tüm kullanıcıları getirir
 	adminGameParts.Post("/", gamePartHandler.CreateGameParticipants)           // gamePart ekler
 	adminGameParts.Delete("/:id", gamePartHandler.DeleteByGameParticipantsID)  // gamePart siler
 
 	// Admin Team routes
-	adminTeams := adminRoutes.Group("/teams")
+	adminTeams := adminRoutes.Group("/teams", middleware.RequirePermission(models.PermissionTeamsManage))
 	adminTeams.Post("/", teamHandler.CreateTeam)
 	adminTeams.Delete("/:id", teamHandler.DeleteByTeamID)
 	adminTeams.Put("/:id", teamHandler.UpdateTeamByID)
 
 	// Admin Field routes
-	adminFields := adminRoutes.Group("/fields")
+	adminFields := adminRoutes.Group("/fields", middleware.RequirePermission(models.PermissionFieldsManage))
 	adminFields.Post("/", fieldHandler.CreateField)
 	adminFields.Delete("/:id", fieldHandler.DeleteByFieldID)
 	adminFields.Put("/:id", fieldHandler.UpdateFieldByID)
 
 	// Admin Game routes
-	adminGames := adminRoutes.Group("/games")
+	adminGames := adminRoutes.Group("/games", middleware.RequirePermission(models.PermissionGamesManage))
 	adminGames.Post("/", gameHandler.CreateGame)
 	adminGames.Delete("/:id", gameHandler.DeleteByGameID)
 	adminGames.Put("/:id", gameHandler.UpdateGameByID)
+	adminGames.Post("/:id/cancel", gameHandler.CancelGame)
 }
@@ -0,0 +1,80 @@
+package standings
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Tiebreaker string
+
+const (
+	TiebreakerGoalDifference Tiebreaker = "GOAL_DIFFERENCE"
+	TiebreakerGoalsScored    Tiebreaker = "GOALS_SCORED"
+	TiebreakerHeadToHead     Tiebreaker = "HEAD_TO_HEAD"
+	TiebreakerFairPlay       Tiebreaker = "FAIR_PLAY"
+	TiebreakerDrawingLots    Tiebreaker = "DRAWING_LOTS"
+	// TiebreakerUnresolved hiçbir kural eşitliği bozamadığında takım id'sine göre sıralandığını belirtir
+	TiebreakerUnresolved Tiebreaker = "UNRESOLVED"
+)
+
+// Rules bir ligin puanlama ve eşitlik bozma kurallarıdır
+type Rules struct {
+	PointsPerWin  int64
+	PointsPerDraw int64
+	PointsPerLoss int64
+	// Hükmen maçlarda kazanan ve kaybeden takıma yazılan skor, örn. 3-0
+	ForfeitGoalsFor     int64
+	ForfeitGoalsAgainst int64
+	// ForfeitPointsDeduction hükmen kaybeden takımdan ayrıca düşülen puandır
+	ForfeitPointsDeduction int64
+	// Tiebreakers puanca eşit takımlara sırayla uygulanır
+	Tiebreakers []Tiebreaker
+	// LotSeed kura sonucunu lig için sabit tutar, tablo her yeniden kurulduğunda aynı kura çıkar
+	LotSeed int64
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		PointsPerWin:        3,
+		PointsPerDraw:       1,
+		PointsPerLoss:       0,
+		ForfeitGoalsFor:     3,
+		ForfeitGoalsAgainst: 0,
+		Tiebreakers:         []Tiebreaker{TiebreakerGoalDifference, TiebreakerGoalsScored},
+	}
+}
+
+func (t Tiebreaker) IsValid() bool {
+	switch t {
+	case TiebreakerGoalDifference, TiebreakerGoalsScored, TiebreakerHeadToHead, TiebreakerFairPlay, TiebreakerDrawingLots:
+		return true
+	}
+	return false
+}
+
+func (r Rules) Validate() error {
+	var errs []error
+	if r.PointsPerWin < r.PointsPerDraw || r.PointsPerDraw < r.PointsPerLoss {
+		errs = append(errs, errors.New("galibiyet puanı beraberlikten, beraberlik puanı mağlubiyetten az olamaz"))
+	}
+	if r.ForfeitGoalsFor < 0 || r.ForfeitGoalsAgainst < 0 || r.ForfeitGoalsFor <= r.ForfeitGoalsAgainst {
+		errs = append(errs, errors.New("hükmen skor kazanan lehine olmalı"))
+	}
+	if r.ForfeitPointsDeduction < 0 {
+		errs = append(errs, errors.New("hükmen puan silme negatif olamaz"))
+	}
+
+	seen := make(map[Tiebreaker]bool, len(r.Tiebreakers))
+	for _, t := range r.Tiebreakers {
+		if !t.IsValid() {
+			errs = append(errs, fmt.Errorf("bilinmeyen eşitlik kuralı: %s", t))
+			continue
+		}
+		if seen[t] {
+			errs = append(errs, fmt.Errorf("eşitlik kuralı birden fazla kez verilmiş: %s", t))
+		}
+		seen[t] = true
+	}
+
+	return errors.Join(errs...)
+}
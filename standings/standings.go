@@ -0,0 +1,159 @@
+package standings
+
+import (
+	"sort"
+	"time"
+)
+
+// FormLength tabloda gösterilen son maç sayısıdır
+const FormLength = 5
+
+const (
+	FormWin  = "W"
+	FormDraw = "D"
+	FormLoss = "L"
+)
+
+// Result puan durumuna sayılan tamamlanmış bir maçın skorudur
+type Result struct {
+	HomeTeamID uint
+	AwayTeamID uint
+	HomeScore  int64
+	AwayScore  int64
+	PlayedAt   time.Time
+	// ForfeitedBy hükmen kaybeden takımdır, 0 ise maç normal sonuçlanmıştır.
+	// Hükmen maçlarda skor yerine kuralların hükmen skoru kullanılır.
+	ForfeitedBy uint
+	// Walkover rakip gelmediği için verilen maçtır, hükmen skor yazılır ama kaybedenden puan silinmez
+	Walkover bool
+}
+
+// Row bir takımın ligdeki hesaplanmış satırıdır
+type Row struct {
+	TeamID       uint
+	Played       int64
+	Won          int64
+	Drawn        int64
+	Lost         int64
+	GoalsFor     int64
+	GoalsAgainst int64
+	Points       int64
+	Rank         int64
+	// Form en eski sonuçtan en yeniye son FormLength maçı tutar, örn. "WWDLW"
+	Form string
+	// DecidedBy takım bir üstündeki takımla puanca eşitse aralarındaki sırayı belirleyen kuraldır
+	DecidedBy Tiebreaker
+}
+
+func (r Row) GoalDifference() int64 {
+	return r.GoalsFor - r.GoalsAgainst
+}
+
+func (r *Row) record(scored, conceded, points int64, outcome string) {
+	r.Played++
+	r.GoalsFor += scored
+	r.GoalsAgainst += conceded
+	r.Points += points
+
+	switch outcome {
+	case FormWin:
+		r.Won++
+	case FormLoss:
+		r.Lost++
+	default:
+		r.Drawn++
+	}
+
+	r.Form += outcome
+	if len(r.Form) > FormLength {
+		r.Form = r.Form[len(r.Form)-FormLength:]
+	}
+}
+
+// Compute puan durumunu sıfırdan maç sonuçlarından hesaplar. Aynı sonuçlar her zaman aynı tabloyu verir,
+// bu yüzden maç eklemek, düzenlemek veya silmek sonrası tabloyu baştan kurmak güvenlidir.
+// teamIDs ligde kayıtlı takımlardır, tablo yalnızca bu takımlardan oluşur. Ligden çıkarılan bir takımın
+// maçları iki taraf için de sayılmaz.
+// fairPlay takım başına disiplin puanıdır (az olan önde), bilinmiyorsa nil verilebilir.
+func Compute(teamIDs []uint, results []Result, rules Rules, fairPlay map[uint]int64) []Row {
+	rows := make(map[uint]*Row, len(teamIDs))
+	for _, teamID := range teamIDs {
+		rows[teamID] = &Row{TeamID: teamID}
+	}
+
+	// Form doğru sırada oluşsun diye sonuçlar oynanma zamanına göre işlenir
+	ordered := make([]Result, 0, len(results))
+	for _, result := range results {
+		if rows[result.HomeTeamID] != nil && rows[result.AwayTeamID] != nil {
+			ordered = append(ordered, result)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].PlayedAt.Before(ordered[j].PlayedAt)
+	})
+
+	for _, result := range ordered {
+		applyResult(rows[result.HomeTeamID], rows[result.AwayTeamID], result, rules)
+	}
+
+	table := make([]Row, 0, len(rows))
+	for _, r := range rows {
+		table = append(table, *r)
+	}
+
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].Points != table[j].Points {
+			return table[i].Points > table[j].Points
+		}
+		return table[i].TeamID < table[j].TeamID
+	})
+
+	// Puanca eşit takım grupları kuralların sırasıyla ayrıştırılır
+	r := ranker{rules: rules, results: ordered, fairPlay: fairPlay}
+	for start := 0; start < len(table); {
+		end := start + 1
+		for end < len(table) && table[end].Points == table[start].Points {
+			end++
+		}
+		r.order(table[start:end], rules.Tiebreakers)
+		start = end
+	}
+
+	for i := range table {
+		table[i].Rank = int64(i + 1)
+	}
+
+	return table
+}
+
+// applyResult tek bir maçın iki takıma etkisini işler
+func applyResult(home, away *Row, result Result, rules Rules) {
+	forfeitLoss := rules.PointsPerLoss - rules.ForfeitPointsDeduction
+	if result.Walkover {
+		forfeitLoss = rules.PointsPerLoss
+	}
+
+	switch result.ForfeitedBy {
+	case result.HomeTeamID:
+		home.record(rules.ForfeitGoalsAgainst, rules.ForfeitGoalsFor, forfeitLoss, FormLoss)
+		away.record(rules.ForfeitGoalsFor, rules.ForfeitGoalsAgainst, rules.PointsPerWin, FormWin)
+		return
+	case result.AwayTeamID:
+		home.record(rules.ForfeitGoalsFor, rules.ForfeitGoalsAgainst, rules.PointsPerWin, FormWin)
+		away.record(rules.ForfeitGoalsAgainst, rules.ForfeitGoalsFor, forfeitLoss, FormLoss)
+		return
+	}
+
+	homeScore, awayScore := result.HomeScore, result.AwayScore
+	switch {
+	case homeScore > awayScore:
+		home.record(homeScore, awayScore, rules.PointsPerWin, FormWin)
+		away.record(awayScore, homeScore, rules.PointsPerLoss, FormLoss)
+	case homeScore < awayScore:
+		home.record(homeScore, awayScore, rules.PointsPerLoss, FormLoss)
+		away.record(awayScore, homeScore, rules.PointsPerWin, FormWin)
+	default:
+		home.record(homeScore, awayScore, rules.PointsPerDraw, FormDraw)
+		away.record(awayScore, homeScore, rules.PointsPerDraw, FormDraw)
+	}
+}
@@ -0,0 +1,245 @@
+package standings
+
+import (
+	"testing"
+	"time"
+)
+
+var day0 = time.Date(2026, time.March, 1, 19, 0, 0, 0, time.UTC)
+
+// match sırayla oynanan maçları kurar, her maç bir öncekinden bir gün sonra oynanır
+func match(n int, home, away uint, homeScore, awayScore int64) Result {
+	return Result{
+		HomeTeamID: home,
+		AwayTeamID: away,
+		HomeScore:  homeScore,
+		AwayScore:  awayScore,
+		PlayedAt:   day0.AddDate(0, 0, n),
+	}
+}
+
+func rulesWith(tiebreakers ...Tiebreaker) Rules {
+	rules := DefaultRules()
+	rules.Tiebreakers = tiebreakers
+	rules.LotSeed = 42
+	return rules
+}
+
+type wantRow struct {
+	TeamID    uint
+	Points    int64
+	DecidedBy Tiebreaker
+}
+
+func TestComputeTiebreakOrder(t *testing.T) {
+	// 1 ile 2 eşit puanda, 2'nin averajı daha iyi ama aralarındaki maçı 1 kazandı
+	headToHead := []Result{
+		match(1, 1, 2, 1, 0),
+		match(2, 1, 3, 0, 0),
+		match(3, 2, 4, 5, 0),
+		match(4, 2, 3, 0, 0),
+	}
+	// 1 ile 2 puan, averaj ve atılan golde eşit
+	level := []Result{
+		match(1, 1, 3, 2, 1),
+		match(2, 2, 3, 2, 1),
+	}
+
+	tests := []struct {
+		name     string
+		rules    Rules
+		results  []Result
+		fairPlay map[uint]int64
+		want     []wantRow
+	}{
+		{
+			name:    "puan eşitliği bozmadan önce gelir",
+			rules:   rulesWith(TiebreakerGoalsScored),
+			results: []Result{match(1, 1, 2, 1, 0), match(2, 3, 4, 5, 5)},
+			want: []wantRow{
+				{TeamID: 1, Points: 3},
+				{TeamID: 3, Points: 1},
+				{TeamID: 4, Points: 1, DecidedBy: TiebreakerUnresolved},
+				{TeamID: 2, Points: 0},
+			},
+		},
+		{
+			name:    "averaj",
+			rules:   rulesWith(TiebreakerGoalDifference, TiebreakerGoalsScored),
+			results: headToHead,
+			want: []wantRow{
+				{TeamID: 2, Points: 4},
+				{TeamID: 1, Points: 4, DecidedBy: TiebreakerGoalDifference},
+				{TeamID: 3, Points: 2},
+				{TeamID: 4, Points: 0},
+			},
+		},
+		{
+			name:    "averaj eşitse atılan gol",
+			rules:   rulesWith(TiebreakerGoalDifference, TiebreakerGoalsScored),
+			results: []Result{match(1, 1, 3, 3, 2), match(2, 2, 3, 1, 0)},
+			want: []wantRow{
+				{TeamID: 1, Points: 3},
+				{TeamID: 2, Points: 3, DecidedBy: TiebreakerGoalsScored},
+				{TeamID: 3, Points: 0},
+			},
+		},
+		{
+			name:    "ikili averaj genel averajdan önce",
+			rules:   rulesWith(TiebreakerHeadToHead, TiebreakerGoalDifference),
+			results: headToHead,
+			want: []wantRow{
+				{TeamID: 1, Points: 4},
+				{TeamID: 2, Points: 4, DecidedBy: TiebreakerHeadToHead},
+				{TeamID: 3, Points: 2},
+				{TeamID: 4, Points: 0},
+			},
+		},
+		{
+			name:  "ikili averajda eşit kalan takımlar sonraki kurala geçer",
+			rules: rulesWith(TiebreakerHeadToHead, TiebreakerGoalDifference),
+			results: []Result{
+				match(1, 1, 2, 1, 1),
+				match(2, 1, 3, 1, 0),
+				match(3, 2, 3, 4, 0),
+			},
+			want: []wantRow{
+				{TeamID: 2, Points: 4},
+				{TeamID: 1, Points: 4, DecidedBy: TiebreakerGoalDifference},
+				{TeamID: 3, Points: 0},
+			},
+		},
+		{
+			name:     "fair play disiplin puanı az olanı öne alır",
+			rules:    rulesWith(TiebreakerGoalDifference, TiebreakerGoalsScored, TiebreakerFairPlay),
+			results:  level,
+			fairPlay: map[uint]int64{1: 5, 2: 2},
+			want: []wantRow{
+				{TeamID: 2, Points: 3},
+				{TeamID: 1, Points: 3, DecidedBy: TiebreakerFairPlay},
+				{TeamID: 3, Points: 0},
+			},
+		},
+		{
+			name:     "fair play eşitse takım id'sine göre sıralanır",
+			rules:    rulesWith(TiebreakerGoalDifference, TiebreakerFairPlay),
+			results:  level,
+			fairPlay: map[uint]int64{1: 3, 2: 3},
+			want: []wantRow{
+				{TeamID: 1, Points: 3},
+				{TeamID: 2, Points: 3, DecidedBy: TiebreakerUnresolved},
+				{TeamID: 3, Points: 0},
+			},
+		},
+		{
+			name:    "kural yoksa eşitlik çözülmez",
+			rules:   rulesWith(),
+			results: level,
+			want: []wantRow{
+				{TeamID: 1, Points: 3},
+				{TeamID: 2, Points: 3, DecidedBy: TiebreakerUnresolved},
+				{TeamID: 3, Points: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teamIDs := make([]uint, len(tt.want))
+			for i, want := range tt.want {
+				teamIDs[i] = want.TeamID
+			}
+			table := Compute(teamIDs, tt.results, tt.rules, tt.fairPlay)
+			if len(table) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d", len(table), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := table[i]
+				if got.TeamID != want.TeamID || got.Points != want.Points || got.DecidedBy != want.DecidedBy {
+					t.Errorf("row %d: got team %d (%d pts, %q), want team %d (%d pts, %q)",
+						i+1, got.TeamID, got.Points, got.DecidedBy, want.TeamID, want.Points, want.DecidedBy)
+				}
+				if got.Rank != int64(i+1) {
+					t.Errorf("row %d: got rank %d", i+1, got.Rank)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeForfeits(t *testing.T) {
+	rules := rulesWith(TiebreakerGoalDifference)
+	rules.ForfeitPointsDeduction = 1
+
+	forfeit := match(1, 1, 2, 0, 0)
+	forfeit.ForfeitedBy = 2
+	walkover := match(2, 3, 4, 0, 0)
+	walkover.ForfeitedBy = 4
+	walkover.Walkover = true
+
+	table := Compute([]uint{1, 2, 3, 4}, []Result{forfeit, walkover}, rules, nil)
+	got := make(map[uint]Row, len(table))
+	for _, row := range table {
+		got[row.TeamID] = row
+	}
+
+	tests := []struct {
+		teamID       uint
+		points       int64
+		goalsFor     int64
+		goalsAgainst int64
+		form         string
+	}{
+		{teamID: 1, points: 3, goalsFor: 3, goalsAgainst: 0, form: FormWin},
+		{teamID: 2, points: -1, goalsFor: 0, goalsAgainst: 3, form: FormLoss},
+		{teamID: 3, points: 3, goalsFor: 3, goalsAgainst: 0, form: FormWin},
+		// Rakip gelmediği maçta hükmen skor yazılır ama puan silinmez
+		{teamID: 4, points: 0, goalsFor: 0, goalsAgainst: 3, form: FormLoss},
+	}
+	for _, tt := range tests {
+		row := got[tt.teamID]
+		if row.Points != tt.points || row.GoalsFor != tt.goalsFor || row.GoalsAgainst != tt.goalsAgainst || row.Form != tt.form {
+			t.Errorf("team %d: got %d pts %d-%d %q, want %d pts %d-%d %q",
+				tt.teamID, row.Points, row.GoalsFor, row.GoalsAgainst, row.Form, tt.points, tt.goalsFor, tt.goalsAgainst, tt.form)
+		}
+	}
+}
+
+func TestComputeSkipsUnregisteredTeams(t *testing.T) {
+	// 3 ligden çıkarılmıştır, 1'e karşı aldığı galibiyet de 2'ye karşı yenilgisi de sayılmaz
+	results := []Result{
+		match(1, 1, 2, 1, 1),
+		match(2, 3, 1, 2, 0),
+		match(3, 2, 3, 4, 0),
+	}
+
+	table := Compute([]uint{1, 2}, results, rulesWith(TiebreakerGoalDifference), nil)
+	if len(table) != 2 {
+		t.Fatalf("got %d rows, want 2", len(table))
+	}
+	for _, row := range table {
+		if row.TeamID == 3 {
+			t.Fatal("unregistered team is in the table")
+		}
+		if row.Played != 1 || row.Points != 1 || row.GoalDifference() != 0 {
+			t.Errorf("team %d: got %d played, %d pts, %d goal difference; want 1, 1, 0",
+				row.TeamID, row.Played, row.Points, row.GoalDifference())
+		}
+	}
+}
+
+func TestComputeDrawingLotsIsStable(t *testing.T) {
+	rules := rulesWith(TiebreakerDrawingLots)
+	results := []Result{match(1, 1, 2, 0, 0)}
+
+	first := Compute([]uint{1, 2}, results, rules, nil)
+	if first[1].DecidedBy != TiebreakerDrawingLots {
+		t.Fatalf("got decided by %q, want %q", first[1].DecidedBy, TiebreakerDrawingLots)
+	}
+	for i := 0; i < 5; i++ {
+		again := Compute([]uint{2, 1}, results, rules, nil)
+		if again[0].TeamID != first[0].TeamID {
+			t.Fatalf("lot changed between rebuilds: got team %d first, want %d", again[0].TeamID, first[0].TeamID)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package standings
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+)
+
+// ranker puanca eşit takımları ligin kurallarına göre sıralar
+type ranker struct {
+	rules    Rules
+	results  []Result
+	fairPlay map[uint]int64
+}
+
+// order grubu ilk kurala göre sıralar, o kurala göre hâlâ eşit kalan alt gruplara kalan kuralları uygular.
+// Bir kuralın ayırdığı her takımın DecidedBy alanına o kural yazılır.
+func (r ranker) order(group []Row, tiebreakers []Tiebreaker) {
+	if len(group) < 2 {
+		return
+	}
+
+	if len(tiebreakers) == 0 {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].TeamID < group[j].TeamID
+		})
+		for i := 1; i < len(group); i++ {
+			group[i].DecidedBy = TiebreakerUnresolved
+		}
+		return
+	}
+
+	tiebreaker := tiebreakers[0]
+	keys := r.keys(group, tiebreaker)
+
+	sort.SliceStable(group, func(i, j int) bool {
+		return compareKeys(keys[group[i].TeamID], keys[group[j].TeamID]) > 0
+	})
+
+	for start := 0; start < len(group); {
+		end := start + 1
+		for end < len(group) && compareKeys(keys[group[end].TeamID], keys[group[start].TeamID]) == 0 {
+			end++
+		}
+		if start > 0 {
+			group[start].DecidedBy = tiebreaker
+		}
+		r.order(group[start:end], tiebreakers[1:])
+		start = end
+	}
+}
+
+// keys her takım için kuralın karşılaştırma anahtarını üretir, büyük olan öndedir
+func (r ranker) keys(group []Row, tiebreaker Tiebreaker) map[uint][]int64 {
+	keys := make(map[uint][]int64, len(group))
+
+	switch tiebreaker {
+	case TiebreakerGoalDifference:
+		for _, row := range group {
+			keys[row.TeamID] = []int64{row.GoalDifference()}
+		}
+	case TiebreakerGoalsScored:
+		for _, row := range group {
+			keys[row.TeamID] = []int64{row.GoalsFor}
+		}
+	case TiebreakerHeadToHead:
+		// Sadece eşit takımlar arasındaki maçlardan mini bir tablo kurulur: puan, averaj, atılan gol
+		teams := make(map[uint]bool, len(group))
+		teamIDs := make([]uint, 0, len(group))
+		for _, row := range group {
+			teams[row.TeamID] = true
+			teamIDs = append(teamIDs, row.TeamID)
+		}
+
+		mini := make(map[uint]*Row, len(group))
+		for _, teamID := range teamIDs {
+			mini[teamID] = &Row{TeamID: teamID}
+		}
+		for _, result := range r.results {
+			if teams[result.HomeTeamID] && teams[result.AwayTeamID] {
+				applyResult(mini[result.HomeTeamID], mini[result.AwayTeamID], result, r.rules)
+			}
+		}
+
+		for _, teamID := range teamIDs {
+			row := mini[teamID]
+			keys[teamID] = []int64{row.Points, row.GoalDifference(), row.GoalsFor}
+		}
+	case TiebreakerFairPlay:
+		// Disiplin puanı az olan öne geçer
+		for _, row := range group {
+			keys[row.TeamID] = []int64{-r.fairPlay[row.TeamID]}
+		}
+	case TiebreakerDrawingLots:
+		for _, row := range group {
+			keys[row.TeamID] = []int64{lot(r.rules.LotSeed, row.TeamID)}
+		}
+	default:
+		for _, row := range group {
+			keys[row.TeamID] = nil
+		}
+	}
+
+	return keys
+}
+
+// lot kurayı lig ve takım için sabit bir sayıya indirger, böylece tablo yeniden kurulunca kura değişmez
+func lot(seed int64, teamID uint) int64 {
+	h := fnv.New64a()
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], uint64(seed))
+	binary.BigEndian.PutUint64(buf[8:], uint64(teamID))
+	_, _ = h.Write(buf[:])
+	return int64(h.Sum64() >> 1)
+}
+
+func compareKeys(a, b []int64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] > b[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseWeekday "monday", "pazartesi" gibi gün adlarını çözer
+func ParseWeekday(s string) (time.Weekday, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "sunday", "pazar":
+		return time.Sunday, nil
+	case "monday", "pazartesi":
+		return time.Monday, nil
+	case "tuesday", "salı", "sali":
+		return time.Tuesday, nil
+	case "wednesday", "çarşamba", "carsamba":
+		return time.Wednesday, nil
+	case "thursday", "perşembe", "persembe":
+		return time.Thursday, nil
+	case "friday", "cuma":
+		return time.Friday, nil
+	case "saturday", "cumartesi":
+		return time.Saturday, nil
+	}
+	return 0, fmt.Errorf("geçersiz gün: %s", s)
+}
+
+// ParseClock "19:30" biçimindeki saati gün başından itibaren süreye çevirir
+func ParseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("geçersiz saat: %s", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}